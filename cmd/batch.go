@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/detector"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+)
+
+var (
+	BatchInputDirectoryPath  string
+	BatchOutputDirectoryPath string
+	BatchOptions             options.BatchOptions = options.GetDefaultBatchOptions()
+)
+
+func init() {
+	batchCmd.Flags().StringVarP(&BatchInputDirectoryPath, "input-directory-path", "i", "", "Input directory containing the videos to perform the lightning detection.")
+	batchCmd.MarkFlagRequired("input-directory-path")
+
+	batchCmd.Flags().StringVarP(&BatchOutputDirectoryPath, "output-directory-path", "o", "", "Output directory path for the batch summary and the export artifacts of each video stored in separate subdirectories.")
+	batchCmd.MarkFlagRequired("output-directory-path")
+
+	batchCmd.Flags().StringSliceVar(
+		&BatchOptions.IncludePatterns,
+		"include",
+		BatchOptions.IncludePatterns,
+		"Glob patterns of the video files to be processed. The patterns are matched case-insensitively against the file name and the path relative to the input directory.")
+
+	batchCmd.Flags().StringSliceVar(
+		&BatchOptions.ExcludePatterns,
+		"exclude",
+		BatchOptions.ExcludePatterns,
+		"Glob patterns of the video files to be excluded from processing. The patterns are matched case-insensitively against the file name and the path relative to the input directory.")
+
+	batchCmd.Flags().BoolVar(
+		&BatchOptions.Recursive,
+		"recursive",
+		BatchOptions.Recursive,
+		"Search for the video files in the subdirectories of the input directory.")
+
+	batchCmd.Flags().IntVar(
+		&BatchOptions.Concurrency,
+		"concurrency",
+		BatchOptions.Concurrency,
+		"The number of videos processed concurrently. The progress of concurrently processed videos is not printed.")
+
+	registerDetectorOptionsFlags(batchCmd)
+
+	registerConfigFlags(batchCmd)
+
+	rootCmd.AddCommand(batchCmd)
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Perform the analysis, detection and export stage on all videos of a directory.",
+	Long:  "Perform the analysis, detection and export stage on all videos of a directory. The results of each video are stored in a separate subdirectory and the batch summary is stored in the output directory. Videos already processed with the same options are skipped.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer.Configure(printer.PrinterConfig{
+			UseColor:     true,
+			LogLevel:     LogLevel,
+			OutStream:    os.Stdout,
+			ParsableMode: false,
+		})
+
+		configValues, err := loadConfigValues(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := DetectorOptions.ApplyConfig(configValues); err != nil {
+			return fmt.Errorf("cmd: failed to apply the configuration: %w", err)
+		}
+
+		DetectorOptions.CacheDirectoryPath = resolveAnalysisCacheDirectoryPath(DetectorOptions.CacheDirectoryPath, true)
+
+		detectorInstance, err := detector.CreateBatchDetector(printer.Instance(), DetectorOptions, BatchOptions)
+		if err != nil {
+			return fmt.Errorf("cmd: failed to create the batch detector instance: %w", err)
+		}
+
+		if err := detectorInstance.Run(BatchInputDirectoryPath, BatchOutputDirectoryPath, cmd.Context()); err != nil {
+			return fmt.Errorf("cmd: batch detector run failed: %w", err)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/analyzer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+)
+
+var (
+	CacheDirectoryPath string
+	CachePruneAge      time.Duration = 30 * 24 * time.Hour
+)
+
+func init() {
+	cacheCmd.PersistentFlags().StringVar(
+		&CacheDirectoryPath,
+		"cache-directory",
+		CacheDirectoryPath,
+		fmt.Sprintf("The directory of the cache store. Defaults to the %s environment variable or the user cache directory.", analyzer.CacheStoreDirectoryEnv))
+
+	cachePruneCmd.Flags().DurationVar(
+		&CachePruneAge,
+		"older-than",
+		CachePruneAge,
+		"Remove the cache entries which were not used for the given duration. Example: 72h")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheInspectCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+
+	rootCmd.AddCommand(cacheCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache store of the pre-analyzed frames.",
+	Long:  "Manage the cache store shared between the output directories, which holds the pre-analyzed frames and the analysis checkpoints of many videos created using different options.",
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all entries of the cache store.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := createCacheStore()
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.List()
+		if err != nil {
+			return fmt.Errorf("cmd: failed to list the cache store entries: %w", err)
+		}
+
+		printCacheStoreEntries(printer.Instance(), store, entries, false)
+		return nil
+	},
+}
+
+var cacheInspectCmd = &cobra.Command{
+	Use:   "inspect <video>",
+	Short: "List the entries of the cache store created for the video.",
+	Long:  "List the entries of the cache store created for the video including the analysed frames. The video is matched by the content fingerprint, which is covering the size, modification time and sampled content of the video, therefore the entries are found for the moved or renamed video, but not for the modified video or the copy which is not preserving the modification time.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := createCacheStore()
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.Inspect(args[0])
+		if err != nil {
+			return fmt.Errorf("cmd: failed to inspect the cache store entries of the video: %w", err)
+		}
+
+		printCacheStoreEntries(printer.Instance(), store, entries, true)
+		return nil
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the unused entries of the cache store.",
+	Long:  "Remove the entries of the cache store which were not used for the given duration and the entries with a corrupted header.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if CachePruneAge < 0 {
+			return fmt.Errorf("cmd: the prune duration can not be negative")
+		}
+
+		store, err := createCacheStore()
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.Prune(time.Now().Add(-CachePruneAge))
+		if err != nil {
+			return fmt.Errorf("cmd: failed to prune the cache store: %w", err)
+		}
+
+		var size int64
+		for _, entry := range entries {
+			size += entry.Size
+		}
+
+		printer.Instance().Info("Removed %d cache store entries (%s).", len(entries), formatCacheEntrySize(size))
+		return nil
+	},
+}
+
+// Helper function used to resolve the cache store directory of the cache commands. The default directory is used if the
+// directory is not specified with the flag or the configuration.
+func resolveCacheDirectoryPath(directoryPath string) (string, error) {
+	if len(directoryPath) != 0 {
+		return directoryPath, nil
+	}
+
+	directoryPath, err := analyzer.GetDefaultCacheStoreDirectory()
+	if err != nil {
+		return "", fmt.Errorf("cmd: failed to resolve the cache store directory: %w", err)
+	}
+
+	return directoryPath, nil
+}
+
+// Helper function used to resolve the cache store directory of the analysing commands, only if the cache is used. The cache store
+// is not used if the default directory can not be resolved (Example: no home directory), therefore the cache is stored in the output
+// directory instead.
+func resolveAnalysisCacheDirectoryPath(directoryPath string, cacheUsed bool) string {
+	if len(directoryPath) != 0 || !cacheUsed {
+		return directoryPath
+	}
+
+	directoryPath, err := analyzer.GetDefaultCacheStoreDirectory()
+	if err != nil {
+		printer.Instance().Warning("Failed to resolve the default cache store directory. The cache is stored in the output directory.")
+		printer.Instance().Debug("Cache store directory resolving failure: %s", err)
+		return ""
+	}
+
+	return directoryPath
+}
+
+func createCacheStore() (analyzer.CacheStore, error) {
+	printer.Configure(printer.PrinterConfig{
+		UseColor:     true,
+		LogLevel:     LogLevel,
+		OutStream:    os.Stdout,
+		ParsableMode: false,
+	})
+
+	directoryPath, err := resolveCacheDirectoryPath(CacheDirectoryPath)
+	if err != nil {
+		return analyzer.CacheStore{}, err
+	}
+
+	return analyzer.NewCacheStore(directoryPath), nil
+}
+
+// Helper function used to print the cache store entries as a table. The frames column is printed only for the inspected entries,
+// because the frames of the listed entries are not decoded.
+func printCacheStoreEntries(p printer.Printer, store analyzer.CacheStore, entries []analyzer.CacheStoreEntry, frames bool) {
+	p.Info("Cache store: %s", store.DirectoryPath)
+
+	if len(entries) == 0 {
+		p.Info("No cache store entries found.")
+		return
+	}
+
+	table := make([][]string, 0, len(entries)+1)
+	table = append(table, formatCacheEntryRow(frames, "Video fingerprint", "Options checksum", "Type", "Frames", "Size", "Last used"))
+
+	var size int64
+	for _, entry := range entries {
+		size += entry.Size
+
+		if entry.Corrupted {
+			table = append(table, formatCacheEntryRow(frames, "N/A", "N/A", "Corrupted", "N/A", formatCacheEntrySize(entry.Size), entry.LastUsed.Format(time.DateTime)))
+			continue
+		}
+
+		entryType, entryFrames := "Cache", strconv.Itoa(entry.Frames)
+		if entry.Checkpoint {
+			entryType = "Checkpoint"
+		}
+
+		if entry.Frames != 0 {
+			entryFrames = fmt.Sprintf("%d (%d-%d)", entry.Frames, entry.FirstFrame, entry.LastFrame)
+		}
+
+		table = append(table, formatCacheEntryRow(frames,
+			shortenCacheDigest(entry.Header.Fingerprint),
+			shortenCacheDigest(entry.Header.Checksum),
+			entryType,
+			entryFrames,
+			formatCacheEntrySize(entry.Size),
+			entry.LastUsed.Format(time.DateTime)))
+	}
+
+	p.Table(table)
+	p.Info("Total %d entries (%s).", len(entries), formatCacheEntrySize(size))
+}
+
+func formatCacheEntryRow(frames bool, fingerprint, checksum, entryType, entryFrames, size, lastUsed string) []string {
+	if !frames {
+		return []string{fingerprint, checksum, entryType, size, lastUsed}
+	}
+
+	return []string{fingerprint, checksum, entryType, entryFrames, size, lastUsed}
+}
+
+func shortenCacheDigest(digest string) string {
+	if len(digest) == 0 {
+		return "N/A"
+	}
+
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+
+	return digest
+}
+
+func formatCacheEntrySize(size int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(size)/(1024*1024))
+}
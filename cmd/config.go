@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+)
+
+var (
+	ConfigFilePath string
+	ConfigProfile  string
+)
+
+// Register the configuration file flags shared by the commands performing the detection.
+func registerConfigFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&ConfigFilePath,
+		"config",
+		ConfigFilePath,
+		"The YAML or JSON configuration file with the detector options. The keys are matching the flag names and the explicitly specified flags overwrite the file values.")
+
+	profileValues := strings.Join(options.GetBuiltInProfileNames(), ", ")
+	cmd.PersistentFlags().StringVar(
+		&ConfigProfile,
+		"profile",
+		ConfigProfile,
+		fmt.Sprintf("The named profile of detector options applied over the configuration file values. The profiles can be defined in the configuration file. Built-in: [ %s ]", profileValues))
+}
+
+// Load the configuration values of the selected profile without the keys overwritten by the explicitly specified flags.
+func loadConfigValues(cmd *cobra.Command) (map[string]any, error) {
+	config := options.Config{}
+	if len(ConfigFilePath) != 0 {
+		var err error
+		if config, err = options.LoadConfig(ConfigFilePath); err != nil {
+			return nil, fmt.Errorf("cmd: failed to load the configuration file: %w", err)
+		}
+	}
+
+	values, err := config.GetValues(ConfigProfile)
+	if err != nil {
+		return nil, fmt.Errorf("cmd: failed to select the configuration profile: %w", err)
+	}
+
+	for key := range values {
+		if cmd.Flags().Changed(key) {
+			delete(values, key)
+		}
+	}
+
+	return values, nil
+}
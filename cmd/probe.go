@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/analyzer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
+)
+
+var (
+	ProbeInputVideoPath   string
+	ProbeVideoStreamIndex int     = -1
+	ProbeScalingFactor    float64 = options.GetDefaultDetectorOptions().FrameScalingFactor
+	ProbeWorkers          int     = 1
+	ProbeJsonOutput       bool    = false
+)
+
+func init() {
+	probeCmd.Flags().StringVarP(&ProbeInputVideoPath, "input-video-path", "i", "", "Input video file or video stream url to be probed.")
+	probeCmd.MarkFlagRequired("input-video-path")
+
+	probeCmd.Flags().IntVar(
+		&ProbeVideoStreamIndex,
+		"video-stream-index",
+		ProbeVideoStreamIndex,
+		"The 0-based index of the video stream to be probed in files containing multiple video streams.")
+
+	probeCmd.Flags().Float64VarP(
+		&ProbeScalingFactor,
+		"scaling-factor", "s",
+		ProbeScalingFactor,
+		"Scaling factor for the frame size used to estimate the analysis processing time and memory usage.")
+
+	probeCmd.Flags().IntVar(
+		&ProbeWorkers,
+		"workers",
+		ProbeWorkers,
+		"Number of analysis workers used to estimate the analysis processing time and memory usage.")
+
+	probeCmd.Flags().BoolVar(
+		&ProbeJsonOutput,
+		"json",
+		ProbeJsonOutput,
+		"Print the video information as JSON instead of tables.")
+
+	rootCmd.AddCommand(probeCmd)
+}
+
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Print the information about the video.",
+	Long:  "Print the information about the video including the list of available video streams, which can be selected for the analysis via the video stream index, and the estimated analysis processing time and memory usage. The processing time is a rough guess based on an assumed reference throughput.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer.Configure(printer.PrinterConfig{
+			UseColor:     true,
+			LogLevel:     LogLevel,
+			OutStream:    os.Stdout,
+			ParsableMode: false,
+		})
+
+		if ProbeScalingFactor <= 0 || ProbeScalingFactor > 1 {
+			return fmt.Errorf("cmd: the scaling factor must be between zero and one")
+		}
+
+		if ProbeWorkers < 1 {
+			return fmt.Errorf("cmd: the workers count must be greater than zero")
+		}
+
+		streams, err := video.ProbeVideoStreams(ProbeInputVideoPath)
+		if err != nil {
+			return fmt.Errorf("cmd: failed to probe the video streams: %w", err)
+		}
+
+		// NOTE: The video file containing multiple video streams can not be probed without the stream index, therefore the default
+		// stream is probed and the stream index must be specified for the analysis.
+		streamIndex := ProbeVideoStreamIndex
+		if streamIndex < 0 && len(streams) > 1 && !utils.IsValidUrl(ProbeInputVideoPath) {
+			streamIndex = video.DefaultVideoStreamIndex(streams)
+
+			if !ProbeJsonOutput {
+				printer.Instance().Warning("The video contains %d video streams. The details of the default stream %d are printed. Use the video stream index to select the stream for the analysis.", len(streams), streamIndex)
+			}
+		}
+
+		probe, err := video.ProbeVideo(ProbeInputVideoPath, streamIndex)
+		if err != nil {
+			return fmt.Errorf("cmd: failed to probe the video: %w", err)
+		}
+
+		report := createProbeReport(probe, utils.MaxInt(0, streamIndex), streams, analyzer.EstimateAnalysis(probe.Width, probe.Height, probe.Frames, ProbeScalingFactor, ProbeWorkers))
+
+		if ProbeJsonOutput {
+			reportMarshal, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("cmd: failed to marshal the video probe report: %w", err)
+			}
+
+			printer.Instance().WriteRaw("%s\n", string(reportMarshal))
+			return nil
+		}
+
+		printProbeReportTables(printer.Instance(), report)
+		return nil
+	},
+}
+
+type probeReport struct {
+	StreamIndex       int                 `json:"stream-index"`
+	Width             int                 `json:"width"`
+	Height            int                 `json:"height"`
+	Fps               float64             `json:"fps"`
+	AverageFps        float64             `json:"average-fps"`
+	VariableFrameRate bool                `json:"variable-frame-rate"`
+	Duration          float64             `json:"duration"`
+	Frames            int                 `json:"frames"`
+	Rotation          int                 `json:"rotation"`
+	HorizontalFlip    bool                `json:"horizontal-flip"`
+	CreationTime      string              `json:"creation-time"`
+	Streams           []probeReportStream `json:"streams"`
+	Estimate          probeReportEstimate `json:"estimate"`
+}
+
+type probeReportStream struct {
+	Index           int     `json:"index"`
+	Codec           string  `json:"codec"`
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	Fps             float64 `json:"fps"`
+	Frames          int     `json:"frames"`
+	Default         bool    `json:"default"`
+	AttachedPicture bool    `json:"attached-picture"`
+}
+
+type probeReportEstimate struct {
+	ScalingFactor float64 `json:"scaling-factor"`
+	Workers       int     `json:"workers"`
+	OutputWidth   int     `json:"output-width"`
+	OutputHeight  int     `json:"output-height"`
+	Duration      float64 `json:"duration"`
+	MemoryBytes   uint64  `json:"memory-bytes"`
+}
+
+func createProbeReport(probe video.VideoProbe, streamIndex int, streams []video.VideoStreamProbe, estimate analyzer.AnalysisEstimate) probeReport {
+	report := probeReport{
+		StreamIndex:       streamIndex,
+		Width:             probe.Width,
+		Height:            probe.Height,
+		Fps:               probe.Fps,
+		AverageFps:        probe.AvgFps,
+		VariableFrameRate: probe.VariableFrameRate,
+		Duration:          probe.Duration,
+		Frames:            probe.Frames,
+		Rotation:          probe.Orientation.Rotation,
+		HorizontalFlip:    probe.Orientation.HorizontalFlip,
+		Streams:           make([]probeReportStream, 0, len(streams)),
+		Estimate: probeReportEstimate{
+			ScalingFactor: ProbeScalingFactor,
+			Workers:       ProbeWorkers,
+			OutputWidth:   estimate.OutputWidth,
+			OutputHeight:  estimate.OutputHeight,
+			Duration:      estimate.Duration.Seconds(),
+			MemoryBytes:   estimate.Memory,
+		},
+	}
+
+	if !probe.CreationTime.IsZero() {
+		report.CreationTime = probe.CreationTime.Format(time.RFC3339Nano)
+	}
+
+	for _, stream := range streams {
+		report.Streams = append(report.Streams, probeReportStream{
+			Index:           stream.Index,
+			Codec:           stream.Codec,
+			Width:           stream.Width,
+			Height:          stream.Height,
+			Fps:             stream.Fps,
+			Frames:          stream.Frames,
+			Default:         stream.Default,
+			AttachedPicture: stream.AttachedPicture,
+		})
+	}
+
+	return report
+}
+
+func printProbeReportTables(p printer.Printer, report probeReport) {
+	creationTime := report.CreationTime
+	if len(creationTime) == 0 {
+		creationTime = "N/A"
+	}
+
+	p.Table([][]string{
+		{"Stream index", strconv.Itoa(report.StreamIndex)},
+		{"Width", strconv.Itoa(report.Width)},
+		{"Height", strconv.Itoa(report.Height)},
+		{"FPS", strconv.FormatFloat(report.Fps, 'f', -1, 64)},
+		{"Average FPS", strconv.FormatFloat(report.AverageFps, 'f', -1, 64)},
+		{"Variable frame rate", strconv.FormatBool(report.VariableFrameRate)},
+		{"Duration", fmt.Sprintf("%ss", strconv.FormatFloat(report.Duration, 'f', -1, 64))},
+		{"Frames", strconv.Itoa(report.Frames)},
+		{"Rotation", fmt.Sprintf("%d°", report.Rotation)},
+		{"Horizontal flip", strconv.FormatBool(report.HorizontalFlip)},
+		{"Creation time", creationTime},
+	})
+
+	streamsTable := make([][]string, 0, len(report.Streams)+1)
+	streamsTable = append(streamsTable, []string{"Stream index", "Codec", "Width", "Height", "FPS", "Frames", "Default", "Attached picture"})
+
+	for _, stream := range report.Streams {
+		streamsTable = append(streamsTable, []string{
+			strconv.Itoa(stream.Index),
+			stream.Codec,
+			strconv.Itoa(stream.Width),
+			strconv.Itoa(stream.Height),
+			strconv.FormatFloat(stream.Fps, 'f', -1, 64),
+			strconv.Itoa(stream.Frames),
+			strconv.FormatBool(stream.Default),
+			strconv.FormatBool(stream.AttachedPicture),
+		})
+	}
+
+	p.Table(streamsTable)
+
+	p.Table([][]string{
+		{"Scaling factor", strconv.FormatFloat(report.Estimate.ScalingFactor, 'f', -1, 64)},
+		{"Workers", strconv.Itoa(report.Estimate.Workers)},
+		{"Analysis frame size", fmt.Sprintf("%dx%d", report.Estimate.OutputWidth, report.Estimate.OutputHeight)},
+		{"Estimated analysis time (rough guess)", (time.Duration(report.Estimate.Duration * float64(time.Second))).Round(time.Second).String()},
+		{"Estimated analysis memory", fmt.Sprintf("%.1f MiB", float64(report.Estimate.MemoryBytes)/(1024*1024))},
+	})
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandsShouldPrintTheHelp(t *testing.T) {
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetErr(nil)
+
+	for _, command := range rootCmd.Commands() {
+		assert.NotPanics(t, func() {
+			rootCmd.SetArgs([]string{command.Name(), "--help"})
+			assert.Nil(t, rootCmd.Execute(), "command %s", command.Name())
+		}, "command %s", command.Name())
+	}
+
+	assert.NotPanics(t, func() {
+		rootCmd.SetArgs([]string{"cache", "prune", "--help"})
+		assert.Nil(t, rootCmd.Execute())
+	})
+}
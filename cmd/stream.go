@@ -38,12 +38,96 @@ func init() {
 		DetectorOptions.BrightnessDetectionThreshold,
 		"The threshold used to determine the brightness of the frame. See the documentation for more information on detection threshold values.")
 
+	detectionStrategyValues := strings.Join(options.GetDetectionStrategyValues(), ", ")
+	streamCmd.PersistentFlags().Var(
+		&StreamDetectorOptions.DetectionStrategy,
+		"detection-strategy",
+		fmt.Sprintf("The strategy used to classify the frame weights. The z-score strategy is using the z-score thresholds instead of the absolute ones. Values: [ %s ]", detectionStrategyValues))
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.ColorDifferenceDetectionZScore,
+		"color-difference-z-score",
+		StreamDetectorOptions.ColorDifferenceDetectionZScore,
+		"The number of moving standard deviations above the moving mean required to classify the color difference. Used by the z-score detection strategy.")
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.BinaryThresholdDifferenceDetectionZScore,
+		"binary-threshold-difference-z-score",
+		StreamDetectorOptions.BinaryThresholdDifferenceDetectionZScore,
+		"The number of moving standard deviations above the moving mean required to classify the binary threshold difference. Used by the z-score detection strategy.")
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.BrightnessDetectionZScore,
+		"brightness-z-score",
+		StreamDetectorOptions.BrightnessDetectionZScore,
+		"The number of moving standard deviations above the moving mean required to classify the brightness. Used by the z-score detection strategy.")
+
+	votingRuleValues := strings.Join(options.GetVotingRuleValues(), ", ")
+	streamCmd.PersistentFlags().Var(
+		&StreamDetectorOptions.VotingRule,
+		"voting-rule",
+		fmt.Sprintf("The rule used to combine the classified weights into a frame detection. Values: [ %s ]", votingRuleValues))
+
+	streamCmd.PersistentFlags().IntVar(
+		&StreamDetectorOptions.VotingRuleK,
+		"voting-rule-k",
+		StreamDetectorOptions.VotingRuleK,
+		"The minimal number of classified weights required for a frame detection. Used by the k-of-n voting rule.")
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.ColorDifferenceVotingWeight,
+		"color-difference-voting-weight",
+		StreamDetectorOptions.ColorDifferenceVotingWeight,
+		"The score added when the color difference is classified. Used by the weighted voting rule.")
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.BinaryThresholdDifferenceVotingWeight,
+		"binary-threshold-difference-voting-weight",
+		StreamDetectorOptions.BinaryThresholdDifferenceVotingWeight,
+		"The score added when the binary threshold difference is classified. Used by the weighted voting rule.")
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.BrightnessVotingWeight,
+		"brightness-voting-weight",
+		StreamDetectorOptions.BrightnessVotingWeight,
+		"The score added when the brightness is classified. Used by the weighted voting rule.")
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.VotingScoreThreshold,
+		"voting-score-threshold",
+		StreamDetectorOptions.VotingScoreThreshold,
+		"The minimal sum of the classified weights scores required for a frame detection. Used by the weighted voting rule.")
+
+	streamCmd.PersistentFlags().IntVar(
+		&StreamDetectorOptions.ClassificationWindowSize,
+		"classification-window-size",
+		StreamDetectorOptions.ClassificationWindowSize,
+		"The number of neighbouring frames considered together during the classification. Larger windows are useful for high frame rate recordings.")
+
+	streamCmd.PersistentFlags().IntVar(
+		&StreamDetectorOptions.ClassificationMaxGap,
+		"classification-max-gap",
+		StreamDetectorOptions.ClassificationMaxGap,
+		"The maximal number of not classified frames placed between two classified frames that will be bridged. Must not exceed the classification window size minus two.")
+
 	streamCmd.PersistentFlags().Int32VarP(
 		&StreamDetectorOptions.MovingMeanResolution,
 		"moving-mean-resolution", "m",
 		DetectorOptions.MovingMeanResolution,
 		"Resolution of the moving mean used when determining the statistics of the analysed frames. Has a direct impact on the accuracy of detection.")
 
+	statisticsBackendValues := strings.Join(options.GetStatisticsBackendValues(), ", ")
+	streamCmd.PersistentFlags().Var(
+		&StreamDetectorOptions.StatisticsBackend,
+		"statistics-backend",
+		fmt.Sprintf("The backend used to calculate the moving statistics of the analysed frames. The exponential backend is using the exponentially weighted moving mean and variance. Values: [ %s ]", statisticsBackendValues))
+
+	streamCmd.PersistentFlags().Float64Var(
+		&StreamDetectorOptions.ExponentialHalfLife,
+		"exponential-half-life",
+		StreamDetectorOptions.ExponentialHalfLife,
+		"The half-life in seconds after which the weight of a frame in the exponential statistics is halved. Used by the exponential statistics backend.")
+
 	streamCmd.PersistentFlags().Float64VarP(
 		&StreamDetectorOptions.FrameScalingFactor,
 		"scaling-factor", "s",
@@ -92,6 +176,8 @@ func init() {
 		StreamDetectorOptions.DiagnosticMode,
 		"Enable diagnostic mode. Internal statistics will be printed out on stepping out of the detection stage.")
 
+	registerConfigFlags(streamCmd)
+
 	rootCmd.AddCommand(streamCmd)
 }
 
@@ -107,6 +193,15 @@ var streamCmd = &cobra.Command{
 			ParsableMode: true,
 		})
 
+		configValues, err := loadConfigValues(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := StreamDetectorOptions.ApplyConfig(configValues); err != nil {
+			return fmt.Errorf("cmd: failed to apply the configuration: %w", err)
+		}
+
 		detectorInstance, err := detector.CreateStreamDetector(printer.Instance(), StreamDetectorOptions)
 		if err != nil {
 			return fmt.Errorf("cmd: failed to create the stream detector instance: %w", err)
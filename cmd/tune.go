@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/detector"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+)
+
+var (
+	TuneInputVideoPath      string
+	TuneOutputDirectoryPath string
+	TuneDetectionStrategies []string
+	TuneOptions             options.TuneOptions = options.GetDefaultTuneOptions()
+)
+
+func init() {
+	tuneCmd.Flags().StringVarP(&TuneInputVideoPath, "input-video-path", "i", "", "Input video to perform the thresholds tuning. Can be omitted if the output directory or the cache store contains the analysis cache created with matching options and the moving window statistics backend is used.")
+
+	tuneCmd.Flags().StringVarP(&TuneOutputDirectoryPath, "output-directory-path", "o", "", "Output directory path for the analysis cache and the tuned configuration profile.")
+	tuneCmd.MarkFlagRequired("output-directory-path")
+
+	tuneCmd.Flags().StringVar(
+		&TuneOptions.GroundTruthExpression,
+		"ground-truth-expression",
+		TuneOptions.GroundTruthExpression,
+		"An expression indicating the frames (numbered from one) that actually contain the lightning. Example: 4,5,10-12")
+	tuneCmd.MarkFlagRequired("ground-truth-expression")
+
+	tuneMetricValues := strings.Join(options.GetTuneMetricValues(), ", ")
+	tuneCmd.Flags().Var(
+		&TuneOptions.Metric,
+		"metric",
+		fmt.Sprintf("The metric of the detection compared to the ground-truth frames which is maximized. Values: [ %s ]", tuneMetricValues))
+
+	tuneCmd.Flags().Float64Var(
+		&TuneOptions.MinPrecision,
+		"min-precision",
+		TuneOptions.MinPrecision,
+		"The minimal precision of the detection. Used by the recall at precision metric.")
+
+	tuneCmd.Flags().IntVar(
+		&TuneOptions.ThresholdSteps,
+		"threshold-steps",
+		TuneOptions.ThresholdSteps,
+		"The number of threshold candidates of each frame metric. The candidates are evenly spaced percentiles of the frame metric deviations.")
+
+	tuneCmd.Flags().IntSliceVar(
+		&TuneOptions.MovingMeanResolutions,
+		"moving-mean-resolutions",
+		TuneOptions.MovingMeanResolutions,
+		"The moving mean resolutions to be searched. Only the moving mean resolution option is used by the exponential statistics backend.")
+
+	detectionStrategyValues := strings.Join(options.GetDetectionStrategyValues(), ", ")
+	tuneCmd.Flags().StringSliceVar(
+		&TuneDetectionStrategies,
+		"detection-strategies",
+		options.GetDetectionStrategyValues(),
+		fmt.Sprintf("The detection strategies to be searched. Values: [ %s ]", detectionStrategyValues))
+
+	tuneCmd.Flags().StringVar(
+		&TuneOptions.ProfileName,
+		"profile-name",
+		TuneOptions.ProfileName,
+		"The name of the configuration profile containing the tuned options.")
+
+	registerDetectorOptionsFlags(tuneCmd)
+
+	registerConfigFlags(tuneCmd)
+
+	rootCmd.AddCommand(tuneCmd)
+}
+
+var tuneCmd = &cobra.Command{
+	Use:   "tune",
+	Short: "Search the detector options maximizing the detection quality compared to the ground-truth frames.",
+	Long:  "Search the thresholds, moving mean resolution and detection strategy maximizing the selected metric of the detection compared to the ground-truth frames. The video analysis is cached and each trial only reruns the detection stage. The best options are stored as a configuration profile in the output directory.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer.Configure(printer.PrinterConfig{
+			UseColor:     true,
+			LogLevel:     LogLevel,
+			OutStream:    os.Stdout,
+			ParsableMode: false,
+		})
+
+		configValues, err := loadConfigValues(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := DetectorOptions.ApplyConfig(configValues); err != nil {
+			return fmt.Errorf("cmd: failed to apply the configuration: %w", err)
+		}
+
+		DetectorOptions.CacheDirectoryPath = resolveAnalysisCacheDirectoryPath(DetectorOptions.CacheDirectoryPath, true)
+
+		TuneOptions.DetectionStrategies = make([]options.DetectionStrategy, len(TuneDetectionStrategies))
+		for index, name := range TuneDetectionStrategies {
+			if err := TuneOptions.DetectionStrategies[index].Set(name); err != nil {
+				return fmt.Errorf("cmd: invalid detection strategy %q: %w", name, err)
+			}
+		}
+
+		tunerInstance, err := detector.CreateTuner(printer.Instance(), DetectorOptions, TuneOptions)
+		if err != nil {
+			return fmt.Errorf("cmd: failed to create the tuner instance: %w", err)
+		}
+
+		if _, err := tunerInstance.Run(TuneInputVideoPath, TuneOutputDirectoryPath, cmd.Context()); err != nil {
+			return fmt.Errorf("cmd: tuner run failed: %w", err)
+		}
+
+		return nil
+	},
+}
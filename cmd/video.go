@@ -3,13 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/Krzysztofz01/video-lightning-detector/internal/analyzer"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/detector"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
 )
 
 var (
@@ -19,115 +22,350 @@ var (
 )
 
 func init() {
-	videoCmd.Flags().StringVarP(&InputVideoPath, "input-video-path", "i", "", "Input video to perform the lightning detection.")
+	videoCmd.Flags().StringVarP(&InputVideoPath, "input-video-path", "i", "", "Input video to perform the lightning detection. An image sequence can be specified as a directory of images, a glob pattern or a numbered pattern. Example: frames/*.jpg, frames/img-%04d.png")
 	videoCmd.MarkPersistentFlagRequired("input-video-path")
 
 	videoCmd.PersistentFlags().StringVarP(&OutputDirectoryPath, "output-directory-path", "o", "", "Output directory path for export artifacts such as frames and reports in selected formats.")
 	videoCmd.MarkPersistentFlagRequired("output-directory-path")
 
-	videoCmd.PersistentFlags().BoolVarP(
+	registerDetectorOptionsFlags(videoCmd)
+
+	registerConfigFlags(videoCmd)
+
+	rootCmd.AddCommand(videoCmd)
+}
+
+// Register the detector options flags shared by the commands performing the detection on video files.
+func registerDetectorOptionsFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(
 		&DetectorOptions.AutoThresholds,
 		"auto-thresholds", "a",
 		DetectorOptions.AutoThresholds,
 		"Automatic determination of thresholds after video analysis. The specified thresholds will overwrite those determined.")
 
-	videoCmd.PersistentFlags().Float64VarP(
+	autoThresholdStrategyValues := strings.Join(options.GetAutoThresholdStrategyValues(), ", ")
+	cmd.PersistentFlags().Var(
+		&DetectorOptions.AutoThresholdStrategy,
+		"auto-threshold-strategy",
+		fmt.Sprintf("The strategy used to determine the thresholds from the positive deviations of the frame metrics from the moving mean. Values: [ %s ]", autoThresholdStrategyValues))
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.AutoThresholdPercentile,
+		"auto-threshold-percentile",
+		DetectorOptions.AutoThresholdPercentile,
+		"The percentile of the positive deviations used as the threshold. Used by the percentile auto threshold strategy.")
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.AutoThresholdOtsuBins,
+		"auto-threshold-otsu-bins",
+		DetectorOptions.AutoThresholdOtsuBins,
+		"The number of histogram bins of the positive deviations split into two classes. Used by the otsu auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BrightnessAutoThresholdMeanCoefficient,
+		"brightness-auto-threshold-mean-coefficient",
+		DetectorOptions.BrightnessAutoThresholdMeanCoefficient,
+		"The coefficient of the mean of positive brightness deviations. Used by the mean-of-deviations auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BrightnessAutoThresholdStdDevCoefficient,
+		"brightness-auto-threshold-stddev-coefficient",
+		DetectorOptions.BrightnessAutoThresholdStdDevCoefficient,
+		"The coefficient of the mean of brightness moving standard deviations. Used by the mean-of-deviations auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.ColorDifferenceAutoThresholdMeanCoefficient,
+		"color-difference-auto-threshold-mean-coefficient",
+		DetectorOptions.ColorDifferenceAutoThresholdMeanCoefficient,
+		"The coefficient of the mean of positive color difference deviations. Used by the mean-of-deviations auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.ColorDifferenceAutoThresholdStdDevCoefficient,
+		"color-difference-auto-threshold-stddev-coefficient",
+		DetectorOptions.ColorDifferenceAutoThresholdStdDevCoefficient,
+		"The coefficient of the mean of color difference moving standard deviations. Used by the mean-of-deviations auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BinaryThresholdDifferenceAutoThresholdMeanCoefficient,
+		"binary-threshold-difference-auto-threshold-mean-coefficient",
+		DetectorOptions.BinaryThresholdDifferenceAutoThresholdMeanCoefficient,
+		"The coefficient of the mean of positive binary threshold difference deviations. Used by the mean-of-deviations auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BinaryThresholdDifferenceAutoThresholdStdDevCoefficient,
+		"binary-threshold-difference-auto-threshold-stddev-coefficient",
+		DetectorOptions.BinaryThresholdDifferenceAutoThresholdStdDevCoefficient,
+		"The coefficient of the mean of binary threshold difference moving standard deviations. Used by the mean-of-deviations auto threshold strategy.")
+
+	cmd.PersistentFlags().Float64VarP(
 		&DetectorOptions.ColorDifferenceDetectionThreshold,
 		"color-difference-threshold", "c",
 		DetectorOptions.ColorDifferenceDetectionThreshold,
 		"The threshold used to determine the difference between two neighbouring frames on the color basis. See the documentation for more information on detection threshold values.")
 
-	videoCmd.PersistentFlags().Float64VarP(
+	cmd.PersistentFlags().Float64VarP(
 		&DetectorOptions.BinaryThresholdDifferenceDetectionThreshold,
 		"binary-threshold-difference-threshold", "t",
 		DetectorOptions.BinaryThresholdDifferenceDetectionThreshold,
 		"The threshold used to determine the difference between two neighbouring frames after the binary thresholding segmentation process. See the documentation for more information on detection threshold values.")
 
-	videoCmd.PersistentFlags().Float64VarP(
+	cmd.PersistentFlags().Float64VarP(
 		&DetectorOptions.BrightnessDetectionThreshold,
 		"brightness-threshold", "b",
 		DetectorOptions.BrightnessDetectionThreshold,
 		"The threshold used to determine the brightness of the frame. See the documentation for more information on detection threshold values.")
 
-	videoCmd.PersistentFlags().Int32VarP(
+	detectionStrategyValues := strings.Join(options.GetDetectionStrategyValues(), ", ")
+	cmd.PersistentFlags().Var(
+		&DetectorOptions.DetectionStrategy,
+		"detection-strategy",
+		fmt.Sprintf("The strategy used to classify the frame weights. The z-score strategy is using the z-score thresholds instead of the absolute ones. Values: [ %s ]", detectionStrategyValues))
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.ColorDifferenceDetectionZScore,
+		"color-difference-z-score",
+		DetectorOptions.ColorDifferenceDetectionZScore,
+		"The number of moving standard deviations above the moving mean required to classify the color difference. Used by the z-score detection strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BinaryThresholdDifferenceDetectionZScore,
+		"binary-threshold-difference-z-score",
+		DetectorOptions.BinaryThresholdDifferenceDetectionZScore,
+		"The number of moving standard deviations above the moving mean required to classify the binary threshold difference. Used by the z-score detection strategy.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BrightnessDetectionZScore,
+		"brightness-z-score",
+		DetectorOptions.BrightnessDetectionZScore,
+		"The number of moving standard deviations above the moving mean required to classify the brightness. Used by the z-score detection strategy.")
+
+	votingRuleValues := strings.Join(options.GetVotingRuleValues(), ", ")
+	cmd.PersistentFlags().Var(
+		&DetectorOptions.VotingRule,
+		"voting-rule",
+		fmt.Sprintf("The rule used to combine the classified weights into a frame detection. Values: [ %s ]", votingRuleValues))
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.VotingRuleK,
+		"voting-rule-k",
+		DetectorOptions.VotingRuleK,
+		"The minimal number of classified weights required for a frame detection. Used by the k-of-n voting rule.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.ColorDifferenceVotingWeight,
+		"color-difference-voting-weight",
+		DetectorOptions.ColorDifferenceVotingWeight,
+		"The score added when the color difference is classified. Used by the weighted voting rule.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BinaryThresholdDifferenceVotingWeight,
+		"binary-threshold-difference-voting-weight",
+		DetectorOptions.BinaryThresholdDifferenceVotingWeight,
+		"The score added when the binary threshold difference is classified. Used by the weighted voting rule.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.BrightnessVotingWeight,
+		"brightness-voting-weight",
+		DetectorOptions.BrightnessVotingWeight,
+		"The score added when the brightness is classified. Used by the weighted voting rule.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.VotingScoreThreshold,
+		"voting-score-threshold",
+		DetectorOptions.VotingScoreThreshold,
+		"The minimal sum of the classified weights scores required for a frame detection. Used by the weighted voting rule.")
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.ClassificationWindowSize,
+		"classification-window-size",
+		DetectorOptions.ClassificationWindowSize,
+		"The number of neighbouring frames considered together during the classification. Larger windows are useful for high frame rate recordings.")
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.ClassificationMaxGap,
+		"classification-max-gap",
+		DetectorOptions.ClassificationMaxGap,
+		"The maximal number of not classified frames placed between two classified frames that will be bridged. Must not exceed the classification window size minus two.")
+
+	cmd.PersistentFlags().Int32VarP(
 		&DetectorOptions.MovingMeanResolution,
 		"moving-mean-resolution", "m",
 		DetectorOptions.MovingMeanResolution,
 		"Resolution of the moving mean used when determining the statistics of the analysed frames. Has a direct impact on the accuracy of detection.")
 
-	videoCmd.PersistentFlags().BoolVarP(
+	statisticsBackendValues := strings.Join(options.GetStatisticsBackendValues(), ", ")
+	cmd.PersistentFlags().Var(
+		&DetectorOptions.StatisticsBackend,
+		"statistics-backend",
+		fmt.Sprintf("The backend used to calculate the moving statistics of the analysed frames. The exponential backend is using the exponentially weighted moving mean and variance. Values: [ %s ]", statisticsBackendValues))
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.ExponentialHalfLife,
+		"exponential-half-life",
+		DetectorOptions.ExponentialHalfLife,
+		"The half-life in seconds after which the weight of a frame in the exponential statistics is halved. Used by the exponential statistics backend.")
+
+	cmd.PersistentFlags().BoolVarP(
 		&DetectorOptions.SkipFramesExport,
 		"skip-frames-export", "f",
 		DetectorOptions.SkipFramesExport,
 		"Skipping the step in which positively classified frames are exported to image files.")
 
-	videoCmd.PersistentFlags().BoolVarP(
+	cmd.PersistentFlags().BoolVarP(
 		&DetectorOptions.ExportCsvReport,
 		"export-csv-report", "e",
 		DetectorOptions.ExportCsvReport,
 		"Export of reports in CSV format.")
 
-	videoCmd.PersistentFlags().BoolVarP(
+	cmd.PersistentFlags().BoolVarP(
 		&DetectorOptions.ExportJsonReport,
 		"export-json-report", "j",
 		DetectorOptions.ExportJsonReport,
 		"Export of reports in JSON format.")
 
-	videoCmd.PersistentFlags().BoolVarP(
+	cmd.PersistentFlags().BoolVarP(
 		&DetectorOptions.ExportChartReport,
 		"export-chart-report", "r",
 		DetectorOptions.ExportChartReport,
 		"Export of frame statistics as a chart in HTML format.")
 
-	videoCmd.PersistentFlags().Float64VarP(
+	cmd.PersistentFlags().Float64VarP(
 		&DetectorOptions.FrameScalingFactor,
 		"scaling-factor", "s",
 		DetectorOptions.FrameScalingFactor,
 		"Scaling factor for the frame size of the recording. Has a direct impact on the performance, quality and processing time of recordings.")
 
 	denoiseValues := strings.Join(options.GetDenoiseAlgorithmValues(), ", ")
-	videoCmd.PersistentFlags().VarP(
+	cmd.PersistentFlags().VarP(
 		&DetectorOptions.Denoise,
 		"denoise", "n",
 		fmt.Sprintf("The use of de-noising in the form of low-pass filters. Impact on the quality of weighting determination. Values: [ %s ]", denoiseValues))
 
-	videoCmd.PersistentFlags().BoolVar(
+	cmd.PersistentFlags().BoolVar(
 		&DetectorOptions.ExportConfusionMatrix,
 		"export-confusion-matrix",
 		DetectorOptions.ExportConfusionMatrix,
 		"Value indicating if the frames detection classification confusion matrix should be rendered.")
 
-	videoCmd.PersistentFlags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&DetectorOptions.ConfusionMatrixActualDetectionsExpression,
 		"confusion-matrix-actual-detections-expression",
 		DetectorOptions.ConfusionMatrixActualDetectionsExpression,
 		"Expression indicating the range of frames that should be used as actual classification. Example: 4,5,8-10,12,14")
 
-	videoCmd.PersistentFlags().BoolVarP(
+	cmd.PersistentFlags().BoolVarP(
 		&DetectorOptions.ImportPreanalyzed,
 		"import-preanalyzed", "p",
 		DetectorOptions.ImportPreanalyzed,
 		"Use the cached data associated with the video analysis or save it in case the video has not already been analysed.")
 
-	videoCmd.PersistentFlags().BoolVar(
+	cmd.PersistentFlags().BoolVar(
 		&DetectorOptions.StrictExplicitThreshold,
 		"strict-explicit-threshold",
 		DetectorOptions.StrictExplicitThreshold,
 		"Omit strict validation of detection threshold ranges.")
 
-	videoCmd.PersistentFlags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&DetectorOptions.DetectionBoundsExpression,
 		"detection-bounds-expression",
 		DetectorOptions.DetectionBoundsExpression,
 		"An expression indicating consecutively the coordinates of the upper left point, width and height of the cutout (bounding box) of the recording to be processed.  Example: 0:0:100:200")
 
+	cmd.PersistentFlags().StringVar(
+		&DetectorOptions.AnalysisStartExpression,
+		"start",
+		DetectorOptions.AnalysisStartExpression,
+		"Position of the video from which the analysis should start, given as a 1-based frame number or a timestamp. Example: 1500, 90.5s, 00:42:10")
+
+	cmd.PersistentFlags().StringVar(
+		&DetectorOptions.AnalysisEndExpression,
+		"end",
+		DetectorOptions.AnalysisEndExpression,
+		"Position of the video at which the analysis should end (inclusive), given as a 1-based frame number or a timestamp. Example: 1500, 90.5s, 00:42:10")
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.AnalysisWorkers,
+		"workers",
+		DetectorOptions.AnalysisWorkers,
+		"Number of video segments decoded concurrently by separate video decoding processes during the analysis. The results are identical to the sequential analysis.")
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.CheckpointInterval,
+		"checkpoint-interval",
+		DetectorOptions.CheckpointInterval,
+		"The number of analysed frames after which the analysis progress is stored, so an interrupted analysis is resumed on the next run. Used only with the pre-analyzed frames import. The value of zero disables the checkpoints.")
+
+	cmd.PersistentFlags().BoolVar(
+		&DetectorOptions.AllowIncompleteAnalysis,
+		"allow-incomplete-analysis",
+		DetectorOptions.AllowIncompleteAnalysis,
+		"Perform the detection and export stages on the frames analysed before the analysis was interrupted.")
+
+	cmd.PersistentFlags().StringVar(
+		&DetectorOptions.CacheDirectoryPath,
+		"cache-directory",
+		DetectorOptions.CacheDirectoryPath,
+		fmt.Sprintf("The directory of the cache store shared between the output directories holding the pre-analyzed frames and the analysis checkpoints. Defaults to the %s environment variable or the user cache directory.", analyzer.CacheStoreDirectoryEnv))
+
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.VideoStreamIndex,
+		"video-stream-index",
+		DetectorOptions.VideoStreamIndex,
+		"The 0-based index of the video stream to be analyzed in files containing multiple video streams. A negative value requires the file to contain a single video stream. Use the probe command to list the available streams.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.ImageSequenceFps,
+		"image-sequence-fps",
+		DetectorOptions.ImageSequenceFps,
+		fmt.Sprintf("The frame rate of the image sequence input used for the frames timing. The value of zero indicates the default frame rate of %s frames per second.", strconv.FormatFloat(video.DefaultImageSequenceFps, 'f', -1, 64)))
+
 	scalingValues := strings.Join(options.GetScaleAlgorithmValues(), ", ")
-	videoCmd.PersistentFlags().Var(
+	cmd.PersistentFlags().Var(
 		&DetectorOptions.ScaleAlgorithm,
 		"scaling-algorithm",
 		fmt.Sprintf("Sampling interpolation algorithm to be used when scaling the video during analysis. Values: [ %s ]", scalingValues))
 
-	rootCmd.AddCommand(videoCmd)
+	cmd.PersistentFlags().IntVar(
+		&DetectorOptions.StrikeEventGapTolerance,
+		"strike-event-gap-tolerance",
+		DetectorOptions.StrikeEventGapTolerance,
+		"The maximum amount of not detected frames between two detected frames for them to be grouped into the same strike event.")
+
+	cmd.PersistentFlags().BoolVar(
+		&DetectorOptions.ExportStrikeEventClips,
+		"export-strike-event-clips",
+		DetectorOptions.ExportStrikeEventClips,
+		"Export of video clips cut around each strike event. The clips are cut without re-encoding using the source video container.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.StrikeEventClipPreRoll,
+		"strike-event-clip-pre-roll",
+		DetectorOptions.StrikeEventClipPreRoll,
+		"The amount of seconds of the video to be included in the strike event clip before the event start.")
+
+	cmd.PersistentFlags().Float64Var(
+		&DetectorOptions.StrikeEventClipPostRoll,
+		"strike-event-clip-post-roll",
+		DetectorOptions.StrikeEventClipPostRoll,
+		"The amount of seconds of the video to be included in the strike event clip after the event end.")
+
+	cmd.PersistentFlags().BoolVar(
+		&DetectorOptions.ExportCompositeImage,
+		"export-composite-image",
+		DetectorOptions.ExportCompositeImage,
+		"Export of a single full resolution image created by stacking all positively classified frames.")
+
+	cmd.PersistentFlags().BoolVar(
+		&DetectorOptions.ExportStrikeEventCompositeImages,
+		"export-strike-event-composite-images",
+		DetectorOptions.ExportStrikeEventCompositeImages,
+		"Export of a full resolution image for each strike event created by stacking the frames of the given event.")
+
+	compositeBlendModeValues := strings.Join(options.GetCompositeBlendModeValues(), ", ")
+	cmd.PersistentFlags().Var(
+		&DetectorOptions.CompositeBlendMode,
+		"composite-blend-mode",
+		fmt.Sprintf("The blending mode used to stack the frames into composite images. Values: [ %s ]", compositeBlendModeValues))
 }
 
 var videoCmd = &cobra.Command{
@@ -142,12 +380,23 @@ var videoCmd = &cobra.Command{
 			ParsableMode: false,
 		})
 
+		configValues, err := loadConfigValues(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := DetectorOptions.ApplyConfig(configValues); err != nil {
+			return fmt.Errorf("cmd: failed to apply the configuration: %w", err)
+		}
+
+		DetectorOptions.CacheDirectoryPath = resolveAnalysisCacheDirectoryPath(DetectorOptions.CacheDirectoryPath, DetectorOptions.ImportPreanalyzed)
+
 		detectorInstance, err := detector.CreateDetector(printer.Instance(), DetectorOptions)
 		if err != nil {
 			return fmt.Errorf("cmd: failed to create the detector instance: %w", err)
 		}
 
-		if err := detectorInstance.Run(InputVideoPath, OutputDirectoryPath, cmd.Context()); err != nil {
+		if _, err := detectorInstance.Run(InputVideoPath, OutputDirectoryPath, cmd.Context()); err != nil {
 			return fmt.Errorf("cmd: detector run failed: %w", err)
 		}
 
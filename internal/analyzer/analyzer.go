@@ -2,14 +2,17 @@ package analyzer
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"image"
 	"io"
+	"math"
 	"os"
 	"path"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
-	"github.com/Krzysztofz01/video-lightning-detector/internal/denoise"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
@@ -19,11 +22,36 @@ import (
 
 const frameCollectionCacheFilename string = ".vld-cache"
 
+const frameCollectionCheckpointFilename string = ".vld-checkpoint"
+
+// Error returned when the video analysis was interrupted and the detection on the partially analysed frames was not requested.
+var ErrAnalysisIncomplete = errors.New("analyzer: the video analysis is incomplete")
+
+// The tolerance used while converting the timestamps to frame indexes to avoid floating point rounding issues.
+const positionRoundingEpsilon float64 = 1e-9
+
 type Analyzer interface {
 	// Perform the analysis of the video frames. Depending on the options, this function will perform
 	// the analysis or import the result of the previous analysis with a fallback to a standard analysis.
 	// Depending on the options the frames analysis will be exported for future usage.
 	GetFrames(ctx context.Context) (frame.FrameCollection, error)
+
+	// Access the metadata of the analyzed video required by the following stages. The metadata is accessed by the analysis,
+	// therefore the video is opened only if the frames were imported from the cache.
+	GetVideoMetadata() (VideoMetadata, error)
+}
+
+// The metadata of the analyzed video.
+type VideoMetadata struct {
+	FramesPerSecond float64
+	CreationTime    time.Time
+}
+
+func newVideoMetadata(v video.Video) *VideoMetadata {
+	return &VideoMetadata{
+		FramesPerSecond: v.FramesPerSecond(),
+		CreationTime:    v.CreationTime(),
+	}
 }
 
 type analyzer struct {
@@ -31,143 +59,685 @@ type analyzer struct {
 	OutputDirPath  string
 	Options        options.DetectorOptions
 	Printer        printer.Printer
+	VideoOpener    func(path string, streamIndex int, sequenceFps float64) (video.Video, error)
+	Fingerprint    *videoFingerprint
+	Metadata       *VideoMetadata
+}
+
+func (analyzer *analyzer) GetVideoMetadata() (VideoMetadata, error) {
+	if analyzer.Metadata == nil {
+		video, err := analyzer.VideoOpener(analyzer.InputVideoPath, analyzer.Options.VideoStreamIndex, analyzer.Options.ImageSequenceFps)
+		if err != nil {
+			return VideoMetadata{}, fmt.Errorf("analyzer: failed to open the video file for the metadata access: %w", err)
+		}
+
+		defer video.Close()
+
+		analyzer.Metadata = newVideoMetadata(video)
+	}
+
+	return *analyzer.Metadata, nil
 }
 
 func (analyzer *analyzer) GetFrames(ctx context.Context) (frame.FrameCollection, error) {
 	var (
-		frames      frame.FrameCollection
-		preanalyzed bool
-		err         error
+		frames frame.FrameCollection
+		status CacheStatus
+		err    error
 	)
 
 	if analyzer.Options.ImportPreanalyzed {
 		preanalizedImportTime := time.Now()
 
-		frames, preanalyzed, err = analyzer.ImportPreanalyzedFrames()
+		frames, status, err = analyzer.ImportPreanalyzedFrames()
 		if err != nil {
 			return nil, fmt.Errorf("analyzer: failed to import the preanalyzed frames: %w", err)
 		}
 
-		if preanalyzed {
+		switch status {
+		case CacheValid:
 			analyzer.Printer.Info("Importing the pre-analyzed frames data. Stage took: %s", time.Since(preanalizedImportTime))
 			return frames, nil
-		}
+		case CacheLegacy:
+			analyzer.Printer.Info("Importing the pre-analyzed frames data. Stage took: %s", time.Since(preanalizedImportTime))
+			analyzer.Printer.Warning("The pre-analyzed frames cache is using the legacy format which can not be verified against the video. The cache is upgraded.")
+
+			if err := analyzer.ExportPreanalyzedFrames(frames); err != nil {
+				return nil, fmt.Errorf("analyzer: preanalyzed frames export stage failed: %w", err)
+			}
 
-		analyzer.Printer.Warning("No exported pre-analzyed frames JSON file found. Fallback to frames analysis.")
+			return frames, nil
+		case CacheOptionsMismatch:
+			analyzer.Printer.Warning("The pre-analyzed frames cache was created using different analysis options. Fallback to frames analysis.")
+		case CacheVideoMismatch:
+			analyzer.Printer.Warning("The pre-analyzed frames cache was created for a different or modified video. Fallback to frames analysis.")
+		default:
+			analyzer.Printer.Warning("No exported pre-analzyed frames cache found. Fallback to frames analysis.")
+		}
 	}
 
-	if frames, err = analyzer.PerformFramesAnalysis(ctx); err != nil {
+	frames, complete, err := analyzer.PerformFramesAnalysis(ctx)
+	if err != nil {
 		return nil, fmt.Errorf("analyzer: failed to perform the frames analysis: %w", err)
 	}
 
+	if !complete {
+		if analyzer.IsCheckpointEnabled() {
+			analyzer.Printer.Info("The video analysis progress is stored and will be resumed on the next run.")
+		}
+
+		if !analyzer.Options.AllowIncompleteAnalysis {
+			return nil, ErrAnalysisIncomplete
+		}
+
+		analyzer.Printer.Warning("The video analysis is incomplete. The following stages are performed on the %d analysed frames.", frames.Count())
+		return frames, nil
+	}
+
 	if analyzer.Options.ImportPreanalyzed {
 		if err := analyzer.ExportPreanalyzedFrames(frames); err != nil {
 			return nil, fmt.Errorf("analyzer: preanalyzed frames export stage failed: %w", err)
 		}
+
+		if err := analyzer.RemoveAnalysisCheckpoint(); err != nil {
+			analyzer.Printer.Warning("Failed to remove the video analysis checkpoint.")
+			analyzer.Printer.Debug("Video analysis checkpoint removal failure: %s", err)
+		}
 	}
 
 	return frames, nil
 }
 
 // Helper function used to iterate over the video frames in order to generate a collection of frames instances containing
-// processed values about given frames and neighbouring frames relations.
-func (analyzer *analyzer) PerformFramesAnalysis(ctx context.Context) (frame.FrameCollection, error) {
+// processed values about given frames and neighbouring frames relations. The analysis is resumed from the checkpoint, if
+// available, and the boolean return value indicates if the analysis was completed without an interruption.
+func (analyzer *analyzer) PerformFramesAnalysis(ctx context.Context) (frame.FrameCollection, bool, error) {
 	videoAnalysisTime := time.Now()
 	analyzer.Printer.Debug("Starting the video analysis stage.")
 
-	video, err := video.NewVideo(analyzer.InputVideoPath)
+	video, err := analyzer.OpenVideo()
 	if err != nil {
-		return nil, fmt.Errorf("analyzer: failed to open the video file for the analysis stage: %w", err)
+		return nil, false, fmt.Errorf("analyzer: failed to open the video for the analysis stage: %w", err)
+	}
+
+	defer video.Close()
+
+	analyzer.Metadata = newVideoMetadata(video)
+
+	timestamps := analyzer.ProbeFrameTimestamps(video)
+
+	firstFrame, lastFrame, err := resolveAnalysisRange(analyzer.Options.AnalysisStartExpression, analyzer.Options.AnalysisEndExpression, video.FramesPerSecond(), timestamps)
+	if err != nil {
+		return nil, false, fmt.Errorf("analyzer: failed to resolve the analysis range: %w", err)
+	}
+
+	checkpointFrames := analyzer.ImportAnalysisCheckpoint(firstFrame)
+
+	// NOTE: The frame preceding the resumed range is decoded again and used only as the previous frame reference for the difference metrics
+	var (
+		resumeFrame int  = firstFrame + len(checkpointFrames)
+		resumed     bool = len(checkpointFrames) != 0
+		rangeFirst  int  = resumeFrame
+	)
+
+	if resumed {
+		rangeFirst -= 1
+	}
+
+	if rangeFirst != 0 || lastFrame >= 0 {
+		if err := video.SetFrameRange(rangeFirst, lastFrame); err != nil {
+			if !resumed {
+				return nil, false, fmt.Errorf("analyzer: failed to apply the analysis range to the video: %w", err)
+			}
+
+			return nil, false, fmt.Errorf("analyzer: failed to apply the resumed analysis range to the video, the checkpoint may be removed using the cache command: %w", err)
+		}
+
+		analyzer.Printer.Debug("Analysis limited to the frames index range from %d to %d.", rangeFirst, lastFrame)
+	}
+
+	if resumed {
+		analyzer.Printer.Info("Resuming the video analysis from the checkpoint at frame %d.", resumeFrame+1)
+	}
+
+	// NOTE: Due to the fact that the internal video implementation works in such a way, that the frame count is an approximation instead of an
+	// exact value, the frameCount is used as an initial capacity value for the frames collection and not the result fixed size/frames count.
+	frameCount := video.FramesCountApprox()
+	if resumed {
+		frameCount = utils.MaxInt(1, frameCount-1)
+	}
+
+	frames := frame.NewOffsetFrameCollection(len(checkpointFrames)+frameCount, firstFrame)
+	defer frames.Lock()
+
+	for _, f := range checkpointFrames {
+		if err := frames.Push(f); err != nil {
+			return nil, false, fmt.Errorf("analyzer: failed to push the checkpoint frame to the collection: %w", err)
+		}
+	}
+
+	progressStep, progressFinalize := analyzer.Printer.ProgressSteps("Video analysis stage.", frameCount)
+
+	checkpoint := newAnalysisCheckpoint(analyzer, checkpointFrames)
+
+	complete := true
+	if analyzer.Options.AnalysisWorkers > 1 && frameCount > 1 {
+		// NOTE: The segments are using separate video instances, therefore the video opened for the range resolution is not required.
+		video.Close()
+
+		complete, err = analyzer.PerformSegmentedFramesAnalysis(ctx, frames, checkpoint, resumeFrame, lastFrame, frameCount, timestamps, resumed, progressStep)
+	} else {
+		err = analyzer.ReadVideoFrames(ctx, video, resumeFrame+1, resumed, func(f *frame.Frame) error {
+			if err := frames.Push(f); err != nil {
+				return fmt.Errorf("analyzer: failed to push the frame to the collection: %w", err)
+			}
+
+			analyzer.Printer.Debug("Frame: [%d/%d]. Brightness: %f ColorDiff: %f BTDiff: %f", f.OrdinalNumber, frameCount, f.Brightness, f.ColorDifference, f.BinaryThresholdDifference)
+
+			checkpoint.Push(f)
+
+			progressStep()
+			return nil
+		})
+
+		if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			complete, err = false, nil
+		}
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	frames.Lock()
+
+	// NOTE: The checkpoint frames are matching the frames of the interrupted analysis, because the segments are pushing only the frames
+	// continuing the analysed range, which are the frames merged into the collection.
+	if !complete {
+		checkpoint.Store()
+	}
+
+	analyzer.AssignFrameTimestamps(frames, timestamps, video.FramesPerSecond())
+
+	progressFinalize()
+	analyzer.Printer.Debug("Video analysis stage finished. Stage took: %s", time.Since(videoAnalysisTime))
+	return frames, complete, nil
+}
+
+// Helper function used to probe the presentation timestamps of the video frames. The timestamps are probed only for a video with a
+// variable frame rate or if the analysis range is used. A nil slice is returned if the timestamps are not probed or the probing failed,
+// therefore the frame rate is used for the timing instead. A warning is printed if the video has a variable frame rate.
+func (analyzer *analyzer) ProbeFrameTimestamps(video video.Video) []float64 {
+	rangeUsed := len(analyzer.Options.AnalysisStartExpression) != 0 || len(analyzer.Options.AnalysisEndExpression) != 0
+	if !video.IsVariableFrameRate() && !rangeUsed {
+		return nil
+	}
+
+	timestamps, err := video.FrameTimestamps()
+	if err != nil {
+		analyzer.Printer.Warning("Failed to probe the video frames timestamps. The timestamps are estimated using the frame rate.")
+		analyzer.Printer.Debug("Frames timestamps probing failure: %s", err)
+		timestamps = nil
+	}
+
+	if video.IsVariableFrameRate() {
+		if timestamps != nil {
+			analyzer.Printer.Warning("The video has a variable frame rate. The probed frames timestamps are used instead of the frame rate.")
+		} else {
+			analyzer.Printer.Warning("The video has a variable frame rate. The frames timestamps and time positions may be inaccurate.")
+		}
+	}
+
+	return timestamps
+}
+
+// Helper function used to assign the presentation timestamps probed from the video to the frames. The timestamps of the frames
+// which are not covered by the probed timestamps are extrapolated using the frame rate.
+func (analyzer *analyzer) AssignFrameTimestamps(fc frame.FrameCollection, timestamps []float64, fps float64) {
+	for _, f := range fc.GetAll() {
+		f.Timestamp = resolveFrameTimestamp(timestamps, f.OrdinalNumber-1, fps)
+	}
+}
+
+// Helper function used to analyze the frames range concurrently using segments decoded by separate video instances. The segments
+// are merged into the collection and the boolean return value indicates if the analysis was completed without an interruption. Only
+// the frames preceding the first interrupted segment are merged. The frames of the completed segments and of the first segment which
+// is not completed are pushed to the checkpoint, because only the frames continuing the analysed range can be resumed.
+func (analyzer *analyzer) PerformSegmentedFramesAnalysis(ctx context.Context, fc frame.FrameCollection, checkpoint *analysisCheckpoint, firstFrame, lastFrame, frameCount int, timestamps []float64, resumed bool, progressStep func()) (bool, error) {
+	var (
+		segments       []analysisSegment = splitAnalysisSegments(firstFrame, lastFrame, frameCount, analyzer.Options.AnalysisWorkers, resumed)
+		segmentsMutex  sync.Mutex
+		wg             sync.WaitGroup
+		checkpointHead int
+	)
+
+	analyzer.Printer.Debug("Performing the video analysis using %d segments of %d frames.", len(segments), cap(segments[0].Frames))
+
+	for index := range segments {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+
+			segment := &segments[index]
+
+			err := analyzer.AnalyzeVideoSegment(ctx, segment, timestamps, func(f *frame.Frame) {
+				segmentsMutex.Lock()
+				defer segmentsMutex.Unlock()
+
+				segment.Frames = append(segment.Frames, f)
+				if index == checkpointHead {
+					checkpoint.Push(f)
+				}
+
+				progressStep()
+			})
+
+			segmentsMutex.Lock()
+			defer segmentsMutex.Unlock()
+
+			segment.Err = err
+			segment.Done = err == nil
+
+			// NOTE: The segment that ended earlier than expected is not followed by the frames continuing the analysed range
+			for checkpointHead < len(segments) && segments[checkpointHead].Done {
+				if !segments[checkpointHead].IsComplete() {
+					checkpointHead = len(segments)
+					break
+				}
+
+				checkpointHead += 1
+				if checkpointHead < len(segments) {
+					checkpoint.Push(segments[checkpointHead].Frames...)
+				}
+			}
+		}(index)
+	}
+
+	wg.Wait()
+
+	return mergeAnalysisSegments(ctx, fc, segments)
+}
+
+type analysisSegment struct {
+	First   int
+	Last    int
+	Overlap bool
+	Frames  []*frame.Frame
+	Done    bool
+	Err     error
+}
+
+// Return a boolean value representing if all frames of the segment range were analysed. The last segment is read to the end of the
+// range, therefore it is always complete after the analysis.
+func (segment *analysisSegment) IsComplete() bool {
+	return segment.Last < 0 || len(segment.Frames) == segment.Last-segment.First+1
+}
+
+// Helper function used to split the analysed frames range into at most the given count of segments. Each segment, except the first
+// one, is overlapping the preceding segment by one frame in order to use the overlapping frame as the previous frame for the difference
+// metrics, therefore the result is identical to the sequential analysis. The first segment is overlapping only if the analysis is
+// resumed. The last segment is read to the end of the range, because the frame count is an approximation.
+func splitAnalysisSegments(firstFrame, lastFrame, frameCount, workers int, resumed bool) []analysisSegment {
+	workers = utils.MaxInt(1, utils.MinInt(workers, frameCount))
+	segmentLength := (frameCount + workers - 1) / workers
+
+	segments := make([]analysisSegment, 0, workers)
+	for segmentFirst := firstFrame; segmentFirst < firstFrame+frameCount; segmentFirst += segmentLength {
+		segmentLast := segmentFirst + segmentLength - 1
+		if segmentLast >= firstFrame+frameCount-1 {
+			segmentLast = lastFrame
+		}
+
+		segments = append(segments, analysisSegment{
+			First:   segmentFirst,
+			Last:    segmentLast,
+			Overlap: len(segments) != 0 || resumed,
+			Frames:  make([]*frame.Frame, 0, segmentLength),
+		})
+	}
+
+	return segments
+}
+
+// Helper function used to merge the frames of the analysed segments into the collection. The boolean return value indicates if
+// all segments were analysed without an interruption. A segment that is shorter than expected indicates that the video ended earlier
+// than expected, therefore an error is returned if any of the following segments contains frames, which can not be merged without
+// creating a gap in the frames collection.
+func mergeAnalysisSegments(ctx context.Context, fc frame.FrameCollection, segments []analysisSegment) (bool, error) {
+	for index, segment := range segments {
+		interrupted := segment.Err != nil && ctx.Err() != nil && errors.Is(segment.Err, ctx.Err())
+		if segment.Err != nil && !interrupted {
+			return false, fmt.Errorf("analyzer: failed to analyze the video segment starting at frame %d: %w", segment.First+1, segment.Err)
+		}
+
+		for _, f := range segment.Frames {
+			if err := fc.Push(f); err != nil {
+				return false, fmt.Errorf("analyzer: failed to push the frame to the collection: %w", err)
+			}
+		}
+
+		if interrupted {
+			return false, nil
+		}
+
+		if segment.IsComplete() {
+			continue
+		}
+
+		for _, following := range segments[index+1:] {
+			if len(following.Frames) != 0 {
+				return false, fmt.Errorf("analyzer: the video segment starting at frame %d ended after %d of %d frames and the following segments can not be merged", segment.First+1, len(segment.Frames), segment.Last-segment.First+1)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Helper function used to analyze the frames of a single video segment using a separate video instance. The already probed
+// frames timestamps are passed to the video instance, if available, to seek the segment without probing them again. The analysed
+// frames are passed to the handler.
+func (analyzer *analyzer) AnalyzeVideoSegment(ctx context.Context, segment *analysisSegment, timestamps []float64, handler func(f *frame.Frame)) error {
+	video, err := analyzer.OpenVideo()
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to open the video for the segment analysis: %w", err)
 	}
 
 	defer video.Close()
 
+	if timestamps != nil {
+		if err := video.SetFrameTimestamps(timestamps); err != nil {
+			return fmt.Errorf("analyzer: failed to apply the frames timestamps to the segment video: %w", err)
+		}
+	}
+
+	rangeFirst := segment.First
+	if segment.Overlap {
+		rangeFirst -= 1
+	}
+
+	if err := video.SetFrameRange(rangeFirst, segment.Last); err != nil {
+		return fmt.Errorf("analyzer: failed to apply the segment range to the video: %w", err)
+	}
+
+	return analyzer.ReadVideoFrames(ctx, video, segment.First+1, segment.Overlap, func(f *frame.Frame) error {
+		handler(f)
+		return nil
+	})
+}
+
+// Helper function used to read the following frames of the video and pass the created frame instances to the handler. The frame
+// numbering starts with the given frame number. The decoding and denoising is pipelined with the frame metrics computation. If
+// the overlap is used, the first frame is only used as the previous frame reference, otherwise a blank frame is used as reference.
+// The context error is returned if the reading was stopped by the context cancellation.
+func (analyzer *analyzer) ReadVideoFrames(ctx context.Context, video video.Video, frameNumber int, overlap bool, handler func(f *frame.Frame) error) error {
+	targetWidth, targetHeight := video.GetOutputDimensions()
+
+	// NOTE: The consumer is holding the current and previous frame images in addition to the images processed by the pipeline stages.
+	pool := newFrameImagePool(framePipelineDepth+2, targetWidth, targetHeight)
+	framePrevious := pool.Get()
+
+	pipeline := newFramePipeline(func(buffer []byte) (time.Time, error) {
+		return time.Time{}, video.ReadInto(buffer)
+	}, analyzer.Options.Denoise, pool)
+	defer pipeline.Close()
+
+	if overlap {
+		frameCurrent, _, err := pipeline.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("analyzer: failed to read the video frame: %w", err)
+		}
+
+		pool.Put(framePrevious)
+		framePrevious = frameCurrent
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			analyzer.Printer.Info("Stopping the video analysis stage")
+			return ctx.Err()
+		default:
+		}
+
+		frameCurrent, _, err := pipeline.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("analyzer: failed to read the video frame: %w", err)
+		}
+
+		if err := handler(frame.CreateNewFrame(frameCurrent, framePrevious, frameNumber, frame.BinaryThresholdParam)); err != nil {
+			return err
+		}
+
+		frameNumber += 1
+
+		pool.Put(framePrevious)
+		framePrevious = frameCurrent
+	}
+}
+
+// Helper function used to open the video and apply the scaling and detection bounds specified by the options.
+func (analyzer *analyzer) OpenVideo() (video.Video, error) {
+	video, err := analyzer.VideoOpener(analyzer.InputVideoPath, analyzer.Options.VideoStreamIndex, analyzer.Options.ImageSequenceFps)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer: failed to open the video file for the analysis stage: %w", err)
+	}
+
 	if err := video.SetScale(analyzer.Options.FrameScalingFactor); err != nil {
+		video.Close()
 		return nil, fmt.Errorf("analyzer: failed to set the video scaling to the given frame scaling factor: %w", err)
 	}
 
 	if err := video.SetScaleAlgorithm(analyzer.Options.ScaleAlgorithm); err != nil {
+		video.Close()
 		return nil, fmt.Errorf("analyzer: failed to set the video scaling algorithm for the video: %w", err)
 	}
 
 	if len(analyzer.Options.DetectionBoundsExpression) != 0 {
 		x, y, w, h, err := utils.ParseBoundsExpression(analyzer.Options.DetectionBoundsExpression)
 		if err != nil {
+			video.Close()
 			return nil, fmt.Errorf("analyzer: failed to parse the detection bounds expression: %w", err)
 		}
 
 		if err := video.SetBbox(x, y, w, h); err != nil {
+			video.Close()
 			return nil, fmt.Errorf("analyzer: failed to apply the detection bounds to the video: %w", err)
 		}
 	}
 
-	targetWidth, targetHeight := video.GetOutputDimensions()
-	frameCurrent := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
-	framePrevious := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	return video, nil
+}
 
-	if err := video.SetFrameBuffer(frameCurrent.Pix); err != nil {
-		return nil, fmt.Errorf("analyzer: failed to apply the given buffer as the video frame buffer: %w", err)
+// Helper function used to resolve the timestamp in seconds of the frame specified by the 0-based index. The frames beyond the
+// probed timestamps are extrapolated from the last probed timestamp using the frame rate.
+func resolveFrameTimestamp(timestamps []float64, index int, fps float64) float64 {
+	if index < len(timestamps) {
+		return timestamps[index]
 	}
 
-	// NOTE: Due to the fact that the internal video implementation works in such a way, that the frame count is an approximation instead of an
-	// exact value, the frameCount is used as an initial capacity value for the frames collection and not the result fixed size/frames count.
-	frameNumber := 1
-	frameCount := video.FramesCountApprox()
-	frames := frame.NewFrameCollection(frameCount)
-	defer frames.Lock()
+	if fps <= 0 {
+		return 0
+	}
 
-	progressStep, progressFinalize := analyzer.Printer.ProgressSteps("Video analysis stage.", frameCount)
+	if len(timestamps) == 0 {
+		return float64(index) / fps
+	}
 
-videoRead:
-	for {
-		select {
-		case <-ctx.Done():
-			analyzer.Printer.Info("Stopping the video analysis stage")
-			break videoRead
-		default:
+	return timestamps[len(timestamps)-1] + float64(index-len(timestamps)+1)/fps
+}
+
+// Helper function used to resolve the start and end position expressions to a 0-based frames index range. The last
+// index is inclusive and a negative value indicates that the range is not limited. The start timestamp is rounded up
+// and the end timestamp is rounded down to the nearest frame.
+func resolveAnalysisRange(startExpression, endExpression string, fps float64, timestamps []float64) (int, int, error) {
+	var (
+		first int = 0
+		last  int = -1
+	)
+
+	if len(startExpression) != 0 {
+		value, isFrame, err := utils.ParsePositionExpression(startExpression)
+		if err != nil {
+			return 0, 0, fmt.Errorf("analyzer: failed to parse the analysis start position expression: %w", err)
 		}
 
-		if err := video.Read(); err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, fmt.Errorf("analyzer: failed to read the video frame: %w", err)
+		if isFrame {
+			first = int(value) - 1
+		} else if fps > 0 || len(timestamps) != 0 {
+			first = resolveTimestampFrameIndex(value, true, fps, timestamps)
+		} else {
+			return 0, 0, fmt.Errorf("analyzer: the video frame rate is required to resolve the analysis start time position")
 		}
+	}
 
-		if analyzer.Options.Denoise != options.NoDenoise {
-			if err := denoise.Denoise(frameCurrent, frameCurrent, analyzer.Options.Denoise); err != nil {
-				return nil, fmt.Errorf("analyzer: failed to apply denoise to the current frame image on the analyze stage: %w", err)
-			}
+	if len(endExpression) != 0 {
+		value, isFrame, err := utils.ParsePositionExpression(endExpression)
+		if err != nil {
+			return 0, 0, fmt.Errorf("analyzer: failed to parse the analysis end position expression: %w", err)
 		}
 
-		frame := frame.CreateNewFrame(frameCurrent, framePrevious, frameNumber, frame.BinaryThresholdParam)
-		if err := frames.Push(frame); err != nil {
-			return nil, fmt.Errorf("analyzer: failed to push the frame to the collection: %w", err)
+		if isFrame {
+			last = int(value) - 1
+		} else if fps > 0 || len(timestamps) != 0 {
+			last = resolveTimestampFrameIndex(value, false, fps, timestamps)
+		} else {
+			return 0, 0, fmt.Errorf("analyzer: the video frame rate is required to resolve the analysis end time position")
 		}
 
-		analyzer.Printer.Debug("Frame: [%d/%d]. Brightness: %f ColorDiff: %f BTDiff: %f", frameNumber, frameCount, frame.Brightness, frame.ColorDifference, frame.BinaryThresholdDifference)
+		// NOTE: The positions are compared after the conversion to the frame indexes, therefore the frame position and the time
+		// position are compared using the video frame rate or timestamps.
+		if last < first {
+			return 0, 0, fmt.Errorf("analyzer: the analysis end position is before the start position")
+		}
+	}
 
-		frameNumber += 1
-		progressStep()
+	return first, last, nil
+}
+
+// Helper function used to convert the timestamp in seconds to the 0-based index of the following frame or the preceding frame
+// if rounded down. The probed frames timestamps are used if available, because the frames of a variable frame rate video are
+// not evenly spaced, otherwise the index is calculated using the frame rate.
+func resolveTimestampFrameIndex(timestamp float64, roundUp bool, fps float64, timestamps []float64) int {
+	if len(timestamps) == 0 {
+		if roundUp {
+			return int(math.Ceil(timestamp*fps - positionRoundingEpsilon))
+		}
 
-		// TODO: This can be run concurrently together with CreateNewFrame on separeted goroutines but will require a double-buffered framePrevious.
-		copy(framePrevious.Pix, frameCurrent.Pix)
+		return int(math.Floor(timestamp*fps + positionRoundingEpsilon))
 	}
 
-	progressFinalize()
-	analyzer.Printer.Debug("Video analysis stage finished. Stage took: %s", time.Since(videoAnalysisTime))
-	return frames, nil
+	if roundUp {
+		return sort.Search(len(timestamps), func(index int) bool {
+			return timestamps[index] >= timestamp-positionRoundingEpsilon
+		})
+	}
+
+	return sort.Search(len(timestamps), func(index int) bool {
+		return timestamps[index] > timestamp+positionRoundingEpsilon
+	}) - 1
 }
 
-// Helper function used to import the pre-analyzed frames collection from the JSON export file.
-func (analyzer *analyzer) ImportPreanalyzedFrames() (frame.FrameCollection, bool, error) {
-	frameCollectionCachePath := path.Join(analyzer.OutputDirPath, frameCollectionCacheFilename)
-	if !utils.FileExists(frameCollectionCachePath) {
-		return nil, false, nil
+// The state of the pre-analyzed frames cache compared to the current options and the analyzed video.
+type CacheStatus int
+
+const (
+	// The cache is not present in the cache store or the output directory.
+	CacheMissing CacheStatus = iota
+	// The cache was created using the matching options for the same video.
+	CacheValid
+	// The version 1 cache was created using the matching options, but is not containing the video fingerprint and the timestamps.
+	CacheLegacy
+	// The cache was created using different analysis options.
+	CacheOptionsMismatch
+	// The cache was created for a different or modified video.
+	CacheVideoMismatch
+)
+
+// Helper function used to import the pre-analyzed frames collection from the cache. The frames timestamps are probed from the video
+// if the legacy cache is imported, because they are not stored in the version 1 cache. The legacy cache is not containing the video
+// fingerprint, therefore the cache is treated as created for a different video if the frames are not matching the analysis range.
+func (analyzer *analyzer) ImportPreanalyzedFrames() (frame.FrameCollection, CacheStatus, error) {
+	frames, status, err := importFramesCache(analyzer.OutputDirPath, analyzer.Fingerprint, analyzer.Options)
+	if err != nil || status != CacheLegacy {
+		return frames, status, err
+	}
+
+	video, err := analyzer.VideoOpener(analyzer.InputVideoPath, analyzer.Options.VideoStreamIndex, analyzer.Options.ImageSequenceFps)
+	if err != nil {
+		return nil, status, fmt.Errorf("analyzer: failed to open the video file for the frames timestamps probing: %w", err)
+	}
+
+	defer video.Close()
+
+	analyzer.Metadata = newVideoMetadata(video)
+
+	timestamps := analyzer.ProbeFrameTimestamps(video)
+
+	firstFrame, lastFrame, err := resolveAnalysisRange(analyzer.Options.AnalysisStartExpression, analyzer.Options.AnalysisEndExpression, video.FramesPerSecond(), timestamps)
+	if err != nil {
+		return nil, status, fmt.Errorf("analyzer: failed to resolve the analysis range: %w", err)
+	}
+
+	framesCount, exact := len(timestamps), timestamps != nil
+	if !exact {
+		framesCount = video.FramesCountApprox()
+	}
+
+	if !isLegacyCacheMatchingVideo(frames, firstFrame, lastFrame, framesCount, exact) {
+		return nil, CacheVideoMismatch, nil
+	}
+
+	analyzer.AssignFrameTimestamps(frames, timestamps, video.FramesPerSecond())
+	return frames, status, nil
+}
+
+// The maximal relative difference between the frames count of the legacy cache and the approximated frames count of the video.
+const legacyCacheFramesCountTolerance float64 = 0.01
+
+// Helper function used to check if the frames of the legacy cache are matching the analysis range of the video with the given frames
+// count. The frames must start at the first frame of the range and end at the last frame of the range. The last frame is compared with
+// a tolerance if the video frames count is an approximation.
+func isLegacyCacheMatchingVideo(fc frame.FrameCollection, firstFrame, lastFrame, framesCount int, exact bool) bool {
+	frames := fc.GetAll()
+	if len(frames) == 0 || frames[0].OrdinalNumber != firstFrame+1 {
+		return false
+	}
+
+	expectedLastFrame := framesCount - 1
+	if lastFrame >= 0 {
+		expectedLastFrame = utils.MinInt(lastFrame, expectedLastFrame)
+	}
+
+	difference := math.Abs(float64(frames[len(frames)-1].OrdinalNumber - 1 - expectedLastFrame))
+	if exact {
+		return difference == 0
+	}
+
+	return difference <= math.Max(1, legacyCacheFramesCountTolerance*float64(framesCount))
+}
+
+// Import the pre-analyzed frames collection from the cache store or the cache of the output directory. The frames are returned only if
+// the cache status is valid or legacy. The video content fingerprint is not verified if the input video path is empty. The frames
+// timestamps are not available if the legacy cache is imported.
+func ImportPreanalyzedFramesCache(outputDirPath, inputVideoPath string, o options.DetectorOptions) (frame.FrameCollection, CacheStatus, error) {
+	return importFramesCache(outputDirPath, newVideoFingerprint(inputVideoPath), o)
+}
+
+func importFramesCache(outputDirPath string, fingerprint *videoFingerprint, o options.DetectorOptions) (frame.FrameCollection, CacheStatus, error) {
+	frameCollectionCachePath, status, err := locateFramesCacheFile(outputDirPath, fingerprint, o)
+	if err != nil || (status != CacheValid && status != CacheLegacy) {
+		return nil, status, err
 	}
 
 	frameCollectionCacheFile, err := os.Open(frameCollectionCachePath)
 	if err != nil {
-		return nil, true, fmt.Errorf("analyzer: failed to open the frame collection cache with preanalyzed frames: %w", err)
+		return nil, status, fmt.Errorf("analyzer: failed to open the frame collection cache with preanalyzed frames: %w", err)
 	}
 
 	defer func() {
@@ -176,75 +746,442 @@ func (analyzer *analyzer) ImportPreanalyzedFrames() (frame.FrameCollection, bool
 		}
 	}()
 
-	optionsChecksum, err := options.CalculateChecksum(analyzer.Options)
+	frames, _, err := frame.ImportCachedFrameCollection(frameCollectionCacheFile)
 	if err != nil {
-		return nil, true, fmt.Errorf("analyzer: failed to access the detector options checksum: %w", err)
+		return nil, status, fmt.Errorf("analyzer: failed to import the frame collection cache with preanalyzed frames: %w", err)
+	}
+
+	// NOTE: The modification time of the cache store entry is used as the last usage time by the cache pruning. The failure is not
+	// interrupting the import, because the cache store may be read-only.
+	if frameCollectionCachePath != path.Join(outputDirPath, frameCollectionCacheFilename) {
+		now := time.Now()
+		_ = os.Chtimes(frameCollectionCachePath, now, now)
+	}
+
+	return frames, status, nil
+}
+
+// Check the state of the pre-analyzed frames cache of the cache store or the output directory compared to the options and the content
+// fingerprint of the video. The version 1 cache is compared using the legacy options checksum. The video content fingerprint is not
+// verified if the input video path is empty.
+func CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath string, o options.DetectorOptions) (CacheStatus, error) {
+	_, status, err := locateFramesCacheFile(outputDirPath, newVideoFingerprint(inputVideoPath), o)
+	return status, err
+}
+
+// Helper function used to resolve the paths of the pre-analyzed frames cache or the analysis checkpoint in the order of precedence. The
+// cache store entry precedes the file of the output directory, if the cache store is used. Without the input video, the cache store
+// entry is searched using the options checksum, only if the output directory is not containing the cache.
+func resolveFramesCachePaths(outputDirPath string, fingerprint *videoFingerprint, o options.DetectorOptions, checkpoint bool) ([]string, error) {
+	outputDirFilename := frameCollectionCacheFilename
+	if checkpoint {
+		outputDirFilename = frameCollectionCheckpointFilename
+	}
+
+	outputDirCachePath := path.Join(outputDirPath, outputDirFilename)
+	if len(o.CacheDirectoryPath) == 0 {
+		return []string{outputDirCachePath}, nil
 	}
 
-	if equal, err := frame.ChecksumEqualPeek(frameCollectionCacheFile, optionsChecksum); err != nil {
-		return nil, false, fmt.Errorf("analyzer: failed to peek and compare preanalzyed frames checksum: %w", err)
-	} else if !equal {
-		return nil, false, nil
+	store := NewCacheStore(o.CacheDirectoryPath)
+	if fingerprint.IsAvailable() {
+		storeEntryPath, err := store.resolveEntryPath(fingerprint, o, checkpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{storeEntryPath, outputDirCachePath}, nil
 	}
 
-	if _, err := frameCollectionCacheFile.Seek(0, io.SeekStart); err != nil {
-		return nil, false, fmt.Errorf("analyzer: failed to reset the frame collection cache file reading offset: %w", err)
+	if checkpoint || utils.FileExists(outputDirCachePath) {
+		return []string{outputDirCachePath}, nil
 	}
 
-	if frames, _, err := frame.ImportCachedFrameCollection(frameCollectionCacheFile); err != nil {
-		return nil, true, fmt.Errorf("analyzer: failed to import the json frames report with preanalyzed frames: %w", err)
-	} else {
-		return frames, true, nil
+	optionsChecksum, err := options.CalculateChecksum(o)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer: failed to access the detector options checksum: %w", err)
+	}
+
+	entries, err := store.FindByChecksum(optionsChecksum)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(entries) {
+	case 0:
+		return []string{outputDirCachePath}, nil
+	case 1:
+		return []string{entries[0].Path, outputDirCachePath}, nil
+	default:
+		return nil, fmt.Errorf("analyzer: the cache store contains %d analyses created using matching options, the input video must be specified", len(entries))
 	}
 }
 
-func (analyzer *analyzer) ExportPreanalyzedFrames(fc frame.FrameCollection) error {
-	frameCollectionCachePath := path.Join(analyzer.OutputDirPath, frameCollectionCacheFilename)
+// Helper function used to find the first present pre-analyzed frames cache and check its state. The path of the preferred cache location
+// is returned if no cache is present.
+func locateFramesCacheFile(outputDirPath string, fingerprint *videoFingerprint, o options.DetectorOptions) (string, CacheStatus, error) {
+	frameCollectionCachePaths, err := resolveFramesCachePaths(outputDirPath, fingerprint, o, false)
+	if err != nil {
+		return "", CacheMissing, err
+	}
 
-	var (
-		frameCollectionCacheFile *os.File
-		optionsChecksum          string
-		err                      error
-	)
+	for _, frameCollectionCachePath := range frameCollectionCachePaths {
+		if utils.FileExists(frameCollectionCachePath) {
+			status, err := checkFramesCacheFile(frameCollectionCachePath, fingerprint, o)
+			return frameCollectionCachePath, status, err
+		}
+	}
 
-	if optionsChecksum, err = options.CalculateChecksum(analyzer.Options); err != nil {
-		return fmt.Errorf("analyzer: failed to access the options checksum: %w", err)
+	return frameCollectionCachePaths[0], CacheMissing, nil
+}
+
+func checkFramesCacheFile(frameCollectionCachePath string, fingerprint *videoFingerprint, o options.DetectorOptions) (CacheStatus, error) {
+	if !utils.FileExists(frameCollectionCachePath) {
+		return CacheMissing, nil
 	}
 
-	defer func() {
-		if frameCollectionCacheFile == nil {
-			return
+	frameCollectionCacheFile, err := os.Open(frameCollectionCachePath)
+	if err != nil {
+		return CacheMissing, fmt.Errorf("analyzer: failed to open the frame collection cache with preanalyzed frames: %w", err)
+	}
+
+	defer frameCollectionCacheFile.Close()
+
+	header, err := frame.PeekCacheHeader(frameCollectionCacheFile)
+	if err != nil {
+		return CacheMissing, fmt.Errorf("analyzer: failed to peek the preanalyzed frames cache header: %w", err)
+	}
+
+	if header.Version == frame.FormatVersion1 {
+		legacyChecksum, err := options.CalculateLegacyChecksum(o)
+		if err != nil {
+			return CacheMissing, fmt.Errorf("analyzer: failed to access the detector options legacy checksum: %w", err)
 		}
 
-		if err := frameCollectionCacheFile.Close(); err != nil {
-			panic(err)
+		if header.Checksum != legacyChecksum {
+			return CacheOptionsMismatch, nil
 		}
-	}()
 
-	if utils.FileExists(frameCollectionCachePath) {
-		frameCollectionCacheFile, err = os.Open(frameCollectionCachePath)
+		return CacheLegacy, nil
+	}
+
+	optionsChecksum, err := options.CalculateChecksum(o)
+	if err != nil {
+		return CacheMissing, fmt.Errorf("analyzer: failed to access the detector options checksum: %w", err)
+	}
+
+	if header.Checksum != optionsChecksum {
+		return CacheOptionsMismatch, nil
+	}
+
+	if fingerprint.IsAvailable() && len(header.Fingerprint) != 0 {
+		value, err := fingerprint.Get()
 		if err != nil {
-			return fmt.Errorf("analyzer: failed to open the frame collection cache with preanalyzed frames: %w", err)
+			return CacheMissing, err
 		}
 
-		if equal, err := frame.ChecksumEqualPeek(frameCollectionCacheFile, optionsChecksum); err != nil {
-			return fmt.Errorf("analyzer: failed to peek and compare preanalzyed frames checksum: %w", err)
-		} else {
-			if equal {
-				return nil
+		if header.Fingerprint != value {
+			return CacheVideoMismatch, nil
+		}
+	}
+
+	return CacheValid, nil
+}
+
+// Check if the cache store or the output directory contains the pre-analyzed frames cache created using options with a matching checksum for the same video.
+func IsPreanalyzedFramesCacheValid(outputDirPath, inputVideoPath string, o options.DetectorOptions) (bool, error) {
+	status, err := CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	if err != nil {
+		return false, err
+	}
+
+	return status == CacheValid, nil
+}
+
+// Remove the pre-analyzed frames cache and the analysis checkpoint of the video from the cache store and the output directory, if present.
+func RemovePreanalyzedFramesCache(outputDirPath, inputVideoPath string, o options.DetectorOptions) error {
+	fingerprint := newVideoFingerprint(inputVideoPath)
+	for _, checkpoint := range []bool{false, true} {
+		frameCollectionCachePaths, err := resolveFramesCachePaths(outputDirPath, fingerprint, o, checkpoint)
+		if err != nil {
+			return err
+		}
+
+		for _, frameCollectionCachePath := range frameCollectionCachePaths {
+			if err := os.Remove(frameCollectionCachePath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("analyzer: failed to remove the frame collection cache with preanalyzed frames: %w", err)
 			}
 		}
 	}
 
-	frameCollectionCacheFile, err = utils.CreateFileWithTree(frameCollectionCachePath)
+	return nil
+}
+
+// Helper function used to export the frames collection as the version 2 cache with the video content fingerprint. The cache is exported
+// to the cache store, if used, otherwise to the output directory. The cache is not overwritten if it is already valid.
+func (analyzer *analyzer) ExportPreanalyzedFrames(fc frame.FrameCollection) error {
+	frameCollectionCachePaths, err := resolveFramesCachePaths(analyzer.OutputDirPath, analyzer.Fingerprint, analyzer.Options, false)
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to resolve the frame collection cache path: %w", err)
+	}
+
+	if status, err := checkFramesCacheFile(frameCollectionCachePaths[0], analyzer.Fingerprint, analyzer.Options); err != nil {
+		analyzer.Printer.Debug("Preanalyzed frames cache check failure: %s", err)
+	} else if status == CacheValid {
+		return nil
+	}
+
+	return analyzer.exportFramesCacheFile(frameCollectionCachePaths[0], fc)
+}
+
+// Return a boolean value representing if the analysis progress is stored in the checkpoints. The checkpoints are stored only if the
+// pre-analyzed frames cache is used.
+func (analyzer *analyzer) IsCheckpointEnabled() bool {
+	return analyzer.Options.ImportPreanalyzed && analyzer.Options.CheckpointInterval > 0
+}
+
+// Helper function used to import the frames analysed before the interruption of the previous analysis. The checkpoint is ignored if it
+// was created using different options, for a different video or is not starting at the first frame of the analysis range.
+func (analyzer *analyzer) ImportAnalysisCheckpoint(firstFrame int) []*frame.Frame {
+	if !analyzer.IsCheckpointEnabled() {
+		return nil
+	}
+
+	checkpointPath, err := analyzer.resolveCheckpointPath()
+	if err != nil {
+		analyzer.Printer.Warning("Failed to resolve the video analysis checkpoint path. The checkpoint is ignored.")
+		analyzer.Printer.Debug("Video analysis checkpoint path resolve failure: %s", err)
+		return nil
+	}
+
+	status, err := checkFramesCacheFile(checkpointPath, analyzer.Fingerprint, analyzer.Options)
+	if err != nil {
+		analyzer.Printer.Warning("Failed to check the video analysis checkpoint. The checkpoint is ignored.")
+		analyzer.Printer.Debug("Video analysis checkpoint check failure: %s", err)
+		return nil
+	}
+
+	switch status {
+	case CacheValid:
+	case CacheMissing:
+		return nil
+	default:
+		analyzer.Printer.Warning("The video analysis checkpoint was created using different options or for a different video. The checkpoint is ignored.")
+		return nil
+	}
+
+	checkpointFile, err := os.Open(checkpointPath)
+	if err != nil {
+		analyzer.Printer.Debug("Video analysis checkpoint open failure: %s", err)
+		return nil
+	}
+
+	defer checkpointFile.Close()
+
+	fc, _, err := frame.ImportAppendedFrameCollection(checkpointFile)
+	if err != nil {
+		analyzer.Printer.Warning("Failed to import the video analysis checkpoint. The checkpoint is ignored.")
+		analyzer.Printer.Debug("Video analysis checkpoint import failure: %s", err)
+		return nil
+	}
+
+	frames := fc.GetAll()
+	if len(frames) == 0 || frames[0].OrdinalNumber != firstFrame+1 {
+		return nil
+	}
+
+	return frames
+}
+
+// Helper function used to store the frames analysed so far as the checkpoint. The checkpoint is replaced with the given frames.
+func (analyzer *analyzer) ExportAnalysisCheckpoint(frames []*frame.Frame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	checkpointPath, err := analyzer.resolveCheckpointPath()
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to resolve the video analysis checkpoint path: %w", err)
+	}
+
+	if err := analyzer.exportFramesCacheFile(checkpointPath, createCheckpointFrameCollection(frames)); err != nil {
+		return fmt.Errorf("analyzer: failed to export the video analysis checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function used to append the frames following the frames stored in the checkpoint, without rewriting the stored frames.
+func (analyzer *analyzer) AppendAnalysisCheckpoint(frames []*frame.Frame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	checkpointPath, err := analyzer.resolveCheckpointPath()
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to resolve the video analysis checkpoint path: %w", err)
+	}
+
+	optionsChecksum, fingerprint, err := analyzer.resolveFramesCacheHeader()
+	if err != nil {
+		return err
+	}
+
+	checkpointFile, err := os.OpenFile(checkpointPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to open the video analysis checkpoint: %w", err)
+	}
+
+	if err := frame.ExportCachedFrameCollection(checkpointFile, createCheckpointFrameCollection(frames), optionsChecksum, fingerprint); err != nil {
+		checkpointFile.Close()
+		return fmt.Errorf("analyzer: failed to append the frames to the video analysis checkpoint: %w", err)
+	}
+
+	if err := checkpointFile.Close(); err != nil {
+		return fmt.Errorf("analyzer: failed to close the video analysis checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func createCheckpointFrameCollection(frames []*frame.Frame) frame.FrameCollection {
+	fc := frame.NewOffsetFrameCollection(len(frames), frames[0].OrdinalNumber-1)
+	for _, f := range frames {
+		if err := fc.Push(f); err != nil {
+			panic(fmt.Errorf("analyzer: failed to push the frame to the checkpoint collection: %w", err))
+		}
+	}
+
+	fc.Lock()
+	return fc
+}
+
+// Structure representing the analysis checkpoint stored during the analysis. The consecutive analysed frames are pushed to the
+// checkpoint, which is stored after each interval of frames. The first store is replacing the checkpoint with all frames, including
+// the frames resumed from the previous checkpoint, and the following stores are appending only the frames pushed since the previous
+// store, therefore the stored frames are not written again. The failures are not interrupting the analysis.
+type analysisCheckpoint struct {
+	Analyzer *analyzer
+	Frames   []*frame.Frame
+	Stored   int
+}
+
+func newAnalysisCheckpoint(analyzer *analyzer, checkpointFrames []*frame.Frame) *analysisCheckpoint {
+	return &analysisCheckpoint{
+		Analyzer: analyzer,
+		Frames:   slices.Clone(checkpointFrames),
+		Stored:   0,
+	}
+}
+
+// Push the frames following the already pushed frames and store the checkpoint if the interval of frames was pushed since the
+// previous store.
+func (checkpoint *analysisCheckpoint) Push(frames ...*frame.Frame) {
+	if !checkpoint.Analyzer.IsCheckpointEnabled() {
+		return
+	}
+
+	checkpoint.Frames = append(checkpoint.Frames, frames...)
+
+	if len(checkpoint.Frames)-checkpoint.Stored >= checkpoint.Analyzer.Options.CheckpointInterval {
+		checkpoint.Store()
+	}
+}
+
+// Store the frames pushed since the previous store. The checkpoint is replaced on the following store, if the append failed, because
+// the partially appended frames are ignored by the import, therefore the frames appended after them would not be imported.
+func (checkpoint *analysisCheckpoint) Store() {
+	if !checkpoint.Analyzer.IsCheckpointEnabled() || len(checkpoint.Frames) == checkpoint.Stored {
+		return
+	}
+
+	var err error
+	if checkpoint.Stored == 0 {
+		err = checkpoint.Analyzer.ExportAnalysisCheckpoint(checkpoint.Frames)
+	} else {
+		err = checkpoint.Analyzer.AppendAnalysisCheckpoint(checkpoint.Frames[checkpoint.Stored:])
+	}
+
+	if err != nil {
+		checkpoint.Stored = 0
+
+		checkpoint.Analyzer.Printer.Warning("Failed to store the video analysis checkpoint.")
+		checkpoint.Analyzer.Printer.Debug("Video analysis checkpoint store failure: %s", err)
+		return
+	}
+
+	checkpoint.Stored = len(checkpoint.Frames)
+	checkpoint.Analyzer.Printer.Debug("Video analysis checkpoint stored at frame %d.", checkpoint.Frames[checkpoint.Stored-1].OrdinalNumber)
+}
+
+// Remove the analysis checkpoint from the cache store or the output directory, if present.
+func (analyzer *analyzer) RemoveAnalysisCheckpoint() error {
+	checkpointPath, err := analyzer.resolveCheckpointPath()
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to resolve the video analysis checkpoint path: %w", err)
+	}
+
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("analyzer: failed to remove the video analysis checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function used to resolve the path of the analysis checkpoint. The checkpoint is stored in the cache store, if used.
+func (analyzer *analyzer) resolveCheckpointPath() (string, error) {
+	checkpointPaths, err := resolveFramesCachePaths(analyzer.OutputDirPath, analyzer.Fingerprint, analyzer.Options, true)
+	if err != nil {
+		return "", err
+	}
+
+	return checkpointPaths[0], nil
+}
+
+// Helper function used to resolve the options checksum and the video content fingerprint stored in the cache header.
+func (analyzer *analyzer) resolveFramesCacheHeader() (string, string, error) {
+	optionsChecksum, err := options.CalculateChecksum(analyzer.Options)
+	if err != nil {
+		return "", "", fmt.Errorf("analyzer: failed to access the options checksum: %w", err)
+	}
+
+	fingerprint, err := analyzer.Fingerprint.Get()
+	if err != nil {
+		return "", "", err
+	}
+
+	return optionsChecksum, fingerprint, nil
+}
+
+// Helper function used to export the frames collection as the cache file with the options checksum and the video content fingerprint.
+// The file is written to a temporary file first and renamed, therefore the interrupted export is not leaving a corrupted file.
+func (analyzer *analyzer) exportFramesCacheFile(frameCollectionCachePath string, fc frame.FrameCollection) error {
+	optionsChecksum, fingerprint, err := analyzer.resolveFramesCacheHeader()
+	if err != nil {
+		return err
+	}
+
+	temporaryPath := frameCollectionCachePath + ".tmp"
+
+	frameCollectionCacheFile, err := utils.CreateFileWithTree(temporaryPath)
 	if err != nil {
 		return fmt.Errorf("analyzer: failed to creatae the frame collection cache with preanalyzed frames: %w", err)
 	}
 
-	if err := frame.ExportCachedFrameCollection(frameCollectionCacheFile, fc, optionsChecksum); err != nil {
+	if err := frame.ExportCachedFrameCollection(frameCollectionCacheFile, fc, optionsChecksum, fingerprint); err != nil {
+		frameCollectionCacheFile.Close()
+		os.Remove(temporaryPath)
 		return fmt.Errorf("analyzer: failed to export the preanalyzed frames cache: %w", err)
 	}
 
+	if err := frameCollectionCacheFile.Close(); err != nil {
+		return fmt.Errorf("analyzer: failed to close the frame collection cache with preanalyzed frames: %w", err)
+	}
+
+	if err := os.Rename(temporaryPath, frameCollectionCachePath); err != nil {
+		return fmt.Errorf("analyzer: failed to replace the frame collection cache with preanalyzed frames: %w", err)
+	}
+
 	return nil
 }
 
@@ -254,5 +1191,8 @@ func NewAnalyzer(inputVideo, outputDir string, o options.DetectorOptions, p prin
 		OutputDirPath:  outputDir,
 		Options:        o,
 		Printer:        p,
+		VideoOpener:    video.OpenVideo,
+		Fingerprint:    newVideoFingerprint(inputVideo),
+		Metadata:       nil,
 	}
 }
@@ -0,0 +1,716 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
+)
+
+func TestResolveFrameTimestampShouldUseProbedTimestampsAndExtrapolate(t *testing.T) {
+	timestamps := []float64{0, 0.04, 0.1, 0.14}
+
+	cases := []struct {
+		Timestamps []float64
+		Index      int
+		Fps        float64
+		Expected   float64
+	}{
+		{timestamps, 0, 25, 0},
+		{timestamps, 2, 25, 0.1},
+		{timestamps, 3, 25, 0.14},
+		{timestamps, 4, 25, 0.18},
+		{timestamps, 6, 25, 0.26},
+		{timestamps, 6, 0, 0},
+		{nil, 5, 25, 0.2},
+		{nil, 5, 0, 0},
+	}
+
+	for _, c := range cases {
+		assert.InDelta(t, c.Expected, resolveFrameTimestamp(c.Timestamps, c.Index, c.Fps), 1e-9)
+	}
+}
+
+func TestResolveAnalysisRangeShouldUseProbedTimestamps(t *testing.T) {
+	timestamps := []float64{0, 0.1, 0.15, 0.5, 0.55, 1.0}
+
+	cases := []struct {
+		Start      string
+		End        string
+		Fps        float64
+		Timestamps []float64
+		First      int
+		Last       int
+	}{
+		{"", "", 10, timestamps, 0, -1},
+		{"0.12s", "0.5s", 10, timestamps, 2, 3},
+		{"0.15s", "0.54s", 10, timestamps, 2, 3},
+		{"0.5s", "", 10, timestamps, 3, -1},
+		{"0.12s", "0.5s", 10, nil, 2, 5},
+		{"2", "4", 10, timestamps, 1, 3},
+	}
+
+	for _, c := range cases {
+		first, last, err := resolveAnalysisRange(c.Start, c.End, c.Fps, c.Timestamps)
+		assert.Nil(t, err)
+		assert.Equal(t, c.First, first)
+		assert.Equal(t, c.Last, last)
+	}
+}
+
+func TestResolveAnalysisRangeShouldRejectInvertedRanges(t *testing.T) {
+	cases := []struct {
+		Start string
+		End   string
+		Fps   float64
+		Valid bool
+	}{
+		{"100", "2s", 25, false},
+		{"100", "4s", 25, true},
+		{"4s", "50", 25, false},
+		{"4s", "101", 25, true},
+		{"00:00:04", "00:00:02", 25, false},
+		{"100", "50", 25, false},
+		{"2s", "", 0, false},
+		{"", "2s", 0, false},
+		{"10", "20", 0, true},
+	}
+
+	for _, c := range cases {
+		_, _, err := resolveAnalysisRange(c.Start, c.End, c.Fps, nil)
+		assert.Equal(t, c.Valid, err == nil, "analysis range from %s to %s", c.Start, c.End)
+	}
+}
+
+func TestEstimateAnalysisShouldScaleWithTheScalingFactorAndWorkers(t *testing.T) {
+	full := EstimateAnalysis(1920, 1080, 3000, 1, 1)
+	half := EstimateAnalysis(1920, 1080, 3000, 0.5, 1)
+	parallel := EstimateAnalysis(1920, 1080, 3000, 1, 4)
+
+	assert.Equal(t, 1920, full.OutputWidth)
+	assert.Equal(t, 1080, full.OutputHeight)
+	assert.Equal(t, 960, half.OutputWidth)
+	assert.Equal(t, 540, half.OutputHeight)
+
+	assert.Greater(t, full.Duration, time.Duration(0))
+	assert.LessOrEqual(t, half.Duration, full.Duration)
+	assert.Less(t, parallel.Duration, full.Duration)
+
+	assert.Less(t, half.Memory, full.Memory)
+	assert.Greater(t, parallel.Memory, full.Memory)
+
+	empty := EstimateAnalysis(1920, 1080, 0, 1, 4)
+	assert.Equal(t, time.Duration(0), empty.Duration)
+}
+
+func TestPreanalyzedFramesCacheShouldDetectStaleCache(t *testing.T) {
+	var (
+		outputDirPath  string = t.TempDir()
+		inputVideoPath string = path.Join(t.TempDir(), "video.mp4")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	assert.Nil(t, os.WriteFile(inputVideoPath, []byte("video content"), 0660))
+
+	status, err := CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheMissing, status)
+
+	fc := frame.NewFrameCollection(2)
+	assert.Nil(t, fc.Push(&frame.Frame{OrdinalNumber: 1, Timestamp: 0.0, Brightness: 0.5}))
+	assert.Nil(t, fc.Push(&frame.Frame{OrdinalNumber: 2, Timestamp: 0.5, Brightness: 0.25}))
+	fc.Lock()
+
+	a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+	assert.Nil(t, a.ExportPreanalyzedFrames(fc))
+
+	frames, status, err := ImportPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheValid, status)
+	assert.Equal(t, 2, frames.Count())
+	assert.Equal(t, 0.5, frames.GetAll()[1].Timestamp)
+
+	boundsOptions := o
+	boundsOptions.DetectionBoundsExpression = "0:0:10:10"
+
+	status, err = CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, boundsOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheOptionsMismatch, status)
+
+	assert.Nil(t, os.WriteFile(inputVideoPath, []byte("other video content"), 0660))
+
+	status, err = CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheVideoMismatch, status)
+
+	valid, err := IsPreanalyzedFramesCacheValid(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.False(t, valid)
+
+	status, err = CheckPreanalyzedFramesCache(outputDirPath, "", o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheValid, status)
+}
+
+func createLegacyCacheFile(t *testing.T, outputDirPath string, o options.DetectorOptions, first, count int) {
+	var (
+		data  = &bytes.Buffer{}
+		file  = &bytes.Buffer{}
+		magic = []byte{0x56, 0x4C, 0x44, 0x21}
+	)
+
+	legacyChecksum, err := options.CalculateLegacyChecksum(o)
+	assert.Nil(t, err)
+
+	checksum, err := hex.DecodeString(legacyChecksum)
+	assert.Nil(t, err)
+
+	for index := range count {
+		binary.Write(data, binary.LittleEndian, uint32(first+index))
+		binary.Write(data, binary.LittleEndian, []float64{0.5, 0.25, 0.125})
+	}
+
+	file.Write(magic)
+	file.Write([]byte{frame.FormatVersion1})
+	file.Write(magic)
+	file.Write(checksum)
+	file.Write(magic)
+	file.Write([]byte{0xF0})
+	file.Write(magic)
+	binary.Write(file, binary.LittleEndian, uint32(data.Len()))
+	file.Write(magic)
+	file.Write(data.Bytes())
+
+	assert.Nil(t, os.WriteFile(path.Join(outputDirPath, frameCollectionCacheFilename), file.Bytes(), 0660))
+}
+
+func TestPreanalyzedFramesCacheShouldReadLegacyCache(t *testing.T) {
+	var (
+		outputDirPath string = t.TempDir()
+		o                    = options.GetDefaultDetectorOptions()
+	)
+
+	createLegacyCacheFile(t, outputDirPath, o, 1, 1)
+
+	frames, status, err := ImportPreanalyzedFramesCache(outputDirPath, "", o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheLegacy, status)
+	assert.Equal(t, 1, frames.Count())
+	assert.Equal(t, 0.5, frames.GetAll()[0].Brightness)
+
+	o.FrameScalingFactor = 0.25
+
+	status, err = CheckPreanalyzedFramesCache(outputDirPath, "", o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheOptionsMismatch, status)
+}
+
+func TestPreanalyzedFramesCacheShouldUpgradeOnlyTheLegacyCacheMatchingTheVideo(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.ImportPreanalyzed = true
+
+	cases := []struct {
+		First    int
+		Count    int
+		Upgraded bool
+	}{
+		{1, 60, true},
+		{1, 20, false},
+		{1, 120, false},
+		{11, 50, false},
+	}
+
+	for _, c := range cases {
+		outputDirPath := t.TempDir()
+		createLegacyCacheFile(t, outputDirPath, o, c.First, c.Count)
+
+		a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+		a.VideoOpener = openMockVideo
+
+		frames, err := a.GetFrames(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 60, frames.Count())
+		assert.Equal(t, c.Upgraded, frames.GetAll()[1].Brightness == 0.5, "legacy cache of %d frames", c.Count)
+
+		status, err := CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+		assert.Nil(t, err)
+		assert.Equal(t, CacheValid, status)
+	}
+}
+
+func TestIsLegacyCacheMatchingVideoShouldCompareTheAnalysisRange(t *testing.T) {
+	createFrames := func(first, count int) frame.FrameCollection {
+		fc := frame.NewOffsetFrameCollection(utils.MaxInt(1, count), first-1)
+		for index := range count {
+			assert.Nil(t, fc.Push(&frame.Frame{OrdinalNumber: first + index}))
+		}
+
+		fc.Lock()
+		return fc
+	}
+
+	assert.True(t, isLegacyCacheMatchingVideo(createFrames(1, 300), 0, -1, 300, true))
+	assert.False(t, isLegacyCacheMatchingVideo(createFrames(1, 299), 0, -1, 300, true))
+	assert.True(t, isLegacyCacheMatchingVideo(createFrames(1, 299), 0, -1, 300, false))
+	assert.False(t, isLegacyCacheMatchingVideo(createFrames(1, 200), 0, -1, 300, false))
+	assert.False(t, isLegacyCacheMatchingVideo(createFrames(1, 400), 0, -1, 300, false))
+
+	assert.True(t, isLegacyCacheMatchingVideo(createFrames(11, 10), 10, 19, 300, true))
+	assert.False(t, isLegacyCacheMatchingVideo(createFrames(1, 20), 10, 19, 300, true))
+	assert.True(t, isLegacyCacheMatchingVideo(createFrames(11, 290), 10, 400, 300, true))
+
+	assert.False(t, isLegacyCacheMatchingVideo(createFrames(1, 0), 0, -1, 300, true))
+}
+
+func TestAnalysisCheckpointShouldBeExportedAndImported(t *testing.T) {
+	var (
+		outputDirPath  string = t.TempDir()
+		inputVideoPath string = path.Join(t.TempDir(), "video.mp4")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	assert.Nil(t, os.WriteFile(inputVideoPath, []byte("video content"), 0660))
+
+	o.ImportPreanalyzed = true
+	a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+
+	frames := []*frame.Frame{
+		{OrdinalNumber: 11, Brightness: 0.5},
+		{OrdinalNumber: 12, Brightness: 0.25},
+		{OrdinalNumber: 13, Brightness: 0.125},
+	}
+
+	assert.Nil(t, a.ImportAnalysisCheckpoint(10))
+
+	assert.Nil(t, a.ExportAnalysisCheckpoint(frames))
+	assert.True(t, utils.FileExists(path.Join(outputDirPath, frameCollectionCheckpointFilename)))
+	assert.False(t, utils.FileExists(path.Join(outputDirPath, frameCollectionCheckpointFilename+".tmp")))
+
+	checkpointFrames := a.ImportAnalysisCheckpoint(10)
+	assert.Len(t, checkpointFrames, 3)
+	assert.Equal(t, 13, checkpointFrames[2].OrdinalNumber)
+	assert.Equal(t, 0.125, checkpointFrames[2].Brightness)
+
+	assert.Nil(t, a.ImportAnalysisCheckpoint(0))
+
+	boundsOptions := o
+	boundsOptions.DetectionBoundsExpression = "0:0:10:10"
+	assert.Nil(t, NewAnalyzer(inputVideoPath, outputDirPath, boundsOptions, p).(*analyzer).ImportAnalysisCheckpoint(10))
+
+	disabledOptions := o
+	disabledOptions.CheckpointInterval = 0
+	assert.Nil(t, NewAnalyzer(inputVideoPath, outputDirPath, disabledOptions, p).(*analyzer).ImportAnalysisCheckpoint(10))
+
+	status, err := CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheMissing, status)
+
+	assert.Nil(t, RemovePreanalyzedFramesCache(outputDirPath, inputVideoPath, o))
+	assert.False(t, utils.FileExists(path.Join(outputDirPath, frameCollectionCheckpointFilename)))
+	assert.Nil(t, a.ImportAnalysisCheckpoint(10))
+}
+
+func TestSplitAnalysisSegmentsShouldCoverTheRange(t *testing.T) {
+	segments := splitAnalysisSegments(0, -1, 10, 3, false)
+	assert.Len(t, segments, 3)
+
+	expected := []struct {
+		First   int
+		Last    int
+		Overlap bool
+	}{
+		{0, 3, false},
+		{4, 7, true},
+		{8, -1, true},
+	}
+
+	for index, segment := range segments {
+		assert.Equal(t, expected[index].First, segment.First)
+		assert.Equal(t, expected[index].Last, segment.Last)
+		assert.Equal(t, expected[index].Overlap, segment.Overlap)
+	}
+
+	segments = splitAnalysisSegments(20, 29, 10, 4, true)
+	assert.Len(t, segments, 4)
+	assert.Equal(t, 20, segments[0].First)
+	assert.True(t, segments[0].Overlap)
+	assert.Equal(t, 29, segments[3].Last)
+
+	for index := 1; index < len(segments); index += 1 {
+		assert.Equal(t, segments[index-1].Last+1, segments[index].First)
+	}
+
+	segments = splitAnalysisSegments(0, -1, 2, 8, false)
+	assert.Len(t, segments, 2)
+	assert.Equal(t, 0, segments[0].Last)
+	assert.Equal(t, -1, segments[1].Last)
+}
+
+func TestMergeAnalysisSegmentsShouldNotCreateGaps(t *testing.T) {
+	createFrames := func(first, last int) []*frame.Frame {
+		frames := make([]*frame.Frame, 0, last-first+1)
+		for index := first; index <= last; index += 1 {
+			frames = append(frames, &frame.Frame{OrdinalNumber: index + 1})
+		}
+
+		return frames
+	}
+
+	segments := []analysisSegment{
+		{First: 0, Last: 3, Frames: createFrames(0, 3)},
+		{First: 4, Last: 7, Frames: createFrames(4, 5)},
+		{First: 8, Last: -1, Frames: nil},
+	}
+
+	fc := frame.NewFrameCollection(8)
+	complete, err := mergeAnalysisSegments(context.Background(), fc, segments)
+	assert.Nil(t, err)
+	assert.True(t, complete)
+
+	fc.Lock()
+	assert.Equal(t, 6, fc.Count())
+
+	segments[2].Frames = createFrames(8, 9)
+
+	_, err = mergeAnalysisSegments(context.Background(), frame.NewFrameCollection(8), segments)
+	assert.NotNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	segments[1].Err = ctx.Err()
+
+	fc = frame.NewFrameCollection(8)
+	complete, err = mergeAnalysisSegments(ctx, fc, segments)
+	assert.Nil(t, err)
+	assert.False(t, complete)
+
+	fc.Lock()
+	assert.Equal(t, 6, fc.Count())
+
+	segments[1].Err = errors.New("decoding failed")
+
+	_, err = mergeAnalysisSegments(context.Background(), frame.NewFrameCollection(8), segments)
+	assert.NotNil(t, err)
+}
+
+func TestSegmentedFramesAnalysisShouldMatchTheSequentialAnalysis(t *testing.T) {
+	var (
+		o = options.GetDefaultDetectorOptions()
+		p = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.AnalysisStartExpression = "5"
+	o.AnalysisEndExpression = "40"
+
+	analyze := func(workers int) frame.FrameCollection {
+		o.AnalysisWorkers = workers
+
+		a := NewAnalyzer("video.mp4", t.TempDir(), o, p).(*analyzer)
+		a.VideoOpener = openMockVideo
+
+		frames, complete, err := a.PerformFramesAnalysis(context.Background())
+		assert.Nil(t, err)
+		assert.True(t, complete)
+
+		return frames
+	}
+
+	sequential := analyze(1)
+	assert.Equal(t, 36, sequential.Count())
+
+	for _, workers := range []int{2, 3, 7} {
+		segmented := analyze(workers)
+		assert.Equal(t, sequential.Count(), segmented.Count())
+		assert.Equal(t, sequential.GetAll(), segmented.GetAll(), "segmented analysis using %d workers", workers)
+	}
+}
+
+func TestAnalysisCheckpointShouldBeAppendedDuringTheAnalysis(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.ImportPreanalyzed = true
+	o.CheckpointInterval = 10
+
+	for _, workers := range []int{1, 3} {
+		o.AnalysisWorkers = workers
+
+		a := NewAnalyzer(inputVideoPath, t.TempDir(), o, p).(*analyzer)
+		a.VideoOpener = openMockVideo
+
+		frames, complete, err := a.PerformFramesAnalysis(context.Background())
+		assert.Nil(t, err)
+		assert.True(t, complete)
+
+		checkpointPath, err := a.resolveCheckpointPath()
+		assert.Nil(t, err)
+
+		checkpointFile, err := os.Open(checkpointPath)
+		assert.Nil(t, err)
+
+		first, _, err := frame.ImportCachedFrameCollection(checkpointFile)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, first.Count(), "first stored checkpoint using %d workers", workers)
+
+		checkpointFile.Close()
+
+		// NOTE: The frames pushed after the last checkpoint interval are stored only if the analysis is interrupted
+		checkpointFrames := importMockCheckpoint(a)
+		assert.GreaterOrEqual(t, len(checkpointFrames), frames.Count()-o.CheckpointInterval)
+		assert.Equal(t, frames.GetAll()[:len(checkpointFrames)], checkpointFrames, "checkpoint using %d workers", workers)
+	}
+}
+
+func TestInterruptedAnalysisShouldBeResumedFromTheCheckpoint(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.ImportPreanalyzed = true
+	o.CheckpointInterval = 7
+
+	a := NewAnalyzer(inputVideoPath, t.TempDir(), o, p).(*analyzer)
+	a.VideoOpener = openMockVideo
+
+	expected, _, err := a.PerformFramesAnalysis(context.Background())
+	assert.Nil(t, err)
+
+	for _, workers := range []int{1, 3} {
+		o.AnalysisWorkers = workers
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		a := NewAnalyzer(inputVideoPath, t.TempDir(), o, p).(*analyzer)
+		a.VideoOpener = func(path string, streamIndex int, sequenceFps float64) (video.Video, error) {
+			v, err := openMockVideo(path, streamIndex, sequenceFps)
+			return &interruptedMockVideo{mockVideo: v.(*mockVideo), InterruptFrame: 45, Interrupt: cancel}, err
+		}
+
+		frames, complete, err := a.PerformFramesAnalysis(ctx)
+		assert.Nil(t, err)
+		assert.False(t, complete)
+		assert.Equal(t, frames.GetAll(), importMockCheckpoint(a), "interrupted checkpoint using %d workers", workers)
+
+		a.VideoOpener = openMockVideo
+
+		frames, complete, err = a.PerformFramesAnalysis(context.Background())
+		assert.Nil(t, err)
+		assert.True(t, complete)
+		assert.Equal(t, expected.GetAll(), frames.GetAll(), "resumed analysis using %d workers", workers)
+	}
+}
+
+func TestInterruptedSegmentedAnalysisShouldNotExportTheCache(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		outputDirPath  string = t.TempDir()
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.ImportPreanalyzed = true
+	o.AnalysisWorkers = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+	a.VideoOpener = func(path string, streamIndex int, sequenceFps float64) (video.Video, error) {
+		v, err := openMockVideo(path, streamIndex, sequenceFps)
+		return &interruptedMockVideo{mockVideo: v.(*mockVideo), InterruptFrame: 30, Interrupt: cancel}, err
+	}
+
+	frames, err := a.GetFrames(ctx)
+	assert.ErrorIs(t, err, ErrAnalysisIncomplete)
+	assert.Nil(t, frames)
+
+	status, err := CheckPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheMissing, status)
+}
+
+func TestGetVideoMetadataShouldOpenTheVideoOnlyForTheImportedFrames(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		outputDirPath  string = t.TempDir()
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+		opened         int    = 0
+	)
+
+	o.ImportPreanalyzed = true
+
+	openCountedMockVideo := func(path string, streamIndex int, sequenceFps float64) (video.Video, error) {
+		opened += 1
+		return openMockVideo(path, streamIndex, sequenceFps)
+	}
+
+	for _, imported := range []bool{false, true} {
+		a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+		a.VideoOpener = openCountedMockVideo
+
+		_, err := a.GetFrames(context.Background())
+		assert.Nil(t, err)
+
+		analysisOpened := opened
+
+		metadata, err := a.GetVideoMetadata()
+		assert.Nil(t, err)
+		assert.Equal(t, 25.0, metadata.FramesPerSecond)
+
+		_, err = a.GetVideoMetadata()
+		assert.Nil(t, err)
+
+		if imported {
+			assert.Equal(t, analysisOpened+1, opened)
+		} else {
+			assert.Equal(t, analysisOpened, opened)
+		}
+	}
+}
+
+// Helper function used to import the checkpoint of the mock video analysis. The checkpoint frames are stored before the timestamps are
+// assigned, therefore the timestamps are assigned the same way as after the analysis.
+func importMockCheckpoint(a *analyzer) []*frame.Frame {
+	frames := a.ImportAnalysisCheckpoint(0)
+	for _, f := range frames {
+		f.Timestamp = resolveFrameTimestamp(nil, f.OrdinalNumber-1, 25)
+	}
+
+	return frames
+}
+
+// Mock video cancelling the analysis after reading the given frame.
+type interruptedMockVideo struct {
+	*mockVideo
+	InterruptFrame int
+	Interrupt      func()
+}
+
+func (v *interruptedMockVideo) ReadInto(buffer []byte) error {
+	if err := v.mockVideo.ReadInto(buffer); err != nil {
+		return err
+	}
+
+	if v.FrameIndex > v.InterruptFrame {
+		v.Interrupt()
+	}
+
+	return nil
+}
+
+func TestProbeFrameTimestampsShouldProbeOnlyVariableFrameRateOrRangedVideos(t *testing.T) {
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	cases := []struct {
+		VariableFps bool
+		Start       string
+		Expected    int
+	}{
+		{false, "", 0},
+		{true, "", 1},
+		{false, "10", 1},
+	}
+
+	for _, c := range cases {
+		o := options.GetDefaultDetectorOptions()
+		o.AnalysisStartExpression = c.Start
+
+		a := NewAnalyzer("video.mp4", t.TempDir(), o, p).(*analyzer)
+
+		v := &mockVideo{Width: 8, Height: 6, FramesCount: 60, LastFrame: -1, FrameIndex: -1, VariableFps: c.VariableFps}
+		assert.Nil(t, a.ProbeFrameTimestamps(v))
+		assert.Equal(t, c.Expected, v.TimestampProbes)
+	}
+}
+
+// Mock video providing a deterministic content of the frames without the video processing binaries.
+type mockVideo struct {
+	Width           int
+	Height          int
+	FramesCount     int
+	FirstFrame      int
+	LastFrame       int
+	FrameIndex      int
+	VariableFps     bool
+	TimestampProbes int
+}
+
+func openMockVideo(path string, streamIndex int, sequenceFps float64) (video.Video, error) {
+	return &mockVideo{Width: 8, Height: 6, FramesCount: 60, FirstFrame: 0, LastFrame: -1, FrameIndex: -1}, nil
+}
+
+func (v *mockVideo) GetInputDimensions() (int, int)                   { return v.Width, v.Height }
+func (v *mockVideo) GetOutputDimensions() (int, int)                  { return v.Width, v.Height }
+func (v *mockVideo) SetScale(s float64) error                         { return nil }
+func (v *mockVideo) SetScaleAlgorithm(a options.ScaleAlgorithm) error { return nil }
+func (v *mockVideo) SetBbox(x, y, w, h int) error                     { return nil }
+func (v *mockVideo) SetTargetFrames(n ...int) error                   { return fmt.Errorf("mock: not supported") }
+func (v *mockVideo) FramesPerSecond() float64                         { return 25 }
+func (v *mockVideo) IsVariableFrameRate() bool                        { return v.VariableFps }
+func (v *mockVideo) FrameTimestamps() ([]float64, error) {
+	v.TimestampProbes += 1
+	return nil, fmt.Errorf("mock: not supported")
+}
+func (v *mockVideo) SetFrameTimestamps(timestamps []float64) error { return nil }
+func (v *mockVideo) CreationTime() time.Time                       { return time.Time{} }
+func (v *mockVideo) SetFrameBuffer(buffer []byte) error            { return fmt.Errorf("mock: not supported") }
+func (v *mockVideo) Read() error                                   { return fmt.Errorf("mock: not supported") }
+func (v *mockVideo) Close()                                        {}
+
+func (v *mockVideo) SetFrameRange(first, last int) error {
+	v.FirstFrame, v.LastFrame = first, last
+	return nil
+}
+
+func (v *mockVideo) FramesCountApprox() int {
+	if v.LastFrame >= 0 {
+		return v.LastFrame - v.FirstFrame + 1
+	}
+
+	return v.FramesCount - v.FirstFrame
+}
+
+func (v *mockVideo) ReadInto(buffer []byte) error {
+	if v.FrameIndex < 0 {
+		v.FrameIndex = v.FirstFrame
+	}
+
+	if v.FrameIndex >= v.FramesCount || (v.LastFrame >= 0 && v.FrameIndex > v.LastFrame) {
+		return io.EOF
+	}
+
+	for index := range buffer {
+		buffer[index] = uint8((v.FrameIndex*v.FrameIndex*31 + index*7) % 251)
+	}
+
+	v.FrameIndex += 1
+	return nil
+}
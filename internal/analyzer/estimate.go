@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"math"
+	"time"
+	"unsafe"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// NOTE: The throughput values are assumed reference values of a single desktop CPU core and were not measured with a benchmark. The
+// real throughput depends on the hardware, the video codec and the denoise algorithm and may differ by an order of magnitude, therefore
+// the estimated processing time must be presented as a rough guess.
+const (
+	// The assumed amount of input video pixels decoded per second by a single decoding process
+	estimatedDecodePixelsPerSecond float64 = 150e6
+	// The assumed amount of scaled frame pixels processed per second by the frame metrics computation of a single worker
+	estimatedAnalysisPixelsPerSecond float64 = 60e6
+	// The size of a single frame collection entry including the frame pointer
+	estimatedFrameEntrySize uint64 = uint64(unsafe.Sizeof(frame.Frame{})) + uint64(unsafe.Sizeof(&frame.Frame{}))
+)
+
+// Structure representing the approximate resources required by the video analysis. The estimate is not including the memory
+// used by the decoding processes and the processing time is based on the assumed throughput, therefore it is only a rough guess.
+type AnalysisEstimate struct {
+	OutputWidth  int
+	OutputHeight int
+	Duration     time.Duration
+	Memory       uint64
+}
+
+// Estimate the resources required to analyze the video with the given dimensions and frames count using the scaling factor and
+// the amount of workers. Each worker is holding the frame images of the pipeline, the frame buffer and the previous frame.
+func EstimateAnalysis(width, height, frames int, scale float64, workers int) AnalysisEstimate {
+	var (
+		outputWidth  int = int(float64(width) * scale)
+		outputHeight int = int(float64(height) * scale)
+	)
+
+	workers = utils.MaxInt(1, utils.MinInt(workers, frames))
+
+	var (
+		imageSize     uint64  = uint64(outputWidth) * uint64(outputHeight) * 4
+		workerMemory  uint64  = uint64(framePipelineDepth+3) * imageSize
+		framesMemory  uint64  = uint64(utils.MaxInt(0, frames)) * estimatedFrameEntrySize
+		decodeSeconds float64 = float64(width) * float64(height) / estimatedDecodePixelsPerSecond
+		frameSeconds  float64 = float64(outputWidth) * float64(outputHeight) / estimatedAnalysisPixelsPerSecond
+	)
+
+	// NOTE: The decoding and the frame metrics computation are pipelined, therefore the slower stage is determining the throughput
+	seconds := float64(utils.MaxInt(0, frames)) * math.Max(decodeSeconds, frameSeconds) / float64(workers)
+
+	return AnalysisEstimate{
+		OutputWidth:  outputWidth,
+		OutputHeight: outputHeight,
+		Duration:     time.Duration(seconds * float64(time.Second)),
+		Memory:       uint64(workers)*workerMemory + framesMemory,
+	}
+}
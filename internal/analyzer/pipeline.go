@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/denoise"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+)
+
+// The count of frame images that can be simultaneously processed by the pipeline stages. There is one image being decoded,
+// one image being denoised and one image waiting in each of the channels between the stages.
+const framePipelineDepth int = 4
+
+// A fixed size pool of frame images shared between the pipeline stages and the consumer. The images are passed between the
+// stages by pointer and returned to the pool when no longer used, therefore no frame data is copied between the stages.
+type frameImagePool struct {
+	Images chan *image.RGBA
+}
+
+// Take an image from the pool. The call is blocking until an image is returned to the pool.
+func (pool *frameImagePool) Get() *image.RGBA {
+	return <-pool.Images
+}
+
+// Return the image to the pool. The image must have been taken from the same pool.
+func (pool *frameImagePool) Put(img *image.RGBA) {
+	pool.Images <- img
+}
+
+func newFrameImagePool(size, width, height int) *frameImagePool {
+	if size <= 0 {
+		panic("analyzer: frame image pool size must be greater than zero")
+	}
+
+	pool := &frameImagePool{
+		Images: make(chan *image.RGBA, size),
+	}
+
+	for index := 0; index < size; index += 1 {
+		pool.Images <- image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	return pool
+}
+
+type pipelineFrame struct {
+	Image     *image.RGBA
+	Timestamp time.Time
+	Err       error
+}
+
+// Pipeline running the frame decoding and denoising in separate stages, concurrently to the frame metrics computation
+// performed by the consumer. The frame images are taken from the pool and the consumer is responsible for returning them.
+type framePipeline struct {
+	Pool     *frameImagePool
+	Output   chan pipelineFrame
+	Done     chan struct{}
+	Wg       sync.WaitGroup
+	IsClosed bool
+}
+
+// Access the next decoded and denoised frame image and the time of the frame provided by the source. If the underlying
+// source is finished it will return io.EOF.
+func (pipeline *framePipeline) Next() (*image.RGBA, time.Time, error) {
+	f, ok := <-pipeline.Output
+	if !ok {
+		return nil, time.Time{}, io.EOF
+	}
+
+	return f.Image, f.Timestamp, f.Err
+}
+
+// Stop the pipeline stages and wait for them to finish. The read performed by the decode stage is not interrupted, therefore the
+// underlying source which may stall (Example: live stream) must be closed before the pipeline.
+func (pipeline *framePipeline) Close() {
+	if !pipeline.IsClosed {
+		close(pipeline.Done)
+		pipeline.IsClosed = true
+	}
+
+	pipeline.Wg.Wait()
+}
+
+func (pipeline *framePipeline) decodeStage(read frameReadFunc, output chan<- pipelineFrame) {
+	defer pipeline.Wg.Done()
+	defer close(output)
+
+	for {
+		var img *image.RGBA
+		select {
+		case <-pipeline.Done:
+			return
+		case img = <-pipeline.Pool.Images:
+		}
+
+		f := pipelineFrame{Image: img}
+		if f.Timestamp, f.Err = read(img.Pix); f.Err != nil {
+			pipeline.Pool.Put(img)
+			f.Image = nil
+		}
+
+		if f.Timestamp.IsZero() {
+			f.Timestamp = time.Now().UTC()
+		}
+
+		select {
+		case <-pipeline.Done:
+			return
+		case output <- f:
+		}
+
+		if f.Err != nil {
+			return
+		}
+	}
+}
+
+func (pipeline *framePipeline) denoiseStage(algorithm options.DenoiseAlgorithm, input <-chan pipelineFrame) {
+	defer pipeline.Wg.Done()
+	defer close(pipeline.Output)
+
+	for f := range input {
+		if f.Err == nil && algorithm != options.NoDenoise {
+			if err := denoise.Denoise(f.Image, f.Image, algorithm); err != nil {
+				pipeline.Pool.Put(f.Image)
+				f.Image = nil
+				f.Err = fmt.Errorf("analyzer: failed to apply denoise to the current frame image on the analyze stage: %w", err)
+			}
+		}
+
+		select {
+		case <-pipeline.Done:
+			return
+		case pipeline.Output <- f:
+		}
+
+		if f.Err != nil {
+			return
+		}
+	}
+}
+
+// Function reading the next frame into the buffer and returning the frame time. A zero time indicates that the frame time is not
+// available and the time at which the frame has been read is used instead.
+type frameReadFunc func(buffer []byte) (time.Time, error)
+
+// Create and start a new frame pipeline reading the frames using the provided read function into the pool images.
+func newFramePipeline(read frameReadFunc, algorithm options.DenoiseAlgorithm, pool *frameImagePool) *framePipeline {
+	pipeline := &framePipeline{
+		Pool:     pool,
+		Output:   make(chan pipelineFrame, 1),
+		Done:     make(chan struct{}),
+		IsClosed: false,
+	}
+
+	decoded := make(chan pipelineFrame, 1)
+
+	pipeline.Wg.Add(2)
+	go pipeline.decodeStage(read, decoded)
+	go pipeline.denoiseStage(algorithm, decoded)
+
+	return pipeline
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+)
+
+func TestFramePipelineShouldReadFramesInOrder(t *testing.T) {
+	const count = 20
+
+	pool := newFrameImagePool(framePipelineDepth+1, 2, 2)
+	pipeline := newFramePipeline(mockFramesReader(count, nil), options.NoDenoise, pool)
+	defer pipeline.Close()
+
+	for index := 0; index < count; index += 1 {
+		img, timestamp, err := pipeline.Next()
+		assert.Nil(t, err)
+		assert.NotNil(t, img)
+		assert.False(t, timestamp.IsZero())
+		assert.Equal(t, uint8(index), img.Pix[0])
+
+		pool.Put(img)
+	}
+
+	_, _, err := pipeline.Next()
+	assert.Equal(t, io.EOF, err)
+
+	_, _, err = pipeline.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestFramePipelineShouldForwardReadError(t *testing.T) {
+	readErr := errors.New("read failed")
+
+	pool := newFrameImagePool(framePipelineDepth+1, 2, 2)
+	pipeline := newFramePipeline(mockFramesReader(2, readErr), options.NoDenoise, pool)
+	defer pipeline.Close()
+
+	for index := 0; index < 2; index += 1 {
+		img, _, err := pipeline.Next()
+		assert.Nil(t, err)
+
+		pool.Put(img)
+	}
+
+	img, _, err := pipeline.Next()
+	assert.Nil(t, img)
+	assert.ErrorIs(t, err, readErr)
+}
+
+func TestFramePipelineShouldCloseWhenNotConsumed(t *testing.T) {
+	pool := newFrameImagePool(framePipelineDepth, 2, 2)
+	pipeline := newFramePipeline(mockFramesReader(100, nil), options.NoDenoise, pool)
+
+	img, _, err := pipeline.Next()
+	assert.Nil(t, err)
+	assert.NotNil(t, img)
+
+	assert.NotPanics(t, func() {
+		pipeline.Close()
+		pipeline.Close()
+	})
+}
+
+func TestFramePipelineShouldUseTheFrameTimeIfAvailable(t *testing.T) {
+	frameTime := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	pool := newFrameImagePool(framePipelineDepth+1, 2, 2)
+	pipeline := newFramePipeline(func(buffer []byte) (time.Time, error) {
+		return frameTime, nil
+	}, options.NoDenoise, pool)
+
+	defer pipeline.Close()
+
+	_, timestamp, err := pipeline.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, frameTime, timestamp)
+}
+
+func mockFramesReader(count int, err error) frameReadFunc {
+	index := 0
+	return func(buffer []byte) (time.Time, error) {
+		if index >= count {
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			return time.Time{}, io.EOF
+		}
+
+		buffer[0] = uint8(index)
+		index += 1
+		return time.Time{}, nil
+	}
+}
@@ -0,0 +1,289 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
+)
+
+// The environment variable overriding the default directory of the cache store.
+const CacheStoreDirectoryEnv string = "VLD_CACHE_DIR"
+
+const cacheStoreDirectoryName string = "vld"
+
+// The content-addressed store of the pre-analyzed frames caches and analysis checkpoints shared between the output directories. The
+// entries are named after the video content fingerprint and the analysis options checksum, therefore the store can hold the analyses
+// of many videos created using different options.
+type CacheStore struct {
+	DirectoryPath string
+}
+
+// The pre-analyzed frames cache or analysis checkpoint stored in the cache store. The entries with a header which can not be decoded
+// are marked as corrupted and only the file information is available. The frames information is available only for the inspected
+// entries, because the frames are not decoded while listing the entries.
+type CacheStoreEntry struct {
+	Path       string
+	Header     frame.CacheHeader
+	Checkpoint bool
+	Corrupted  bool
+	Frames     int
+	FirstFrame int
+	LastFrame  int
+	Size       int64
+	LastUsed   time.Time
+}
+
+func NewCacheStore(directoryPath string) CacheStore {
+	return CacheStore{
+		DirectoryPath: directoryPath,
+	}
+}
+
+// Return the default directory of the cache store. The directory can be overridden with the environment variable, otherwise the user
+// cache directory (XDG_CACHE_HOME on Unix systems) is used.
+func GetDefaultCacheStoreDirectory() (string, error) {
+	if directoryPath, ok := os.LookupEnv(CacheStoreDirectoryEnv); ok && len(directoryPath) != 0 {
+		return directoryPath, nil
+	}
+
+	userCacheDirectoryPath, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("analyzer: failed to access the user cache directory: %w", err)
+	}
+
+	return path.Join(userCacheDirectoryPath, cacheStoreDirectoryName), nil
+}
+
+// Return the path of the store entry for the given video content fingerprint and options checksum.
+func (store CacheStore) EntryPath(fingerprint, checksum string, checkpoint bool) string {
+	extension := frameCollectionCacheFilename
+	if checkpoint {
+		extension = frameCollectionCheckpointFilename
+	}
+
+	return path.Join(store.DirectoryPath, fmt.Sprintf("%s-%s%s", fingerprint, checksum, extension))
+}
+
+// Return the path of the store entry for the video and the analysis options.
+func (store CacheStore) ResolveEntryPath(inputVideoPath string, o options.DetectorOptions, checkpoint bool) (string, error) {
+	return store.resolveEntryPath(newVideoFingerprint(inputVideoPath), o, checkpoint)
+}
+
+func (store CacheStore) resolveEntryPath(fingerprint *videoFingerprint, o options.DetectorOptions, checkpoint bool) (string, error) {
+	optionsChecksum, err := options.CalculateChecksum(o)
+	if err != nil {
+		return "", fmt.Errorf("analyzer: failed to access the detector options checksum: %w", err)
+	}
+
+	value, err := fingerprint.Get()
+	if err != nil {
+		return "", err
+	}
+
+	return store.EntryPath(value, optionsChecksum, checkpoint), nil
+}
+
+// The content fingerprint of the input video computed on the first access and reused by the following cache lookups, because the
+// computation is reading the video file or accessing every image of the image sequence. The fingerprint is not available if the
+// input video path is empty.
+type videoFingerprint struct {
+	Path  string
+	Value string
+	Err   error
+	Once  sync.Once
+}
+
+func newVideoFingerprint(inputVideoPath string) *videoFingerprint {
+	return &videoFingerprint{
+		Path: inputVideoPath,
+	}
+}
+
+func (fingerprint *videoFingerprint) IsAvailable() bool {
+	return len(fingerprint.Path) != 0
+}
+
+func (fingerprint *videoFingerprint) Get() (string, error) {
+	fingerprint.Once.Do(func() {
+		if fingerprint.Value, fingerprint.Err = video.Fingerprint(fingerprint.Path); fingerprint.Err != nil {
+			fingerprint.Err = fmt.Errorf("analyzer: failed to access the video content fingerprint: %w", fingerprint.Err)
+		}
+	})
+
+	return fingerprint.Value, fingerprint.Err
+}
+
+// List all entries of the cache store ordered from the most recently used. The missing store directory is treated as an empty store.
+// Only the headers of the entries are decoded.
+func (store CacheStore) List() ([]CacheStoreEntry, error) {
+	directoryEntries, err := os.ReadDir(store.DirectoryPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []CacheStoreEntry{}, nil
+		}
+
+		return nil, fmt.Errorf("analyzer: failed to read the cache store directory: %w", err)
+	}
+
+	entries := make([]CacheStoreEntry, 0, len(directoryEntries))
+	for _, directoryEntry := range directoryEntries {
+		if directoryEntry.IsDir() {
+			continue
+		}
+
+		name := directoryEntry.Name()
+		checkpoint := strings.HasSuffix(name, frameCollectionCheckpointFilename)
+		if !checkpoint && !strings.HasSuffix(name, frameCollectionCacheFilename) {
+			continue
+		}
+
+		info, err := directoryEntry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("analyzer: failed to access the cache store entry information: %w", err)
+		}
+
+		entry := CacheStoreEntry{
+			Path:       path.Join(store.DirectoryPath, name),
+			Checkpoint: checkpoint,
+			Size:       info.Size(),
+			LastUsed:   info.ModTime(),
+		}
+
+		if err := readCacheStoreEntryHeader(&entry); err != nil {
+			entry.Corrupted = true
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+
+	return entries, nil
+}
+
+// List the entries of the cache store created for the given video including the frames information. The video is matched using the
+// content fingerprint. The entries with the frames which can not be imported are marked as corrupted.
+func (store CacheStore) Inspect(inputVideoPath string) ([]CacheStoreEntry, error) {
+	fingerprint, err := video.Fingerprint(inputVideoPath)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer: failed to access the video content fingerprint: %w", err)
+	}
+
+	entries, err := store.filter(func(entry CacheStoreEntry) bool {
+		return !entry.Corrupted && entry.Header.Fingerprint == fingerprint
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range entries {
+		if err := importCacheStoreEntryFrames(&entries[index]); err != nil {
+			entries[index].Corrupted = true
+		}
+	}
+
+	return entries, nil
+}
+
+// List the pre-analyzed frames caches of the cache store created using options with the given checksum.
+func (store CacheStore) FindByChecksum(checksum string) ([]CacheStoreEntry, error) {
+	return store.filter(func(entry CacheStoreEntry) bool {
+		return !entry.Corrupted && !entry.Checkpoint && entry.Header.Checksum == checksum
+	})
+}
+
+// Remove the entries of the cache store which were not used since the given time and the entries with a corrupted header. The removed
+// entries are returned.
+func (store CacheStore) Prune(olderThan time.Time) ([]CacheStoreEntry, error) {
+	entries, err := store.filter(func(entry CacheStoreEntry) bool {
+		return entry.Corrupted || entry.LastUsed.Before(olderThan)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("analyzer: failed to remove the cache store entry: %w", err)
+		}
+	}
+
+	return entries, nil
+}
+
+func (store CacheStore) filter(predicate func(CacheStoreEntry) bool) ([]CacheStoreEntry, error) {
+	entries, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]CacheStoreEntry, 0, len(entries))
+	for _, entry := range entries {
+		if predicate(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered, nil
+}
+
+func readCacheStoreEntryHeader(entry *CacheStoreEntry) error {
+	file, err := os.Open(entry.Path)
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to open the cache store entry: %w", err)
+	}
+
+	defer file.Close()
+
+	header, err := frame.PeekCacheHeader(file)
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to decode the cache store entry header: %w", err)
+	}
+
+	entry.Header = header
+	return nil
+}
+
+func importCacheStoreEntryFrames(entry *CacheStoreEntry) error {
+	file, err := os.Open(entry.Path)
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to open the cache store entry: %w", err)
+	}
+
+	defer file.Close()
+
+	importFrameCollection := frame.ImportCachedFrameCollection
+	if entry.Checkpoint {
+		importFrameCollection = frame.ImportAppendedFrameCollection
+	}
+
+	fc, _, err := importFrameCollection(file)
+	if err != nil {
+		return fmt.Errorf("analyzer: failed to import the cache store entry: %w", err)
+	}
+
+	entry.Frames = fc.Count()
+
+	if frames := fc.GetAll(); len(frames) != 0 {
+		entry.FirstFrame = frames[0].OrdinalNumber
+		entry.LastFrame = frames[len(frames)-1].OrdinalNumber
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package analyzer
+
+import (
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+func mockFrameCollection() frame.FrameCollection {
+	fc := frame.NewFrameCollection(2)
+	defer fc.Lock()
+
+	fc.Push(&frame.Frame{OrdinalNumber: 1, Timestamp: 0.0, Brightness: 0.5})
+	fc.Push(&frame.Frame{OrdinalNumber: 2, Timestamp: 0.5, Brightness: 0.25})
+
+	return fc
+}
+
+func mockVideoFile(t *testing.T, content string) string {
+	inputVideoPath := path.Join(t.TempDir(), "video.mp4")
+	assert.Nil(t, os.WriteFile(inputVideoPath, []byte(content), 0660))
+
+	return inputVideoPath
+}
+
+func TestCacheStoreShouldShareTheCacheBetweenOutputDirectories(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.CacheDirectoryPath = t.TempDir()
+
+	firstOutputDirPath, secondOutputDirPath := t.TempDir(), t.TempDir()
+
+	a := NewAnalyzer(inputVideoPath, firstOutputDirPath, o, p).(*analyzer)
+	assert.Nil(t, a.ExportPreanalyzedFrames(mockFrameCollection()))
+	assert.False(t, utils.FileExists(path.Join(firstOutputDirPath, frameCollectionCacheFilename)))
+
+	frames, status, err := ImportPreanalyzedFramesCache(secondOutputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheValid, status)
+	assert.Equal(t, 2, frames.Count())
+
+	frames, status, err = ImportPreanalyzedFramesCache(secondOutputDirPath, "", o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheValid, status)
+	assert.Equal(t, 2, frames.Count())
+
+	boundsOptions := o
+	boundsOptions.DetectionBoundsExpression = "0:0:10:10"
+
+	status, err = CheckPreanalyzedFramesCache(secondOutputDirPath, inputVideoPath, boundsOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheMissing, status)
+
+	otherInputVideoPath := mockVideoFile(t, "other video content")
+	b := NewAnalyzer(otherInputVideoPath, firstOutputDirPath, o, p).(*analyzer)
+	assert.Nil(t, b.ExportPreanalyzedFrames(mockFrameCollection()))
+
+	_, _, err = ImportPreanalyzedFramesCache(secondOutputDirPath, "", o)
+	assert.NotNil(t, err)
+
+	store := NewCacheStore(o.CacheDirectoryPath)
+
+	entries, err := store.List()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Zero(t, entries[0].Frames)
+
+	entries, err = store.Inspect(inputVideoPath)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 2, entries[0].Frames)
+	assert.Equal(t, 1, entries[0].FirstFrame)
+	assert.Equal(t, 2, entries[0].LastFrame)
+	assert.False(t, entries[0].Checkpoint)
+
+	assert.Nil(t, RemovePreanalyzedFramesCache(secondOutputDirPath, inputVideoPath, o))
+
+	entries, err = store.Inspect(inputVideoPath)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCacheStoreShouldMigrateTheOutputDirectoryCache(t *testing.T) {
+	var (
+		outputDirPath  string = t.TempDir()
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+	assert.Nil(t, a.ExportPreanalyzedFrames(mockFrameCollection()))
+	assert.True(t, utils.FileExists(path.Join(outputDirPath, frameCollectionCacheFilename)))
+
+	o.CacheDirectoryPath = t.TempDir()
+
+	frames, status, err := ImportPreanalyzedFramesCache(outputDirPath, inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheValid, status)
+
+	a = NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+	assert.Nil(t, a.ExportPreanalyzedFrames(frames))
+
+	entries, err := NewCacheStore(o.CacheDirectoryPath).Inspect(inputVideoPath)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCacheStoreShouldStoreTheCheckpoints(t *testing.T) {
+	var (
+		outputDirPath  string = t.TempDir()
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.ImportPreanalyzed = true
+	o.CacheDirectoryPath = t.TempDir()
+
+	a := NewAnalyzer(inputVideoPath, outputDirPath, o, p).(*analyzer)
+	assert.Nil(t, a.ExportAnalysisCheckpoint(mockFrameCollection().GetAll()))
+	assert.Nil(t, a.AppendAnalysisCheckpoint([]*frame.Frame{{OrdinalNumber: 3, Brightness: 0.125}}))
+	assert.False(t, utils.FileExists(path.Join(outputDirPath, frameCollectionCheckpointFilename)))
+
+	entries, err := NewCacheStore(o.CacheDirectoryPath).Inspect(inputVideoPath)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.True(t, entries[0].Checkpoint)
+	assert.Equal(t, 3, entries[0].Frames)
+	assert.Equal(t, 3, entries[0].LastFrame)
+
+	assert.Len(t, NewAnalyzer(inputVideoPath, t.TempDir(), o, p).(*analyzer).ImportAnalysisCheckpoint(0), 3)
+
+	assert.Nil(t, a.RemoveAnalysisCheckpoint())
+	assert.Nil(t, a.ImportAnalysisCheckpoint(0))
+}
+
+func TestCacheStoreShouldPruneUnusedAndCorruptedEntries(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.CacheDirectoryPath = t.TempDir()
+	store := NewCacheStore(o.CacheDirectoryPath)
+
+	entries, err := NewCacheStore(path.Join(o.CacheDirectoryPath, "missing")).List()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	a := NewAnalyzer(inputVideoPath, t.TempDir(), o, p).(*analyzer)
+	assert.Nil(t, a.ExportPreanalyzedFrames(mockFrameCollection()))
+
+	corruptedPath := path.Join(o.CacheDirectoryPath, "corrupted"+frameCollectionCacheFilename)
+	assert.Nil(t, os.WriteFile(corruptedPath, []byte("corrupted"), 0660))
+
+	unrelatedPath := path.Join(o.CacheDirectoryPath, "unrelated.txt")
+	assert.Nil(t, os.WriteFile(unrelatedPath, []byte("unrelated"), 0660))
+
+	entries, err = store.List()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+
+	removed, err := store.Prune(time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Len(t, removed, 1)
+	assert.True(t, removed[0].Corrupted)
+	assert.False(t, utils.FileExists(corruptedPath))
+	assert.True(t, utils.FileExists(unrelatedPath))
+
+	entries, err = store.List()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	usedTime := time.Now().Add(-48 * time.Hour)
+	assert.Nil(t, os.Chtimes(entries[0].Path, usedTime, usedTime))
+
+	_, status, err := ImportPreanalyzedFramesCache(t.TempDir(), inputVideoPath, o)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheValid, status)
+
+	removed, err = store.Prune(time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Empty(t, removed)
+
+	assert.Nil(t, os.Chtimes(entries[0].Path, usedTime, usedTime))
+
+	removed, err = store.Prune(time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Len(t, removed, 1)
+
+	entries, err = store.List()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCacheStoreShouldDecodeTheFramesOnlyWhileInspecting(t *testing.T) {
+	var (
+		inputVideoPath string = mockVideoFile(t, "video content")
+		o                     = options.GetDefaultDetectorOptions()
+		p                     = printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	)
+
+	o.CacheDirectoryPath = t.TempDir()
+	store := NewCacheStore(o.CacheDirectoryPath)
+
+	a := NewAnalyzer(inputVideoPath, t.TempDir(), o, p).(*analyzer)
+	assert.Nil(t, a.ExportPreanalyzedFrames(mockFrameCollection()))
+
+	entryPath, err := store.ResolveEntryPath(inputVideoPath, o, false)
+	assert.Nil(t, err)
+
+	info, err := os.Stat(entryPath)
+	assert.Nil(t, err)
+	assert.Nil(t, os.Truncate(entryPath, info.Size()-1))
+
+	entries, err := store.List()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.False(t, entries[0].Corrupted)
+
+	entries, err = store.Inspect(inputVideoPath)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.True(t, entries[0].Corrupted)
+}
+
+func TestGetDefaultCacheStoreDirectoryShouldRespectTheEnvironment(t *testing.T) {
+	t.Setenv(CacheStoreDirectoryEnv, "/tmp/vld-cache-store")
+
+	directoryPath, err := GetDefaultCacheStoreDirectory()
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/vld-cache-store", directoryPath)
+
+	t.Setenv(CacheStoreDirectoryEnv, "")
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+
+	directoryPath, err = GetDefaultCacheStoreDirectory()
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/xdg-cache/vld", directoryPath)
+}
+
+func TestVideoFingerprintShouldBeComputedOnce(t *testing.T) {
+	inputVideoPath := mockVideoFile(t, "video content")
+
+	fingerprint := newVideoFingerprint(inputVideoPath)
+	assert.True(t, fingerprint.IsAvailable())
+
+	expected, err := fingerprint.Get()
+	assert.Nil(t, err)
+
+	assert.Nil(t, os.WriteFile(inputVideoPath, []byte("modified video content"), 0660))
+
+	actual, err := fingerprint.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+
+	assert.False(t, newVideoFingerprint("").IsAvailable())
+}
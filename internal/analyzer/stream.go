@@ -7,7 +7,6 @@ import (
 	"io"
 	"time"
 
-	"github.com/Krzysztofz01/video-lightning-detector/internal/denoise"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
@@ -35,6 +34,9 @@ type StreamAnalyzer interface {
 	// access the input or output (bbox) dimensions via the boolean argument
 	PeekFrameImageDimensions(input bool) (int, int, error)
 
+	// Access the frame rate of the underlying video stream
+	PeekFramesPerSecond() (float64, error)
+
 	// Read the count of read frames
 	FrameCount() int
 
@@ -43,15 +45,17 @@ type StreamAnalyzer interface {
 }
 
 type streamAnalyzer struct {
-	StreamUrl         string
-	Options           options.StreamDetectorOptions
-	Printer           printer.Printer
-	FrameBuffer       utils.CircularBuffer[*timedFrame]
-	FrameImageBuffer  utils.CircularBuffer[*image.RGBA]
-	FrameImageCurrent *image.RGBA
-	VideoStream       video.VideoStream
-	FrameNumber       int
-	IsInitialized     bool
+	StreamUrl        string
+	Options          options.StreamDetectorOptions
+	Printer          printer.Printer
+	FrameBuffer      utils.CircularBuffer[*timedFrame]
+	FrameImageBuffer utils.CircularBuffer[*image.RGBA]
+	FrameImagePool   *frameImagePool
+	Pipeline         *framePipeline
+	VideoStream      video.VideoStream
+	FrameNumber      int
+	FirstFrameTime   time.Time
+	IsInitialized    bool
 }
 
 func (analyzer *streamAnalyzer) PeekFrame(index int) (*frame.Frame, time.Time, error) {
@@ -89,6 +93,14 @@ func (analyzer *streamAnalyzer) PeekFrameImageDimensions(input bool) (int, int,
 	return w, h, nil
 }
 
+func (analyzer *streamAnalyzer) PeekFramesPerSecond() (float64, error) {
+	if !analyzer.IsInitialized {
+		return 0, fmt.Errorf("analyzer: can not peek frame rate because the analyzer has not been initialzied")
+	}
+
+	return analyzer.VideoStream.FramesPerSecond(), nil
+}
+
 func (analyzer *streamAnalyzer) Initialize() error {
 	video, err := video.NewVideoStream(analyzer.StreamUrl)
 	if err != nil {
@@ -115,26 +127,38 @@ func (analyzer *streamAnalyzer) Initialize() error {
 	}
 
 	targetWidth, targetHeight := video.GetOutputDimensions()
-	frameCurrent := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
 
-	if err = video.SetFrameBuffer(frameCurrent.Pix); err != nil {
-		return fmt.Errorf("analyzer: failed to apply the given buffer as the video frame buffer: %w", err)
-	}
+	analyzer.initializeBuffers(targetWidth, targetHeight, func(buffer []byte) (time.Time, error) {
+		if err := video.ReadInto(buffer); err != nil {
+			return time.Time{}, err
+		}
 
-	capacity := utils.MaxInt(4, int(analyzer.Options.MovingMeanResolution))
-	frameImageBufferAlloc := make([]*image.RGBA, capacity, capacity)
-	for index := range frameImageBufferAlloc {
-		frameImageBufferAlloc[index] = image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
-	}
+		return video.FrameTime(), nil
+	})
 
-	analyzer.FrameBuffer = utils.NewCircularBuffer[*timedFrame](capacity)
-	analyzer.FrameImageBuffer = utils.NewSaturatedCircularBuffer[*image.RGBA](frameImageBufferAlloc)
-	analyzer.FrameImageCurrent = frameCurrent
 	analyzer.VideoStream = video
+	return nil
+}
+
+// Create the frames buffers, the frame images pool and the pipeline reading the frames using the given function.
+func (analyzer *streamAnalyzer) initializeBuffers(width, height int, read frameReadFunc) {
+	// NOTE: The frame images buffer is holding the images taken from the pool, therefore the pool must be able to
+	// provide the images for the whole buffer and the pipeline stages at the same time.
+	capacity := streamBufferCapacity(analyzer.Options)
+	pool := newFrameImagePool(capacity+framePipelineDepth, width, height)
+
+	analyzer.FrameBuffer = utils.NewCircularBuffer[*timedFrame](capacity)
+	analyzer.FrameImageBuffer = utils.NewCircularBuffer[*image.RGBA](capacity)
+	analyzer.FrameImagePool = pool
+	analyzer.Pipeline = newFramePipeline(read, analyzer.Options.Denoise, pool)
 	analyzer.FrameNumber = 1
 	analyzer.IsInitialized = true
+}
 
-	return nil
+// Return the count of the latest frames held by the stream analyzer. The frames must be available for the whole moving
+// statistics resolution and the whole classification window, because the detections are peeked from the window.
+func streamBufferCapacity(o options.StreamDetectorOptions) int {
+	return utils.MaxInt(utils.MaxInt(4, int(o.MovingMeanResolution)), o.ClassificationWindowSize)
 }
 
 func (analyzer *streamAnalyzer) Next() error {
@@ -144,9 +168,8 @@ func (analyzer *streamAnalyzer) Next() error {
 		}
 	}
 
-	// FIXME: The timestamp is dependent on the frame 'receive' and not 'creation' time which makes the process latency sensitive
-	timestamp := time.Now().UTC()
-	if err := analyzer.VideoStream.Read(); err != nil {
+	frameImageCurrent, timestamp, err := analyzer.Pipeline.Next()
+	if err != nil {
 		if errors.Is(err, io.EOF) {
 			return io.EOF
 		} else {
@@ -154,17 +177,11 @@ func (analyzer *streamAnalyzer) Next() error {
 		}
 	}
 
-	if analyzer.Options.Denoise != options.NoDenoise {
-		if err := denoise.Denoise(analyzer.FrameImageCurrent, analyzer.FrameImageCurrent, analyzer.Options.Denoise); err != nil {
-			return fmt.Errorf("analyzer: failed to apply denoise to the current frame image on the analyze stage: %w", err)
-		}
+	if analyzer.FrameNumber == 1 {
+		analyzer.FirstFrameTime = timestamp
 	}
 
-	var (
-		frameImagePrevious *image.RGBA = nil
-		err                error
-	)
-
+	var frameImagePrevious *image.RGBA = nil
 	if analyzer.FrameNumber > 1 {
 		if frameImagePrevious, err = analyzer.FrameImageBuffer.GetHead(0); err != nil {
 			return fmt.Errorf("analyzer: failed to access the previous frame image pointer from the buffer: %w", err)
@@ -172,15 +189,27 @@ func (analyzer *streamAnalyzer) Next() error {
 	}
 
 	f := &timedFrame{
-		Frame:     frame.CreateNewFrame(analyzer.FrameImageCurrent, frameImagePrevious, analyzer.FrameNumber, frame.BinaryThresholdParam),
+		Frame:     frame.CreateNewFrame(frameImageCurrent, frameImagePrevious, analyzer.FrameNumber, frame.BinaryThresholdParam),
 		Timestamp: timestamp,
 	}
 
+	f.Frame.Timestamp = timestamp.Sub(analyzer.FirstFrameTime).Seconds()
+
 	analyzer.FrameNumber += 1
 
 	analyzer.FrameBuffer.Push(f)
-	copy(analyzer.FrameImageBuffer.PushP().Pix, analyzer.FrameImageCurrent.Pix)
 
+	// NOTE: The oldest frame image is going to be overwritten, therefore it is returned to the pool to be reused by the pipeline
+	if analyzer.FrameImageBuffer.IsSaturated() {
+		frameImageDiscard, err := analyzer.FrameImageBuffer.GetTail(0)
+		if err != nil {
+			return fmt.Errorf("analyzer: failed to access the discard frame image pointer from the buffer: %w", err)
+		}
+
+		analyzer.FrameImagePool.Put(frameImageDiscard)
+	}
+
+	analyzer.FrameImageBuffer.Push(frameImageCurrent)
 	return nil
 }
 
@@ -193,15 +222,28 @@ func (analyzer *streamAnalyzer) FrameCount() int {
 }
 
 func (analyzer *streamAnalyzer) Close() error {
+	// NOTE: The stalled stream is blocking the read of the pipeline decode stage, therefore the stream is closed first only
+	// in order to interrupt the read. The closed stream is not reinitialized and the decode stage is finishing with io.EOF.
 	if analyzer.VideoStream != nil {
 		analyzer.VideoStream.Close()
+
+		if err := analyzer.VideoStream.LogError(); err != nil {
+			analyzer.Printer.Debug("Video stream log scanning failure: %s", err)
+		}
+
 		analyzer.VideoStream = nil
 	}
 
+	if analyzer.Pipeline != nil {
+		analyzer.Pipeline.Close()
+		analyzer.Pipeline = nil
+	}
+
 	analyzer.FrameBuffer = nil
 	analyzer.FrameImageBuffer = nil
-	analyzer.FrameImageCurrent = nil
+	analyzer.FrameImagePool = nil
 	analyzer.FrameNumber = 1
+	analyzer.FirstFrameTime = time.Time{}
 	analyzer.IsInitialized = false
 
 	return nil
@@ -209,14 +251,16 @@ func (analyzer *streamAnalyzer) Close() error {
 
 func NewStreamAnalyzer(inputVideoStream string, o options.StreamDetectorOptions, p printer.Printer) StreamAnalyzer {
 	return &streamAnalyzer{
-		StreamUrl:         inputVideoStream,
-		Options:           o,
-		Printer:           p,
-		FrameBuffer:       nil,
-		FrameImageBuffer:  nil,
-		FrameImageCurrent: nil,
-		VideoStream:       nil,
-		FrameNumber:       1,
-		IsInitialized:     false,
+		StreamUrl:        inputVideoStream,
+		Options:          o,
+		Printer:          p,
+		FrameBuffer:      nil,
+		FrameImageBuffer: nil,
+		FrameImagePool:   nil,
+		Pipeline:         nil,
+		VideoStream:      nil,
+		FrameNumber:      1,
+		FirstFrameTime:   time.Time{},
+		IsInitialized:    false,
 	}
 }
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
+)
+
+func TestStreamBufferCapacityShouldHoldTheWholeClassificationWindow(t *testing.T) {
+	cases := []struct {
+		MovingMeanResolution     int32
+		ClassificationWindowSize int
+		Expected                 int
+	}{
+		{0, 3, 4},
+		{0, 8, 8},
+		{50, 8, 50},
+	}
+
+	for _, c := range cases {
+		o := options.GetDefaultStreamDetectorOptions()
+		o.MovingMeanResolution = c.MovingMeanResolution
+		o.ClassificationWindowSize = c.ClassificationWindowSize
+
+		assert.Equal(t, c.Expected, streamBufferCapacity(o))
+	}
+}
+
+func TestStreamAnalyzerShouldHoldTheWholeClassificationWindow(t *testing.T) {
+	o := options.GetDefaultStreamDetectorOptions()
+	o.ClassificationWindowSize = 8
+	o.MovingMeanResolution = 0
+
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	analyzer := NewStreamAnalyzer("", o, p).(*streamAnalyzer)
+	analyzer.initializeBuffers(2, 2, mockFramesReader(20, nil))
+	defer analyzer.Close()
+
+	for index := 0; index < 20; index += 1 {
+		assert.Nil(t, analyzer.Next())
+
+		for peekIndex := 0; peekIndex < o.ClassificationWindowSize && peekIndex <= index; peekIndex += 1 {
+			f, _, err := analyzer.PeekFrame(peekIndex)
+			assert.Nil(t, err)
+			assert.Equal(t, index+1-peekIndex, f.OrdinalNumber)
+
+			img, err := analyzer.PeekFrameImage(peekIndex)
+			assert.Nil(t, err)
+			assert.Equal(t, uint8(index-peekIndex), img.Pix[0])
+		}
+	}
+
+	assert.Equal(t, io.EOF, analyzer.Next())
+}
+
+func TestStreamAnalyzerShouldCloseTheStalledStream(t *testing.T) {
+	// NOTE: The printer package dependency is starting a goroutine on the package initialization
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	stream := &stalledVideoStream{Reading: make(chan struct{}), Closed: make(chan struct{})}
+
+	analyzer := NewStreamAnalyzer("", options.GetDefaultStreamDetectorOptions(), p).(*streamAnalyzer)
+	analyzer.initializeBuffers(2, 2, func(buffer []byte) (time.Time, error) {
+		return time.Time{}, stream.ReadInto(buffer)
+	})
+
+	analyzer.VideoStream = stream
+
+	<-stream.Reading
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		analyzer.Close()
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream analyzer close is blocked by the stalled stream")
+	}
+}
+
+// Mock video stream which is blocking the read until the stream is closed.
+type stalledVideoStream struct {
+	video.VideoStream
+	Reading chan struct{}
+	Closed  chan struct{}
+}
+
+func (v *stalledVideoStream) ReadInto(buffer []byte) error {
+	close(v.Reading)
+
+	<-v.Closed
+	return io.ErrClosedPipe
+}
+
+func (v *stalledVideoStream) Close() {
+	close(v.Closed)
+}
+
+func (v *stalledVideoStream) LogError() error {
+	return nil
+}
@@ -0,0 +1,244 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/analyzer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/export"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// Detector instance that is able to perform a search after lightning strikes on all video files of a directory.
+type BatchDetector interface {
+	Run(inputDirectoryPath, outputDirectoryPath string, ctx context.Context) error
+}
+
+type batchDetector struct {
+	Options      options.DetectorOptions
+	BatchOptions options.BatchOptions
+	Printer      printer.Printer
+	Checksum     string
+}
+
+// Create a new batch video lightning detector instance with the specified detector options used for each video and the batch options.
+func CreateBatchDetector(printer printer.Printer, o options.DetectorOptions, batchOptions options.BatchOptions) (BatchDetector, error) {
+	if printer == nil {
+		return nil, errors.New("detector: invalid nil reference renderer provided")
+	}
+
+	if ok, msg := o.AreValid(); !ok {
+		return nil, fmt.Errorf("detector: invalid options %s", msg)
+	}
+
+	if ok, msg := batchOptions.AreValid(); !ok {
+		return nil, fmt.Errorf("detector: invalid batch options %s", msg)
+	}
+
+	// NOTE: The preanalyzed frames cache is used to recognize the already processed videos, therefore it is always created
+	o.ImportPreanalyzed = true
+
+	checksum, err := options.CalculateDetectionChecksum(o)
+	if err != nil {
+		return nil, fmt.Errorf("detector: failed to calculate the detection options checksum: %w", err)
+	}
+
+	printer.Debug("Batch detector create with options %+v and batch options %+v", o, batchOptions)
+
+	return &batchDetector{
+		Options:      o,
+		BatchOptions: batchOptions,
+		Printer:      printer,
+		Checksum:     checksum,
+	}, nil
+}
+
+// Perform a lightning detection on the video files of the input directory. The results of each video are stored in a separate
+// subdirectory of the output directory and the summary index of all videos is stored in the output directory. The videos which
+// were already processed with the same options are skipped.
+func (batch *batchDetector) Run(inputDirectoryPath, outputDirectoryPath string, ctx context.Context) error {
+	runTime := time.Now()
+	batch.Printer.InfoA("Starting the batch lightning hunt.")
+
+	files, err := utils.FindFiles(inputDirectoryPath, batch.BatchOptions.IncludePatterns, batch.BatchOptions.ExcludePatterns, batch.BatchOptions.Recursive, outputDirectoryPath)
+	if err != nil {
+		return fmt.Errorf("detector: failed to find the batch video files: %w", err)
+	}
+
+	if len(files) == 0 {
+		batch.Printer.Warning("No video files matching the patterns found in the input directory.")
+		return nil
+	}
+
+	batch.Printer.Info("About to process %d video files.", len(files))
+
+	previousEntries := make(map[string]export.BatchSummaryEntry)
+	if entries, err := export.ImportBatchSummary(outputDirectoryPath); err != nil {
+		batch.Printer.Warning("Failed to import the previous batch summary. All video files will be processed.")
+		batch.Printer.Debug("Batch summary import failure: %s", err)
+	} else {
+		for _, entry := range entries {
+			previousEntries[entry.InputPath] = entry
+		}
+	}
+
+	var (
+		entries   []export.BatchSummaryEntry = make([]export.BatchSummaryEntry, len(files))
+		semaphore chan struct{}              = make(chan struct{}, batch.BatchOptions.Concurrency)
+		wg        sync.WaitGroup
+	)
+
+scheduling:
+	for index, file := range files {
+		select {
+		case <-ctx.Done():
+			batch.Printer.InfoA("Stopping the batch lightning hunt.")
+			break scheduling
+		case semaphore <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(index int, file string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			batch.Printer.Info("Video: [%d/%d]. Processing: %s", index+1, len(files), file)
+
+			entries[index] = batch.ProcessVideo(ctx, inputDirectoryPath, outputDirectoryPath, file, previousEntries)
+
+			batch.Printer.Info("Video: [%d/%d]. Finished: %s Status: %s Detections: %d Events: %d", index+1, len(files), file, entries[index].Status, entries[index].Detections, entries[index].Events)
+		}(index, file)
+	}
+
+	wg.Wait()
+
+	summary := make([]export.BatchSummaryEntry, 0, len(files))
+	for index, entry := range entries {
+		if len(entry.Status) == 0 {
+			// NOTE: The previous results of the videos not processed due to the interruption are preserved
+			if previous, ok := previousEntries[files[index]]; ok {
+				summary = append(summary, previous)
+			}
+
+			continue
+		}
+
+		summary = append(summary, entry)
+	}
+
+	if err := export.ExportBatchSummary(outputDirectoryPath, summary); err != nil {
+		return fmt.Errorf("detector: failed to export the batch summary: %w", err)
+	}
+
+	tableBatchSummary(batch.Printer, summary)
+
+	batch.Printer.InfoA("Batch lightning hunting took: %s", time.Since(runTime))
+	return nil
+}
+
+// Helper function used to perform the lightning detection on a single video of the batch. The video is skipped if it is present in the
+// previous batch summary with the same detection options checksum and the preanalyzed frames cache of the video is matching the current
+// options and the video content. If only the detection options changed, the video is processed again using the cached frames.
+func (batch *batchDetector) ProcessVideo(ctx context.Context, inputDirectoryPath, outputDirectoryPath, file string, previousEntries map[string]export.BatchSummaryEntry) export.BatchSummaryEntry {
+	var (
+		inputVideoPath           string                   = filepath.Join(inputDirectoryPath, filepath.FromSlash(file))
+		videoOutputDirectory     string                   = batchOutputDirectory(file)
+		videoOutputDirectoryPath string                   = filepath.Join(outputDirectoryPath, filepath.FromSlash(videoOutputDirectory))
+		entry                    export.BatchSummaryEntry = export.BatchSummaryEntry{InputPath: file, OutputDirectory: videoOutputDirectory, Checksum: batch.Checksum}
+	)
+
+	if previous, ok := previousEntries[file]; ok && previous.Checksum == batch.Checksum && (previous.Status == export.BatchProcessed || previous.Status == export.BatchSkipped) {
+		if valid, err := analyzer.IsPreanalyzedFramesCacheValid(videoOutputDirectoryPath, inputVideoPath, batch.Options); err != nil {
+			batch.Printer.Debug("Preanalyzed frames cache check failure for %s: %s", file, err)
+		} else if valid {
+			previous.Status = export.BatchSkipped
+			previous.OutputDirectory = videoOutputDirectory
+			return previous
+		}
+	}
+
+	// NOTE: The progress bars of concurrently processed videos can not be rendered together, therefore only the warnings and errors are printed
+	videoPrinter := batch.Printer
+	if batch.BatchOptions.Concurrency > 1 {
+		videoPrinter = printer.NewPrinter(printer.PrinterConfig{
+			UseColor:     true,
+			LogLevel:     options.Quiet,
+			OutStream:    os.Stdout,
+			ParsableMode: false,
+		})
+	}
+
+	videoDetector := &detector{
+		options: batch.Options.Clone(),
+		printer: videoPrinter,
+	}
+
+	videoTime := time.Now()
+	summary, err := videoDetector.Run(inputVideoPath, videoOutputDirectoryPath, ctx)
+	entry.Duration = time.Since(videoTime).Seconds()
+
+	if ctx.Err() != nil {
+		// NOTE: The partially analyzed frames are not stored in the cache, but in the checkpoint which is resumed on the next run
+		entry.Status = export.BatchInterrupted
+		return entry
+	}
+
+	if err != nil {
+		batch.Printer.Error("Failed to process the video %s: %s", file, err)
+
+		entry.Status = export.BatchFailed
+		entry.Error = err.Error()
+		return entry
+	}
+
+	entry.Status = export.BatchProcessed
+	entry.Frames = summary.Frames
+	entry.Detections = summary.Detections
+	entry.Events = summary.Events
+	return entry
+}
+
+// Get the slash separated output subdirectory of the video specified by the path relative to the input directory. The extension is
+// preserved as a part of the directory name in order to distinguish videos with the same name and different container formats.
+func batchOutputDirectory(file string) string {
+	extension := path.Ext(file)
+	if len(extension) == 0 {
+		return file
+	}
+
+	return strings.TrimSuffix(file, extension) + "-" + strings.TrimPrefix(extension, ".")
+}
+
+func tableBatchSummary(p printer.Printer, entries []export.BatchSummaryEntry) {
+	counts := make(map[export.BatchStatus]int)
+	detections, events := 0, 0
+
+	for _, entry := range entries {
+		counts[entry.Status] += 1
+		detections += entry.Detections
+		events += entry.Events
+	}
+
+	if counts[export.BatchFailed] > 0 {
+		p.Warning("Failed to process %d video files. See the batch summary for details.", counts[export.BatchFailed])
+	}
+
+	p.Table([][]string{
+		{"Processed videos", strconv.Itoa(counts[export.BatchProcessed])},
+		{"Skipped videos", strconv.Itoa(counts[export.BatchSkipped])},
+		{"Failed videos", strconv.Itoa(counts[export.BatchFailed])},
+		{"Interrupted videos", strconv.Itoa(counts[export.BatchInterrupted])},
+		{"Detections", strconv.Itoa(detections)},
+		{"Strike events", strconv.Itoa(events)},
+	})
+}
@@ -0,0 +1,92 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/export"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
+)
+
+func TestBatchOutputDirectoryShouldPreserveTheExtension(t *testing.T) {
+	cases := map[string]string{
+		"clip.mp4":           "clip-mp4",
+		"clip.MOV":           "clip-MOV",
+		"nested/dir/a.b.mkv": "nested/dir/a.b-mkv",
+		"noextension":        "noextension",
+	}
+
+	for file, expected := range cases {
+		assert.Equal(t, expected, batchOutputDirectory(file))
+	}
+}
+
+func TestCreateBatchDetectorShouldReturnErrorForInvalidParams(t *testing.T) {
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	_, err := CreateBatchDetector(nil, options.GetDefaultDetectorOptions(), options.GetDefaultBatchOptions())
+	assert.NotNil(t, err)
+
+	batchOptions := options.GetDefaultBatchOptions()
+	batchOptions.Concurrency = 0
+	_, err = CreateBatchDetector(p, options.GetDefaultDetectorOptions(), batchOptions)
+	assert.NotNil(t, err)
+
+	_, err = CreateBatchDetector(p, options.GetDefaultDetectorOptions(), options.GetDefaultBatchOptions())
+	assert.Nil(t, err)
+}
+
+func TestBatchProcessVideoShouldSkipOnlyTheVideosProcessedWithTheSameDetectionOptions(t *testing.T) {
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+	inputDirectoryPath, outputDirectoryPath := t.TempDir(), t.TempDir()
+
+	videoPath := filepath.Join(inputDirectoryPath, "clip.mp4")
+	assert.Nil(t, os.WriteFile(videoPath, []byte("clip"), 0660))
+
+	o := options.GetDefaultDetectorOptions()
+	o.AutoThresholds = true
+
+	optionsChecksum, err := options.CalculateChecksum(o)
+	assert.Nil(t, err)
+
+	fingerprint, err := video.Fingerprint(videoPath)
+	assert.Nil(t, err)
+
+	fc, _ := mockTuneFrameCollection()
+	file := new(bytes.Buffer)
+	assert.Nil(t, frame.ExportCachedFrameCollection(file, fc, optionsChecksum, fingerprint))
+
+	videoOutputDirectoryPath := filepath.Join(outputDirectoryPath, batchOutputDirectory("clip.mp4"))
+	assert.Nil(t, os.MkdirAll(videoOutputDirectoryPath, 0775))
+	assert.Nil(t, os.WriteFile(filepath.Join(videoOutputDirectoryPath, ".vld-cache"), file.Bytes(), 0660))
+
+	bd, err := CreateBatchDetector(p, o, options.GetDefaultBatchOptions())
+	assert.Nil(t, err)
+
+	batch := bd.(*batchDetector)
+	previous := export.BatchSummaryEntry{InputPath: "clip.mp4", Status: export.BatchProcessed, Detections: 6, Checksum: batch.Checksum}
+
+	entry := batch.ProcessVideo(context.Background(), inputDirectoryPath, outputDirectoryPath, "clip.mp4", map[string]export.BatchSummaryEntry{"clip.mp4": previous})
+	assert.Equal(t, export.BatchSkipped, entry.Status)
+	assert.Equal(t, 6, entry.Detections)
+
+	o.AutoThresholdPercentile = 90
+	bd, err = CreateBatchDetector(p, o, options.GetDefaultBatchOptions())
+	assert.Nil(t, err)
+
+	batch = bd.(*batchDetector)
+	assert.NotEqual(t, previous.Checksum, batch.Checksum)
+
+	entry = batch.ProcessVideo(context.Background(), inputDirectoryPath, outputDirectoryPath, "clip.mp4", map[string]export.BatchSummaryEntry{"clip.mp4": previous})
+	assert.NotEqual(t, export.BatchSkipped, entry.Status)
+	assert.Equal(t, batch.Checksum, entry.Checksum)
+}
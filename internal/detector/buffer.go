@@ -2,6 +2,7 @@ package detector
 
 import (
 	"fmt"
+	"math"
 	"slices"
 
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
@@ -12,16 +13,14 @@ import (
 // TODO: Diagnostic classification logging via printer
 // TODO: Implement more tests
 
-const (
-	ClassificationRingQueueSize int = 4
-)
-
-type DetectionStrategy int
+type DetectionStrategy = options.DetectionStrategy
 
 const (
-	AboveMovingMeanAllWeights DetectionStrategy = iota
-	AboveGlobalMeanAllWeights
-	AboveZeroAllWeights
+	AboveMovingMeanAllWeights   = options.AboveMovingMeanAllWeights
+	AboveGlobalMeanAllWeights   = options.AboveGlobalMeanAllWeights
+	AboveZeroAllWeights         = options.AboveZeroAllWeights
+	AboveMovingZScoreAllWeights = options.AboveMovingZScoreAllWeights
+	AboveMovingMedianAllWeights = options.AboveMovingMedianAllWeights
 )
 
 type DiscreteDetectionBuffer interface {
@@ -31,6 +30,7 @@ type DiscreteDetectionBuffer interface {
 
 type discreteDetectionBuffer struct {
 	Classifier                  BufferClassifier
+	WindowSize                  int
 	ClassificationQueue         []detectionBufferElement
 	DetectionSet                map[int]bool
 	ClassificationIndexPreAlloc []int
@@ -44,7 +44,7 @@ func (buffer *discreteDetectionBuffer) Push(f *frame.Frame, s statistics.Descrip
 
 	element := buffer.Classifier.CreateElement(f, s)
 
-	if len(buffer.ClassificationQueue) < ClassificationRingQueueSize {
+	if len(buffer.ClassificationQueue) < buffer.WindowSize {
 		buffer.ClassificationQueue = append(buffer.ClassificationQueue, element)
 	} else {
 		buffer.ClassificationQueue = append(buffer.ClassificationQueue[1:], element)
@@ -73,17 +73,16 @@ func (buffer *discreteDetectionBuffer) ResolveIndexes() []int {
 }
 
 func NewDiscreteDetectionBuffer(opt options.DetectorOptions, s DetectionStrategy) DiscreteDetectionBuffer {
-	switch s {
-	case AboveMovingMeanAllWeights, AboveGlobalMeanAllWeights, AboveZeroAllWeights:
-	default:
+	if !options.IsValidDetectionStrategy(s) {
 		panic("detector: invalid detection strategy specified")
 	}
 
 	return &discreteDetectionBuffer{
 		Classifier:                  createBufferClassifier(opt, s),
-		ClassificationQueue:         make([]detectionBufferElement, 0, ClassificationRingQueueSize),
+		WindowSize:                  opt.ClassificationWindowSize,
+		ClassificationQueue:         make([]detectionBufferElement, 0, opt.ClassificationWindowSize),
 		DetectionSet:                make(map[int]bool, 0),
-		ClassificationIndexPreAlloc: make([]int, 0, ClassificationRingQueueSize),
+		ClassificationIndexPreAlloc: make([]int, 0, opt.ClassificationWindowSize),
 	}
 }
 
@@ -93,6 +92,7 @@ type ContinuousDetectionBuffer interface {
 
 type continuousDetectionBuffer struct {
 	Classifier                  BufferClassifier
+	WindowSize                  int
 	ClassificationQueue         []detectionBufferElement
 	ClassificationIndexPreAlloc []int
 }
@@ -105,7 +105,7 @@ func (buffer *continuousDetectionBuffer) PushAndResolveIndexes(f *frame.Frame, s
 
 	element := buffer.Classifier.CreateElement(f, s)
 
-	if len(buffer.ClassificationQueue) < ClassificationRingQueueSize {
+	if len(buffer.ClassificationQueue) < buffer.WindowSize {
 		buffer.ClassificationQueue = append(buffer.ClassificationQueue, element)
 	} else {
 		buffer.ClassificationQueue = append(buffer.ClassificationQueue[1:], element)
@@ -125,8 +125,9 @@ func (buffer *continuousDetectionBuffer) PushAndResolveIndexes(f *frame.Frame, s
 func NewContinuousDetectionBuffer(opt options.StreamDetectorOptions, s DetectionStrategy) ContinuousDetectionBuffer {
 	return &continuousDetectionBuffer{
 		Classifier:                  createBufferClassifier(opt, s),
-		ClassificationQueue:         make([]detectionBufferElement, 0, ClassificationRingQueueSize),
-		ClassificationIndexPreAlloc: make([]int, 0, ClassificationRingQueueSize),
+		WindowSize:                  opt.ClassificationWindowSize,
+		ClassificationQueue:         make([]detectionBufferElement, 0, opt.ClassificationWindowSize),
+		ClassificationIndexPreAlloc: make([]int, 0, opt.ClassificationWindowSize),
 	}
 }
 
@@ -140,6 +141,18 @@ type bufferClassifier struct {
 	BrightnessDetectionThreshold                float64
 	ColorDifferenceDetectionThreshold           float64
 	BinaryThresholdDifferenceDetectionThreshold float64
+	BrightnessDetectionZScore                   float64
+	ColorDifferenceDetectionZScore              float64
+	BinaryThresholdDifferenceDetectionZScore    float64
+	VotingRule                                  options.VotingRule
+	VotingRuleK                                 int
+	BrightnessVotingWeight                      float64
+	ColorDifferenceVotingWeight                 float64
+	BinaryThresholdDifferenceVotingWeight       float64
+	VotingScoreThreshold                        float64
+	WindowSize                                  int
+	MaxGap                                      int
+	VotesPreAlloc                               []bool
 }
 
 func (classifier *bufferClassifier) CreateElement(f *frame.Frame, s statistics.DescriptiveStatisticsEntry) detectionBufferElement {
@@ -163,6 +176,14 @@ func (classifier *bufferClassifier) CreateElement(f *frame.Frame, s statistics.D
 		cl.BrightnessClassified = f.Brightness >= classifier.BrightnessDetectionThreshold
 		cl.ColorDifferenceClassified = f.ColorDifference >= classifier.ColorDifferenceDetectionThreshold
 		cl.BinaryThresholdDifferenceClassified = f.BinaryThresholdDifference >= classifier.BinaryThresholdDifferenceDetectionThreshold
+	case AboveMovingMedianAllWeights:
+		cl.BrightnessClassified = f.Brightness >= classifier.BrightnessDetectionThreshold+s.BrightnessMovingMedianAtPoint
+		cl.ColorDifferenceClassified = f.ColorDifference >= classifier.ColorDifferenceDetectionThreshold+s.ColorDifferenceMovingMedianAtPoint
+		cl.BinaryThresholdDifferenceClassified = f.BinaryThresholdDifference >= classifier.BinaryThresholdDifferenceDetectionThreshold+s.BinaryThresholdDifferenceMovingMedianAtPoint
+	case AboveMovingZScoreAllWeights:
+		cl.BrightnessClassified = zScore(f.Brightness, s.BrightnessMovingMeanAtPoint, s.BrightnessMovingStdDevAtPoint) >= classifier.BrightnessDetectionZScore
+		cl.ColorDifferenceClassified = zScore(f.ColorDifference, s.ColorDifferenceMovingMeanAtPoint, s.ColorDifferenceMovingStdDevAtPoint) >= classifier.ColorDifferenceDetectionZScore
+		cl.BinaryThresholdDifferenceClassified = zScore(f.BinaryThresholdDifference, s.BinaryThresholdDifferenceMovingMeanAtPoint, s.BinaryThresholdDifferenceMovingStdDevAtPoint) >= classifier.BinaryThresholdDifferenceDetectionZScore
 	default:
 		panic("detector: invalid detection strategy specified")
 	}
@@ -170,12 +191,15 @@ func (classifier *bufferClassifier) CreateElement(f *frame.Frame, s statistics.D
 	return cl
 }
 
+// Resolve the indexes of the detected elements in the queue. The not classified elements are bridged if they are placed
+// between two classified elements separated by at most the max gap amount of elements. The bridging is only performed
+// when the queue is filled up to the window size.
 func (classifier *bufferClassifier) ResolveElement(queue []detectionBufferElement, classificationResult []int) []int {
 	classificationResult = classificationResult[:0]
 
-	if len(queue) < ClassificationRingQueueSize {
+	if len(queue) < classifier.WindowSize {
 		for _, e := range queue {
-			if e.BrightnessClassified && e.ColorDifferenceClassified && e.BinaryThresholdDifferenceClassified {
+			if classifier.Vote(e) {
 				classificationResult = append(classificationResult, e.Index)
 			}
 		}
@@ -183,34 +207,86 @@ func (classifier *bufferClassifier) ResolveElement(queue []detectionBufferElemen
 		return classificationResult
 	}
 
-	var (
-		c0  = queue[0]
-		c1  = queue[1]
-		c2  = queue[2]
-		c3  = queue[3]
-		wc0 = c0.BrightnessClassified && c0.ColorDifferenceClassified && c0.BinaryThresholdDifferenceClassified
-		wc1 = c1.BrightnessClassified && c1.ColorDifferenceClassified && c1.BinaryThresholdDifferenceClassified
-		wc2 = c2.BrightnessClassified && c2.ColorDifferenceClassified && c2.BinaryThresholdDifferenceClassified
-		wc3 = c3.BrightnessClassified && c3.ColorDifferenceClassified && c3.BinaryThresholdDifferenceClassified
-	)
-
-	if wc0 {
-		classificationResult = append(classificationResult, c0.Index)
+	votes := classifier.VotesPreAlloc[:0]
+	for _, e := range queue {
+		votes = append(votes, classifier.Vote(e))
 	}
 
-	if wc1 || (wc0 && wc3) || (wc0 && wc2) {
-		classificationResult = append(classificationResult, c1.Index)
+	classifier.VotesPreAlloc = votes
+
+	previous := -1
+	for index, e := range queue {
+		if votes[index] {
+			classificationResult = append(classificationResult, e.Index)
+			previous = index
+			continue
+		}
+
+		if previous == -1 {
+			continue
+		}
+
+		next := index + 1
+		for next < len(votes) && !votes[next] {
+			next += 1
+		}
+
+		if next < len(votes) && next-previous-1 <= classifier.MaxGap {
+			classificationResult = append(classificationResult, e.Index)
+		}
 	}
 
-	if wc2 || (wc1 && wc3) || (wc0 && wc3) {
-		classificationResult = append(classificationResult, c2.Index)
+	return classificationResult
+}
+
+// Determine if the element is classified as a detection according to the voting rule applied to the classified weights.
+func (classifier *bufferClassifier) Vote(e detectionBufferElement) bool {
+	switch classifier.VotingRule {
+	case options.AllOfVoting:
+		return e.BrightnessClassified && e.ColorDifferenceClassified && e.BinaryThresholdDifferenceClassified
+	case options.AnyOfVoting:
+		return e.BrightnessClassified || e.ColorDifferenceClassified || e.BinaryThresholdDifferenceClassified
+	case options.KOfNVoting:
+		votes := 0
+		for _, classified := range []bool{e.BrightnessClassified, e.ColorDifferenceClassified, e.BinaryThresholdDifferenceClassified} {
+			if classified {
+				votes += 1
+			}
+		}
+
+		return votes >= classifier.VotingRuleK
+	case options.WeightedVoting:
+		score := 0.0
+		if e.BrightnessClassified {
+			score += classifier.BrightnessVotingWeight
+		}
+
+		if e.ColorDifferenceClassified {
+			score += classifier.ColorDifferenceVotingWeight
+		}
+
+		if e.BinaryThresholdDifferenceClassified {
+			score += classifier.BinaryThresholdDifferenceVotingWeight
+		}
+
+		return score >= classifier.VotingScoreThreshold
+	default:
+		panic("detector: invalid voting rule specified")
 	}
+}
 
-	if wc3 {
-		classificationResult = append(classificationResult, c3.Index)
+// Calculate the standard score of the value. A value above the mean with no deviation is treated as an infinite
+// score and a value not above the mean with no deviation is treated as a zero score.
+func zScore(value, mean, stdDev float64) float64 {
+	if stdDev <= 0 {
+		if value > mean {
+			return math.Inf(1)
+		}
+
+		return 0
 	}
 
-	return classificationResult
+	return (value - mean) / stdDev
 }
 
 type detectorOptionsConstraint interface {
@@ -218,9 +294,7 @@ type detectorOptionsConstraint interface {
 }
 
 func createBufferClassifier[TOptions detectorOptionsConstraint](opt TOptions, s DetectionStrategy) BufferClassifier {
-	switch s {
-	case AboveMovingMeanAllWeights, AboveGlobalMeanAllWeights, AboveZeroAllWeights:
-	default:
+	if !options.IsValidDetectionStrategy(s) {
 		panic("detector: invalid detection strategy specified")
 	}
 
@@ -232,6 +306,18 @@ func createBufferClassifier[TOptions detectorOptionsConstraint](opt TOptions, s
 				BrightnessDetectionThreshold:                o.BrightnessDetectionThreshold,
 				ColorDifferenceDetectionThreshold:           o.ColorDifferenceDetectionThreshold,
 				BinaryThresholdDifferenceDetectionThreshold: o.BinaryThresholdDifferenceDetectionThreshold,
+				BrightnessDetectionZScore:                   o.BrightnessDetectionZScore,
+				ColorDifferenceDetectionZScore:              o.ColorDifferenceDetectionZScore,
+				BinaryThresholdDifferenceDetectionZScore:    o.BinaryThresholdDifferenceDetectionZScore,
+				VotingRule:                                  o.VotingRule,
+				VotingRuleK:                                 o.VotingRuleK,
+				BrightnessVotingWeight:                      o.BrightnessVotingWeight,
+				ColorDifferenceVotingWeight:                 o.ColorDifferenceVotingWeight,
+				BinaryThresholdDifferenceVotingWeight:       o.BinaryThresholdDifferenceVotingWeight,
+				VotingScoreThreshold:                        o.VotingScoreThreshold,
+				WindowSize:                                  o.ClassificationWindowSize,
+				MaxGap:                                      o.ClassificationMaxGap,
+				VotesPreAlloc:                               make([]bool, 0, o.ClassificationWindowSize),
 			}
 		}
 	case options.StreamDetectorOptions:
@@ -241,6 +327,18 @@ func createBufferClassifier[TOptions detectorOptionsConstraint](opt TOptions, s
 				BrightnessDetectionThreshold:                o.BrightnessDetectionThreshold,
 				ColorDifferenceDetectionThreshold:           o.ColorDifferenceDetectionThreshold,
 				BinaryThresholdDifferenceDetectionThreshold: o.BinaryThresholdDifferenceDetectionThreshold,
+				BrightnessDetectionZScore:                   o.BrightnessDetectionZScore,
+				ColorDifferenceDetectionZScore:              o.ColorDifferenceDetectionZScore,
+				BinaryThresholdDifferenceDetectionZScore:    o.BinaryThresholdDifferenceDetectionZScore,
+				VotingRule:                                  o.VotingRule,
+				VotingRuleK:                                 o.VotingRuleK,
+				BrightnessVotingWeight:                      o.BrightnessVotingWeight,
+				ColorDifferenceVotingWeight:                 o.ColorDifferenceVotingWeight,
+				BinaryThresholdDifferenceVotingWeight:       o.BinaryThresholdDifferenceVotingWeight,
+				VotingScoreThreshold:                        o.VotingScoreThreshold,
+				WindowSize:                                  o.ClassificationWindowSize,
+				MaxGap:                                      o.ClassificationMaxGap,
+				VotesPreAlloc:                               make([]bool, 0, o.ClassificationWindowSize),
 			}
 		}
 	default:
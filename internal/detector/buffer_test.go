@@ -13,10 +13,12 @@ import (
 
 func TestDiscreteDetectionBufferShouldCreate(t *testing.T) {
 	cases := map[DetectionStrategy]bool{
-		AboveMovingMeanAllWeights: true,
-		AboveGlobalMeanAllWeights: true,
-		AboveZeroAllWeights:       true,
-		-1:                        false,
+		AboveMovingMeanAllWeights:   true,
+		AboveGlobalMeanAllWeights:   true,
+		AboveZeroAllWeights:         true,
+		AboveMovingZScoreAllWeights: true,
+		AboveMovingMedianAllWeights: true,
+		-1:                          false,
 	}
 
 	for strategy, validStrategy := range cases {
@@ -145,12 +147,145 @@ func TestDiscreteDetectionBufferCorrectlyResolveAppendedValues(t *testing.T) {
 	}
 }
 
+func TestDiscreteDetectionBufferCorrectlyResolveAppendedValuesWithCustomWindow(t *testing.T) {
+	var (
+		options    options.DetectorOptions = options.GetDefaultDetectorOptions()
+		statistics statistics.DescriptiveStatisticsEntry
+	)
+
+	options.BrightnessDetectionThreshold = 0.5
+	options.ColorDifferenceDetectionThreshold = 0.5
+	options.BinaryThresholdDifferenceDetectionThreshold = 0.5
+	options.ClassificationWindowSize = 6
+	options.ClassificationMaxGap = 3
+
+	cases := []struct {
+		Frames   []bool
+		Expected []int
+	}{
+		{[]bool{false, false, false, false, false, false}, []int{}},
+		{[]bool{true, false, false, false, true, false}, []int{0, 1, 2, 3, 4}},
+		{[]bool{true, false, false, false, false, true}, []int{0, 5}},
+		{[]bool{false, true, false, false, true, false, false, false}, []int{1, 2, 3, 4}},
+		{[]bool{true, false, true, false, false, false, false, true}, []int{0, 1, 2, 7}},
+	}
+
+	for _, c := range cases {
+		detectionBuffer := NewDiscreteDetectionBuffer(options, AboveZeroAllWeights)
+		for index, detection := range c.Frames {
+			value := 0.25
+			if detection {
+				value = 0.75
+			}
+
+			err := detectionBuffer.Push(&frame.Frame{
+				OrdinalNumber:             index + 1,
+				ColorDifference:           value,
+				BinaryThresholdDifference: value,
+				Brightness:                value,
+			}, statistics)
+			assert.Nil(t, err)
+		}
+
+		actual := detectionBuffer.ResolveIndexes()
+		assert.NotNil(t, actual)
+		assert.Equal(t, c.Expected, actual)
+	}
+}
+
+func TestBufferClassifierShouldClassifyUsingMovingZScore(t *testing.T) {
+	options := options.GetDefaultDetectorOptions()
+	options.BrightnessDetectionZScore = 2.0
+	options.ColorDifferenceDetectionZScore = 2.0
+	options.BinaryThresholdDifferenceDetectionZScore = 2.0
+
+	classifier := createBufferClassifier(options, AboveMovingZScoreAllWeights)
+
+	cases := []struct {
+		Value    float64
+		Mean     float64
+		StdDev   float64
+		Expected bool
+	}{
+		{0.5, 0.1, 0.1, true},
+		{0.35, 0.1, 0.1, true},
+		{0.25, 0.1, 0.1, false},
+		{0.05, 0.1, 0.1, false},
+		{0.2, 0.1, 0.0, true},
+		{0.1, 0.1, 0.0, false},
+	}
+
+	for index, c := range cases {
+		f := &frame.Frame{
+			OrdinalNumber:             index + 1,
+			Brightness:                c.Value,
+			ColorDifference:           c.Value,
+			BinaryThresholdDifference: c.Value,
+		}
+
+		s := statistics.DescriptiveStatisticsEntry{
+			BrightnessMovingMeanAtPoint:                  c.Mean,
+			BrightnessMovingStdDevAtPoint:                c.StdDev,
+			ColorDifferenceMovingMeanAtPoint:             c.Mean,
+			ColorDifferenceMovingStdDevAtPoint:           c.StdDev,
+			BinaryThresholdDifferenceMovingMeanAtPoint:   c.Mean,
+			BinaryThresholdDifferenceMovingStdDevAtPoint: c.StdDev,
+		}
+
+		element := classifier.CreateElement(f, s)
+
+		assert.Equal(t, c.Expected, element.BrightnessClassified)
+		assert.Equal(t, c.Expected, element.ColorDifferenceClassified)
+		assert.Equal(t, c.Expected, element.BinaryThresholdDifferenceClassified)
+	}
+}
+
+func TestBufferClassifierShouldVoteUsingVotingRule(t *testing.T) {
+	cases := []struct {
+		Rule     options.VotingRule
+		Weights  [3]bool
+		Expected bool
+	}{
+		{options.AllOfVoting, [3]bool{true, true, true}, true},
+		{options.AllOfVoting, [3]bool{true, true, false}, false},
+		{options.AnyOfVoting, [3]bool{false, false, true}, true},
+		{options.AnyOfVoting, [3]bool{false, false, false}, false},
+		{options.KOfNVoting, [3]bool{true, false, true}, true},
+		{options.KOfNVoting, [3]bool{true, false, false}, false},
+		{options.WeightedVoting, [3]bool{true, false, false}, true},
+		{options.WeightedVoting, [3]bool{false, true, true}, false},
+	}
+
+	for _, c := range cases {
+		opt := options.GetDefaultDetectorOptions()
+		opt.VotingRule = c.Rule
+		opt.VotingRuleK = 2
+		opt.BrightnessVotingWeight = 2.0
+		opt.ColorDifferenceVotingWeight = 0.5
+		opt.BinaryThresholdDifferenceVotingWeight = 0.5
+		opt.VotingScoreThreshold = 1.5
+
+		classifier := createBufferClassifier(opt, AboveZeroAllWeights)
+
+		actual := classifier.(*bufferClassifier).Vote(detectionBufferElement{
+			Index:                               1,
+			BrightnessClassified:                c.Weights[0],
+			ColorDifferenceClassified:           c.Weights[1],
+			BinaryThresholdDifferenceClassified: c.Weights[2],
+		})
+
+		assert.Equal(t, c.Expected, actual)
+	}
+}
+
 func TestContinuousDetectionBufferShouldCreate(t *testing.T) {
 	cases := map[DetectionStrategy]bool{
-		AboveMovingMeanAllWeights: true,
-		AboveGlobalMeanAllWeights: true,
-		AboveZeroAllWeights:       true,
-		-1:                        false,
+		AboveMovingMeanAllWeights:   true,
+		AboveGlobalMeanAllWeights:   true,
+		AboveZeroAllWeights:         true,
+		AboveMovingZScoreAllWeights: true,
+		AboveMovingMedianAllWeights: true,
+		-1:                          false,
 	}
 
 	for strategy, validStrategy := range cases {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Krzysztofz01/video-lightning-detector/internal/analyzer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/export"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
@@ -16,7 +17,14 @@ import (
 
 // Detector instance that is able to perform a search after ligntning strikes on a video file.
 type Detector interface {
-	Run(inputVideoPath, outputDirectoryPath string, ctx context.Context) error
+	Run(inputVideoPath, outputDirectoryPath string, ctx context.Context) (DetectionSummary, error)
+}
+
+// Structure representing the summary of the lightning detection performed on a single video.
+type DetectionSummary struct {
+	Frames     int
+	Detections int
+	Events     int
 }
 
 type detector struct {
@@ -43,7 +51,7 @@ func CreateDetector(printer printer.Printer, options options.DetectorOptions) (D
 }
 
 // Perform a lightning detection on the provided video specified by the file path and store the results at the specified directory path.
-func (detector *detector) Run(inputVideoPath, outputDirectoryPath string, ctx context.Context) error {
+func (detector *detector) Run(inputVideoPath, outputDirectoryPath string, ctx context.Context) (DetectionSummary, error) {
 	runTime := time.Now()
 	detector.printer.InfoA("Starting the lightning hunt.")
 
@@ -51,16 +59,24 @@ func (detector *detector) Run(inputVideoPath, outputDirectoryPath string, ctx co
 
 	frames, err := analyzer.GetFrames(ctx)
 	if err != nil {
-		return fmt.Errorf("detector: video analysis stage failed: %w", err)
+		return DetectionSummary{}, fmt.Errorf("detector: video analysis stage failed: %w", err)
+	}
+
+	metadata, err := analyzer.GetVideoMetadata()
+	if err != nil {
+		return DetectionSummary{}, fmt.Errorf("detector: failed to access the video metadata: %w", err)
 	}
 
-	descriptiveStatistics := statistics.CreateDescriptiveStatistics(frames, int(detector.options.MovingMeanResolution))
+	descriptiveStatistics, err := detector.CreateDescriptiveStatistics(frames, metadata.FramesPerSecond)
+	if err != nil {
+		return DetectionSummary{}, fmt.Errorf("detector: descriptive statistics stage failed: %w", err)
+	}
 
 	if detector.options.AutoThresholds {
 		threshold := NewAutoThreshold(frames, descriptiveStatistics, detector.printer)
 
-		if options, err := threshold.ApplyToOptions(detector.options, AboveMeanOfDeviations); err != nil {
-			return fmt.Errorf("detector: failed to perform the auto-threshold calculation: %w", err)
+		if options, err := threshold.ApplyToOptions(detector.options, detector.options.AutoThresholdStrategy); err != nil {
+			return DetectionSummary{}, fmt.Errorf("detector: failed to perform the auto-threshold calculation: %w", err)
 		} else {
 			detector.options = options
 		}
@@ -68,16 +84,41 @@ func (detector *detector) Run(inputVideoPath, outputDirectoryPath string, ctx co
 
 	detections, err := detector.PerformVideoDetection(frames, descriptiveStatistics)
 	if err != nil {
-		return fmt.Errorf("detector: video detection stage failed: %w", err)
+		return DetectionSummary{}, fmt.Errorf("detector: video detection stage failed: %w", err)
+	}
+
+	events, err := detector.PerformEventsGrouping(metadata, frames, descriptiveStatistics, detections)
+	if err != nil {
+		return DetectionSummary{}, fmt.Errorf("detector: strike events grouping stage failed: %w", err)
 	}
 
 	exporter := export.NewExporter(inputVideoPath, outputDirectoryPath, detector.options, detector.printer)
-	if err := exporter.Export(frames, descriptiveStatistics, detections); err != nil {
-		return fmt.Errorf("detector: export stage failed: %w", err)
+	if err := exporter.Export(frames, descriptiveStatistics, detections, events); err != nil {
+		return DetectionSummary{}, fmt.Errorf("detector: export stage failed: %w", err)
 	}
 
 	detector.printer.InfoA("Lightning hunting took: %s", time.Since(runTime))
-	return nil
+
+	return DetectionSummary{
+		Frames:     frames.Count(),
+		Detections: len(detections),
+		Events:     len(events),
+	}, nil
+}
+
+// Helper function used to create the descriptive statistics using the statistics backend specified in the options. The
+// video frame rate is required only by the exponential statistics backend. The moving median is created only if the moving
+// median detection strategy is selected.
+func (detector *detector) CreateDescriptiveStatistics(fc frame.FrameCollection, fps float64) (statistics.DescriptiveStatistics, error) {
+	switch detector.options.StatisticsBackend {
+	case options.MovingWindowStatistics:
+		movingMedian := detector.options.DetectionStrategy == options.AboveMovingMedianAllWeights
+		return statistics.CreateDescriptiveStatistics(fc, int(detector.options.MovingMeanResolution), movingMedian), nil
+	case options.ExponentialStatistics:
+		return statistics.CreateExponentialDescriptiveStatistics(fc, detector.options.ExponentialHalfLife, fps)
+	default:
+		return statistics.DescriptiveStatistics{}, fmt.Errorf("detector: invalid statistics backend specified")
+	}
 }
 
 // Helper function used to filter out indecies representing frames wihich meet the requirement thresholds.
@@ -85,16 +126,28 @@ func (detector *detector) PerformVideoDetection(framesCollection frame.FrameColl
 	videoDetectionTime := time.Now()
 	detector.printer.Debug("Starting the video detection stage.")
 
+	progressStep, progressFinalize := detector.printer.ProgressSteps("Video detection stage.", framesCollection.Count())
+	defer progressFinalize()
+
+	detections, err := detectFrames(framesCollection, ds, detector.options, progressStep)
+	if err != nil {
+		return nil, err
+	}
+
+	detector.printer.Debug("Video detection stage finished. Stage took: %s", time.Since(videoDetectionTime))
+
+	return detections, nil
+}
+
+// Helper function used to push the frames and their descriptive statistics to the detection buffer created using the options and
+// resolve the detected frames indexes. The progress step function is called after each pushed frame.
+func detectFrames(framesCollection frame.FrameCollection, ds statistics.DescriptiveStatistics, o options.DetectorOptions, progressStep func()) ([]int, error) {
 	var (
-		frames          []*frame.Frame          = framesCollection.GetAll()
-		detectionBuffer DiscreteDetectionBuffer = NewDiscreteDetectionBuffer(detector.options, AboveMovingMeanAllWeights)
+		detectionBuffer DiscreteDetectionBuffer = NewDiscreteDetectionBuffer(o, o.DetectionStrategy)
 		statistics      statistics.DescriptiveStatisticsEntry
 	)
 
-	progressStep, progressFinalize := detector.printer.ProgressSteps("Video detection stage.", len(frames))
-	defer progressFinalize()
-
-	for frameIndex, frame := range frames {
+	for frameIndex, frame := range framesCollection.GetAll() {
 		if err := ds.AtP(frameIndex, &statistics); err != nil {
 			return nil, fmt.Errorf("detector: failed to access frame descriptive statistics: %w", err)
 		}
@@ -106,11 +159,29 @@ func (detector *detector) PerformVideoDetection(framesCollection frame.FrameColl
 		progressStep()
 	}
 
-	detector.printer.Debug("Video detection stage finished. Stage took: %s", time.Since(videoDetectionTime))
-
 	return detectionBuffer.ResolveIndexes(), nil
 }
 
+// Helper function used to group the detected frames indexes into strike events using the frame rate and the creation time of the video.
+func (detector *detector) PerformEventsGrouping(metadata analyzer.VideoMetadata, fc frame.FrameCollection, ds statistics.DescriptiveStatistics, detections []int) ([]event.StrikeEvent, error) {
+	eventsGroupingTime := time.Now()
+	detector.printer.Debug("Starting the strike events grouping stage.")
+
+	if metadata.CreationTime.IsZero() {
+		detector.printer.Warning("The video creation time is not available. The strike events UTC times will not be calculated.")
+	}
+
+	events, err := event.CreateStrikeEvents(fc, ds, detections, metadata.FramesPerSecond, detector.options.StrikeEventGapTolerance, metadata.CreationTime)
+	if err != nil {
+		return nil, fmt.Errorf("detector: failed to group the detections into strike events: %w", err)
+	}
+
+	detector.printer.Info("Detected %d frames grouped into %d strike events.", len(detections), len(events))
+	detector.printer.Debug("Strike events grouping stage finished. Stage took: %s", time.Since(eventsGroupingTime))
+
+	return events, nil
+}
+
 // NOTE: Experimental sampling of binary threshold from recording
 // func (detector *detector) SampleBinaryThreshold(inputVideoPath string) (float64, error) {
 // 	video, err := vidio.NewVideo(inputVideoPath)
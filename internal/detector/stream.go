@@ -49,13 +49,15 @@ func (detector *streamDetector) Run(inputVideoStreamUrl string, ctx context.Cont
 	detector.Printer.InfoA("starting the lightning hunt.")
 
 	var (
-		movingMeanResolution int                                         = int(detector.Options.MovingMeanResolution)
-		analyzer             analyzer.StreamAnalyzer                     = analyzer.NewStreamAnalyzer(inputVideoStreamUrl, detector.Options, detector.Printer)
-		stats                statistics.IncrementalDescriptiveStatistics = statistics.NewIncrementalDescriptiveStatistics(movingMeanResolution)
-		detectionBuffer      ContinuousDetectionBuffer                   = NewContinuousDetectionBuffer(detector.Options, AboveMovingMeanAllWeights)
-		detectionIndexes     utils.DecayingHashSet[int]                  = utils.NewDecayingHashSet[int](4)
+		movingMeanResolution int                        = int(detector.Options.MovingMeanResolution)
+		analyzer             analyzer.StreamAnalyzer    = analyzer.NewStreamAnalyzer(inputVideoStreamUrl, detector.Options, detector.Printer)
+		detectionBuffer      ContinuousDetectionBuffer  = NewContinuousDetectionBuffer(detector.Options, detector.Options.DetectionStrategy)
+		detectionIndexes     utils.DecayingHashSet[int] = newDetectionIndexSet(detector.Options)
 	)
 
+	// NOTE: The analyzer is closed on all return paths in order to stop the pipeline stages and the ffmpeg process of the stream
+	defer analyzer.Close()
+
 	var (
 		currentFrame            *frame.Frame
 		currentFrameTimestamp   time.Time
@@ -65,6 +67,7 @@ func (detector *streamDetector) Run(inputVideoStreamUrl string, ctx context.Cont
 		windowStatistics        statistics.DescriptiveStatisticsEntry
 		err                     error
 		frameStrikeDetector     FrameStrikeDetector
+		stats                   statistics.IncrementalDescriptiveStatistics
 	)
 
 readStream:
@@ -94,6 +97,13 @@ readStream:
 			}
 		}
 
+		// NOTE: Lazy initialization of the statistics due to the frame rate requirement of the exponential statistics backend
+		if stats == nil {
+			if stats, err = detector.CreateIncrementalStatistics(analyzer, movingMeanResolution); err != nil {
+				return fmt.Errorf("detector: failed to create the incremental statistics: %w", err)
+			}
+		}
+
 		if currentFrame, currentFrameTimestamp, err = analyzer.PeekFrame(0); err != nil {
 			return fmt.Errorf("detector: failed to peek the current frame: %w", err)
 		}
@@ -153,11 +163,16 @@ readStream:
 	}
 
 	if detector.Options.DiagnosticMode {
+		var diagnosticStatistics statistics.DescriptiveStatisticsEntry
+		if stats != nil {
+			diagnosticStatistics = stats.Peek()
+		}
+
 		detector.Printer.WriteParsable(struct {
 			Statistics statistics.DescriptiveStatisticsEntry `json:"statistics"`
 			FrameCount int                                   `json:"frame-count"`
 		}{
-			Statistics: stats.Peek(),
+			Statistics: diagnosticStatistics,
 			FrameCount: analyzer.FrameCount(),
 		})
 	}
@@ -165,3 +180,26 @@ readStream:
 	detector.Printer.InfoA("Lightning hunt was running for: %s", time.Since(runTime))
 	return nil
 }
+
+// Helper function used to create the incremental statistics using the statistics backend specified in the options.
+func (detector *streamDetector) CreateIncrementalStatistics(analyzer analyzer.StreamAnalyzer, movingMeanResolution int) (statistics.IncrementalDescriptiveStatistics, error) {
+	switch detector.Options.StatisticsBackend {
+	case options.MovingWindowStatistics:
+		return statistics.NewIncrementalDescriptiveStatistics(movingMeanResolution), nil
+	case options.ExponentialStatistics:
+		fps, err := analyzer.PeekFramesPerSecond()
+		if err != nil {
+			return nil, fmt.Errorf("detector: failed to access the stream frame rate via analyzer: %w", err)
+		}
+
+		return statistics.NewExponentialDescriptiveStatistics(detector.Options.ExponentialHalfLife, fps)
+	default:
+		return nil, fmt.Errorf("detector: invalid statistics backend specified")
+	}
+}
+
+// Create the set of the already reported detection indexes. The detections are resolved for each frame of the classification
+// window, therefore the set must remember at least the indexes of the whole window to report each detection once.
+func newDetectionIndexSet(o options.StreamDetectorOptions) utils.DecayingHashSet[int] {
+	return utils.NewDecayingHashSet[int](o.ClassificationWindowSize)
+}
@@ -0,0 +1,58 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+)
+
+func TestDetectionIndexSetShouldReportEachDetectionOnceWithCustomWindow(t *testing.T) {
+	o := options.GetDefaultStreamDetectorOptions()
+	o.ClassificationWindowSize = 8
+	o.BrightnessDetectionThreshold = 0.5
+	o.ColorDifferenceDetectionThreshold = 0.5
+	o.BinaryThresholdDifferenceDetectionThreshold = 0.5
+
+	var (
+		detectionBuffer  ContinuousDetectionBuffer = NewContinuousDetectionBuffer(o, AboveZeroAllWeights)
+		detectionIndexes                           = newDetectionIndexSet(o)
+		reported                                   = make(map[int]int)
+	)
+
+	for index := 0; index < 64; index += 1 {
+		value := 0.25
+		if index%16 < 6 {
+			value = 0.75
+		}
+
+		f := &frame.Frame{
+			OrdinalNumber:             index + 1,
+			Brightness:                value,
+			ColorDifference:           value,
+			BinaryThresholdDifference: value,
+		}
+
+		detections, err := detectionBuffer.PushAndResolveIndexes(f, statistics.DescriptiveStatisticsEntry{})
+		assert.Nil(t, err)
+
+		for _, frameIndex := range detections {
+			if detectionIndexes.Contains(frameIndex) {
+				continue
+			}
+
+			assert.LessOrEqual(t, index-frameIndex, o.ClassificationWindowSize-1)
+
+			reported[frameIndex] += 1
+			detectionIndexes.Add(frameIndex)
+		}
+	}
+
+	assert.Len(t, reported, 4*6)
+	for frameIndex, count := range reported {
+		assert.Equal(t, 1, count, "frame index %d reported multiple times", frameIndex)
+	}
+}
@@ -8,17 +8,12 @@ import (
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
-)
-
-type AutoThresholdStrategy int
-
-const (
-	AboveMeanOfDeviations AutoThresholdStrategy = iota
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
 )
 
 type AutoThreshold interface {
 	// Perform the auto-threshold calculation with the given strategy and applying the results to the provided options and returning the altered copy
-	ApplyToOptions(opt options.DetectorOptions, s AutoThresholdStrategy) (options.DetectorOptions, error)
+	ApplyToOptions(opt options.DetectorOptions, s options.AutoThresholdStrategy) (options.DetectorOptions, error)
 }
 
 type autoThreshold struct {
@@ -27,7 +22,7 @@ type autoThreshold struct {
 	Printer    printer.Printer
 }
 
-func (at *autoThreshold) ApplyToOptions(opt options.DetectorOptions, s AutoThresholdStrategy) (options.DetectorOptions, error) {
+func (at *autoThreshold) ApplyToOptions(opt options.DetectorOptions, s options.AutoThresholdStrategy) (options.DetectorOptions, error) {
 	autoThresholdTime := time.Now()
 	at.Printer.Debug("Starting the auto thresholds calculation stage.")
 
@@ -36,11 +31,21 @@ func (at *autoThreshold) ApplyToOptions(opt options.DetectorOptions, s AutoThres
 		err        error
 	)
 
+	deviations := at.CollectPositiveDeviations()
+
 	switch s {
-	case AboveMeanOfDeviations:
-		if thresholds, err = at.CalculateAboveMeanOfDeviations(); err != nil {
+	case options.AboveMeanOfDeviations:
+		if thresholds, err = at.CalculateAboveMeanOfDeviations(deviations, opt); err != nil {
 			return options.DetectorOptions{}, fmt.Errorf("detector: failed to calculate the thresholds using about mean of deviations: %w", err)
 		}
+	case options.PercentileOfDeviations:
+		if thresholds, err = at.CalculatePercentileOfDeviations(deviations, opt); err != nil {
+			return options.DetectorOptions{}, fmt.Errorf("detector: failed to calculate the thresholds using percentile of deviations: %w", err)
+		}
+	case options.OtsuOfDeviations:
+		if thresholds, err = at.CalculateOtsuOfDeviations(deviations, opt); err != nil {
+			return options.DetectorOptions{}, fmt.Errorf("detector: failed to calculate the thresholds using otsu of deviations: %w", err)
+		}
 	default:
 		return options.DetectorOptions{}, fmt.Errorf("detector: invalid auto threshold strategy specified")
 	}
@@ -81,79 +86,91 @@ func (at *autoThreshold) ApplyToOptions(opt options.DetectorOptions, s AutoThres
 	return copyOptions, nil
 }
 
-func (at *autoThreshold) CalculateAboveMeanOfDeviations() (thresholdSet, error) {
-	frames := at.Frames.GetAll()
-
-	// TODO: Those values can be either controlled via different strategies or be fine-tuned.
-	const (
-		brightnessDiffCoefficient   float64 = 1.0
-		brightnessStdDevCoefficient float64 = 0.0
-		colorDiffDiffCoefficient    float64 = 1.0
-		colorDiffStdDevCoefficient  float64 = 0.0
-		btDiffDiffCoefficient       float64 = 0.25
-		btDiffStdDevCoefficient     float64 = 0.15
-	)
-
+// Collect the positive deviations of the frame metrics from the moving mean together with the moving standard deviations at the given frames.
+func (at *autoThreshold) CollectPositiveDeviations() deviationSet {
 	var (
-		brightnessMeanDiffSum float64 = 0.0
-		brightnessStdDevSum   float64 = 0.0
-		brightnessCount       int     = 0
-		colorDiffMeanDiffSum  float64 = 0.0
-		colorDiffStdDevSum    float64 = 0.0
-		colorDiffCount        int     = 0
-		btDiffMeanDiffSum     float64 = 0.0
-		btDiffStdDevSum       float64 = 0.0
-		btDiffCount           int     = 0
+		frames     []*frame.Frame = at.Frames.GetAll()
+		deviations deviationSet
 	)
 
 	for frameIndex, frame := range frames {
 		if brightnessDiff := frame.Brightness - at.Statistics.BrightnessMovingMean[frameIndex]; brightnessDiff > 0 {
-			brightnessMeanDiffSum += brightnessDiff
-			brightnessStdDevSum += at.Statistics.BrightnessMovingStdDev[frameIndex]
-			brightnessCount += 1
+			deviations.Brightness.Push(brightnessDiff, at.Statistics.BrightnessMovingStdDev[frameIndex])
 		}
 
 		if colorDiff := frame.ColorDifference - at.Statistics.ColorDifferenceMovingMean[frameIndex]; colorDiff > 0 {
-			colorDiffMeanDiffSum += colorDiff
-			colorDiffStdDevSum += at.Statistics.ColorDifferenceMovingStdDev[frameIndex]
-			colorDiffCount += 1
+			deviations.ColorDifference.Push(colorDiff, at.Statistics.ColorDifferenceMovingStdDev[frameIndex])
 		}
 
 		if btDiff := frame.BinaryThresholdDifference - at.Statistics.BinaryThresholdDifferenceMovingMean[frameIndex]; btDiff > 0 {
-			btDiffMeanDiffSum += btDiff
-			btDiffStdDevSum += at.Statistics.BinaryThresholdDifferenceMovingStdDev[frameIndex]
-			btDiffCount += 1
+			deviations.BinaryThresholdDifference.Push(btDiff, at.Statistics.BinaryThresholdDifferenceMovingStdDev[frameIndex])
 		}
 	}
 
-	var brightnessThreshold float64
-	if brightnessCount == 0 {
-		brightnessThreshold = 0
-	} else {
-		countf := float64(brightnessCount)
-		brightnessThreshold = brightnessDiffCoefficient*brightnessMeanDiffSum/countf + brightnessStdDevCoefficient*brightnessStdDevSum/countf
+	return deviations
+}
+
+// Calculate the thresholds as the weighted sum of the mean of positive deviations and the mean of moving standard deviations at the given frames.
+func (at *autoThreshold) CalculateAboveMeanOfDeviations(deviations deviationSet, opt options.DetectorOptions) (thresholdSet, error) {
+	meanOfDeviations := func(d metricDeviations, diffCoefficient, stdDevCoefficient float64) float64 {
+		if len(d.Deviations) == 0 {
+			return 0
+		}
+
+		deviationsMean, _ := utils.MeanStdDev(d.Deviations)
+		stdDevsMean, _ := utils.MeanStdDev(d.StdDevs)
+
+		return diffCoefficient*deviationsMean + stdDevCoefficient*stdDevsMean
 	}
 
-	var colorDifferenceThreshold float64
-	if colorDiffCount == 0 {
-		colorDifferenceThreshold = 0
-	} else {
-		countf := float64(colorDiffCount)
-		colorDifferenceThreshold = colorDiffDiffCoefficient*colorDiffMeanDiffSum/countf + colorDiffStdDevCoefficient*colorDiffStdDevSum/countf
+	return thresholdSet{
+		BrightnessThreshold:                meanOfDeviations(deviations.Brightness, opt.BrightnessAutoThresholdMeanCoefficient, opt.BrightnessAutoThresholdStdDevCoefficient),
+		ColorDifferenceThreshold:           meanOfDeviations(deviations.ColorDifference, opt.ColorDifferenceAutoThresholdMeanCoefficient, opt.ColorDifferenceAutoThresholdStdDevCoefficient),
+		BinaryThresholdDifferenceThreshold: meanOfDeviations(deviations.BinaryThresholdDifference, opt.BinaryThresholdDifferenceAutoThresholdMeanCoefficient, opt.BinaryThresholdDifferenceAutoThresholdStdDevCoefficient),
+	}, nil
+}
+
+// Calculate the thresholds as the given percentile of the positive deviations. Only the small part of frames with the greatest deviations is
+// expected to be above the thresholds, therefore the strategy is suitable for recordings with rare lightning strikes.
+func (at *autoThreshold) CalculatePercentileOfDeviations(deviations deviationSet, opt options.DetectorOptions) (thresholdSet, error) {
+	if opt.AutoThresholdPercentile <= 0 || opt.AutoThresholdPercentile > 100 {
+		return thresholdSet{}, fmt.Errorf("detector: invalid auto threshold percentile specified")
 	}
 
-	var binaryThresholdDifferenceThreshold float64
-	if btDiffCount == 0 {
-		binaryThresholdDifferenceThreshold = 0
-	} else {
-		countf := float64(btDiffCount)
-		binaryThresholdDifferenceThreshold = btDiffDiffCoefficient*btDiffMeanDiffSum/countf + btDiffStdDevCoefficient*btDiffStdDevSum/countf
+	percentileOfDeviations := func(d metricDeviations) float64 {
+		if len(d.Deviations) == 0 {
+			return 0
+		}
+
+		return utils.Percentile(d.Deviations, opt.AutoThresholdPercentile)
 	}
 
 	return thresholdSet{
-		BrightnessThreshold:                brightnessThreshold,
-		ColorDifferenceThreshold:           colorDifferenceThreshold,
-		BinaryThresholdDifferenceThreshold: binaryThresholdDifferenceThreshold,
+		BrightnessThreshold:                percentileOfDeviations(deviations.Brightness),
+		ColorDifferenceThreshold:           percentileOfDeviations(deviations.ColorDifference),
+		BinaryThresholdDifferenceThreshold: percentileOfDeviations(deviations.BinaryThresholdDifference),
+	}, nil
+}
+
+// Calculate the thresholds using the Otsu method on the histogram of positive deviations. The thresholds are placed at the natural split between
+// the deviations caused by the noise and the ones caused by the lightning, therefore the strategy is suitable for recordings with frequent strikes.
+func (at *autoThreshold) CalculateOtsuOfDeviations(deviations deviationSet, opt options.DetectorOptions) (thresholdSet, error) {
+	if opt.AutoThresholdOtsuBins < 2 {
+		return thresholdSet{}, fmt.Errorf("detector: invalid auto threshold otsu histogram bins count specified")
+	}
+
+	otsuOfDeviations := func(d metricDeviations) float64 {
+		if len(d.Deviations) == 0 {
+			return 0
+		}
+
+		return utils.OtsuThreshold(d.Deviations, opt.AutoThresholdOtsuBins)
+	}
+
+	return thresholdSet{
+		BrightnessThreshold:                otsuOfDeviations(deviations.Brightness),
+		ColorDifferenceThreshold:           otsuOfDeviations(deviations.ColorDifference),
+		BinaryThresholdDifferenceThreshold: otsuOfDeviations(deviations.BinaryThresholdDifference),
 	}, nil
 }
 
@@ -170,3 +187,19 @@ type thresholdSet struct {
 	ColorDifferenceThreshold           float64
 	BinaryThresholdDifferenceThreshold float64
 }
+
+type metricDeviations struct {
+	Deviations []float64
+	StdDevs    []float64
+}
+
+func (d *metricDeviations) Push(deviation, stdDev float64) {
+	d.Deviations = append(d.Deviations, deviation)
+	d.StdDevs = append(d.StdDevs, stdDev)
+}
+
+type deviationSet struct {
+	Brightness                metricDeviations
+	ColorDifference           metricDeviations
+	BinaryThresholdDifference metricDeviations
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+)
+
+func mockAutoThreshold() AutoThreshold {
+	fc := frame.NewFrameCollection(1000)
+	for index := range 1000 {
+		value := 0.1 + 0.02*math.Sin(float64(index)*1.7)
+		if index%250 == 100 {
+			value = 0.8
+		}
+
+		fc.Push(&frame.Frame{
+			OrdinalNumber:             index + 1,
+			Brightness:                value,
+			ColorDifference:           value,
+			BinaryThresholdDifference: value,
+		})
+	}
+
+	fc.Lock()
+
+	ds := statistics.CreateDescriptiveStatistics(fc, 20, false)
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	return NewAutoThreshold(fc, ds, p)
+}
+
+func TestAutoThresholdShouldSeparateTheOutliers(t *testing.T) {
+	at := mockAutoThreshold()
+
+	strategies := []options.AutoThresholdStrategy{
+		options.AboveMeanOfDeviations,
+		options.PercentileOfDeviations,
+		options.OtsuOfDeviations,
+	}
+
+	for _, strategy := range strategies {
+		o := options.GetDefaultDetectorOptions()
+		o.AutoThresholds = true
+		o.AutoThresholdStrategy = strategy
+		o.AutoThresholdPercentile = 99
+
+		result, err := at.ApplyToOptions(o, strategy)
+		assert.Nil(t, err)
+
+		assert.Greater(t, result.BrightnessDetectionThreshold, 0.0)
+		assert.Less(t, result.BrightnessDetectionThreshold, 0.6)
+		assert.Greater(t, result.ColorDifferenceDetectionThreshold, 0.0)
+		assert.Less(t, result.ColorDifferenceDetectionThreshold, 0.6)
+	}
+}
+
+func TestAutoThresholdShouldApplyTheCoefficients(t *testing.T) {
+	at := mockAutoThreshold()
+
+	o := options.GetDefaultDetectorOptions()
+	base, err := at.ApplyToOptions(o, options.AboveMeanOfDeviations)
+	assert.Nil(t, err)
+
+	o.BrightnessAutoThresholdMeanCoefficient = 2.0
+	scaled, err := at.ApplyToOptions(o, options.AboveMeanOfDeviations)
+	assert.Nil(t, err)
+
+	assert.InDelta(t, 2*base.BrightnessDetectionThreshold, scaled.BrightnessDetectionThreshold, 1e-9)
+	assert.InDelta(t, base.ColorDifferenceDetectionThreshold, scaled.ColorDifferenceDetectionThreshold, 1e-9)
+}
+
+func TestAutoThresholdShouldReturnErrorForInvalidStrategy(t *testing.T) {
+	at := mockAutoThreshold()
+
+	_, err := at.ApplyToOptions(options.GetDefaultDetectorOptions(), -1)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,497 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/analyzer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/export"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// The maximal amount of coordinate search rounds performed for a single detection strategy and moving mean resolution.
+const tuneMaxRounds int = 5
+
+// The keys of the detector options affecting the detection, which are stored in the tuned configuration profile.
+var tuneProfileKeys = []string{
+	"auto-thresholds",
+	"detection-strategy",
+	"brightness-threshold",
+	"color-difference-threshold",
+	"binary-threshold-difference-threshold",
+	"brightness-z-score",
+	"color-difference-z-score",
+	"binary-threshold-difference-z-score",
+	"statistics-backend",
+	"moving-mean-resolution",
+	"voting-rule",
+	"voting-rule-k",
+	"brightness-voting-weight",
+	"color-difference-voting-weight",
+	"binary-threshold-difference-voting-weight",
+	"voting-score-threshold",
+	"classification-window-size",
+	"classification-max-gap",
+	"scaling-factor",
+	"scaling-algorithm",
+	"denoise",
+}
+
+// Tuner instance that is able to search the detector options maximizing the detection metric compared to the ground-truth frames.
+type Tuner interface {
+	Run(inputVideoPath, outputDirectoryPath string, ctx context.Context) (TuneResult, error)
+}
+
+// Structure representing the best detector options found by the tuner and the detection quality achieved using them.
+type TuneResult struct {
+	Options         options.DetectorOptions
+	ConfusionMatrix statistics.ConfusionMatrix
+	Score           float64
+	Trials          int
+}
+
+type tuner struct {
+	Options     options.DetectorOptions
+	TuneOptions options.TuneOptions
+	Printer     printer.Printer
+}
+
+// Create a new tuner instance with the specified detector options used as the base of the search and the tune options.
+func CreateTuner(printer printer.Printer, o options.DetectorOptions, tuneOptions options.TuneOptions) (Tuner, error) {
+	if printer == nil {
+		return nil, errors.New("detector: invalid nil reference renderer provided")
+	}
+
+	if ok, msg := o.AreValid(); !ok {
+		return nil, fmt.Errorf("detector: invalid options %s", msg)
+	}
+
+	if ok, msg := tuneOptions.AreValid(); !ok {
+		return nil, fmt.Errorf("detector: invalid tune options %s", msg)
+	}
+
+	// NOTE: The moving median detection strategy is not supported by the exponential statistics backend
+	if o.StatisticsBackend == options.ExponentialStatistics {
+		strategies := make([]options.DetectionStrategy, 0, len(tuneOptions.DetectionStrategies))
+		for _, strategy := range tuneOptions.DetectionStrategies {
+			if strategy != options.AboveMovingMedianAllWeights {
+				strategies = append(strategies, strategy)
+			}
+		}
+
+		if len(strategies) == 0 {
+			return nil, fmt.Errorf("detector: none of the tuned detection strategies is supported by the exponential statistics backend")
+		}
+
+		if len(strategies) != len(tuneOptions.DetectionStrategies) {
+			printer.Warning("The moving median detection strategy is not supported by the exponential statistics backend and is skipped.")
+		}
+
+		tuneOptions.DetectionStrategies = strategies
+	}
+
+	// NOTE: The analysis is performed only once, therefore the frames are always cached and the thresholds are never auto-calculated
+	o.ImportPreanalyzed = true
+	o.AutoThresholds = false
+
+	printer.Debug("Tuner create with options %+v and tune options %+v", o, tuneOptions)
+
+	return &tuner{
+		Options:     o,
+		TuneOptions: tuneOptions,
+		Printer:     printer,
+	}, nil
+}
+
+// Search the detector options maximizing the detection metric for the provided video specified by the file path. The video analysis
+// is cached in the output directory and reused by the following runs. The video path can be empty, if the output directory contains
+// the analysis cache created with matching options. The best options are stored as a configuration profile in the output directory.
+func (tuner *tuner) Run(inputVideoPath, outputDirectoryPath string, ctx context.Context) (TuneResult, error) {
+	runTime := time.Now()
+	tuner.Printer.InfoA("Starting the thresholds tuning.")
+
+	// NOTE: The exponential statistics half-life is specified in seconds and the frame rate is not stored in the analysis cache
+	if len(inputVideoPath) == 0 && tuner.Options.StatisticsBackend == options.ExponentialStatistics {
+		return TuneResult{}, fmt.Errorf("detector: the exponential statistics backend requires the video frame rate and no video specified")
+	}
+
+	frames, fps, err := tuner.GetFrames(inputVideoPath, outputDirectoryPath, ctx)
+	if err != nil {
+		return TuneResult{}, fmt.Errorf("detector: video analysis stage failed: %w", err)
+	}
+
+	groundTruth, err := utils.ParseRangeExpression(tuner.TuneOptions.GroundTruthExpression)
+	if err != nil {
+		return TuneResult{}, fmt.Errorf("detector: failed to parse the ground-truth frames expression: %w", err)
+	}
+
+	result, err := tuner.Search(ctx, frames, groundTruth, func(o options.DetectorOptions) (statistics.DescriptiveStatistics, error) {
+		statisticsDetector := &detector{options: o, printer: tuner.Printer}
+		return statisticsDetector.CreateDescriptiveStatistics(frames, fps)
+	})
+
+	if err != nil {
+		return TuneResult{}, fmt.Errorf("detector: thresholds search failed: %w", err)
+	}
+
+	profilePath, err := tuner.ExportProfile(outputDirectoryPath, result.Options)
+	if err != nil {
+		return TuneResult{}, fmt.Errorf("detector: tuned profile export failed: %w", err)
+	}
+
+	tableTuneResult(tuner.Printer, result)
+
+	tuner.Printer.Info("Tuned configuration profile %q exported to: %s", tuner.TuneOptions.ProfileName, profilePath)
+	tuner.Printer.InfoA("Thresholds tuning took: %s", time.Since(runTime))
+	return result, nil
+}
+
+// Helper function used to access the analyzed frames and the video frame rate. The frames are imported from the cache if the video path
+// is not specified. The frame rate is accessed only if it is required by the exponential statistics backend, otherwise zero is returned.
+func (tuner *tuner) GetFrames(inputVideoPath, outputDirectoryPath string, ctx context.Context) (frame.FrameCollection, float64, error) {
+	if len(inputVideoPath) != 0 {
+		analyzer := analyzer.NewAnalyzer(inputVideoPath, outputDirectoryPath, tuner.Options, tuner.Printer)
+
+		frames, err := analyzer.GetFrames(ctx)
+		if err != nil || tuner.Options.StatisticsBackend != options.ExponentialStatistics {
+			return frames, 0, err
+		}
+
+		metadata, err := analyzer.GetVideoMetadata()
+		if err != nil {
+			return nil, 0, fmt.Errorf("detector: failed to access the video metadata: %w", err)
+		}
+
+		return frames, metadata.FramesPerSecond, nil
+	}
+
+	frames, status, err := analyzer.ImportPreanalyzedFramesCache(outputDirectoryPath, "", tuner.Options)
+	if err != nil {
+		return nil, 0, fmt.Errorf("detector: failed to import the preanalyzed frames: %w", err)
+	}
+
+	switch status {
+	case analyzer.CacheValid, analyzer.CacheLegacy:
+	case analyzer.CacheOptionsMismatch:
+		return nil, 0, fmt.Errorf("detector: the preanalyzed frames cache was created using different analysis options and no video specified")
+	default:
+		return nil, 0, fmt.Errorf("detector: no preanalyzed frames cache found and no video specified")
+	}
+
+	return frames, 0, nil
+}
+
+// Search the detection strategy, moving mean resolution and thresholds maximizing the metric. The thresholds are searched using the
+// coordinate search over the percentiles of the frame metrics deviations specific to the given detection strategy. The statistics are
+// created only once for each moving mean resolution and each trial only reruns the detection stage.
+func (tuner *tuner) Search(ctx context.Context, frames frame.FrameCollection, groundTruth []int, createStatistics func(o options.DetectorOptions) (statistics.DescriptiveStatistics, error)) (TuneResult, error) {
+	var (
+		offset int = 0
+		count  int = frames.Count()
+	)
+
+	if count > 0 {
+		offset = frames.GetAll()[0].OrdinalNumber - 1
+	}
+
+	// NOTE: The ground-truth is specified as 1-based frame numbers and is shifted to be relative to the first analyzed frame
+	actual := statistics.ShiftClassificationRange(groundTruth, offset, 1, count)
+	if len(actual) == 0 {
+		return TuneResult{}, fmt.Errorf("detector: no ground-truth frames are placed within the analyzed frames range")
+	}
+
+	resolutions := tuner.TuneOptions.MovingMeanResolutions
+	if tuner.Options.StatisticsBackend != options.MovingWindowStatistics {
+		resolutions = []int{int(tuner.Options.MovingMeanResolution)}
+	}
+
+	var (
+		best   TuneResult = TuneResult{Score: math.Inf(-1)}
+		trials int        = 0
+	)
+
+	progressStep, progressFinalize := tuner.Printer.ProgressSteps("Thresholds tuning stage.", len(resolutions)*len(tuner.TuneOptions.DetectionStrategies))
+	defer progressFinalize()
+
+	for _, resolution := range resolutions {
+		o := tuner.Options.Clone()
+		o.MovingMeanResolution = int32(resolution)
+
+		// NOTE: The statistics are shared by all strategies, therefore the moving median is created if any strategy requires it
+		statisticsOptions := o.Clone()
+		if slices.Contains(tuner.TuneOptions.DetectionStrategies, options.AboveMovingMedianAllWeights) {
+			statisticsOptions.DetectionStrategy = options.AboveMovingMedianAllWeights
+		}
+
+		ds, err := createStatistics(statisticsOptions)
+		if err != nil {
+			return TuneResult{}, fmt.Errorf("detector: failed to create the descriptive statistics: %w", err)
+		}
+
+		entries := make([]statistics.DescriptiveStatisticsEntry, count)
+		for index := range entries {
+			if err := ds.AtP(index, &entries[index]); err != nil {
+				return TuneResult{}, fmt.Errorf("detector: failed to access frame descriptive statistics: %w", err)
+			}
+		}
+
+		for _, strategy := range tuner.TuneOptions.DetectionStrategies {
+			o.DetectionStrategy = strategy
+
+			evaluate := func(values [3]float64) (TuneResult, error) {
+				if err := ctx.Err(); err != nil {
+					return TuneResult{}, fmt.Errorf("detector: thresholds search interrupted: %w", err)
+				}
+
+				trials += 1
+
+				trialOptions := withTuneThresholds(o, values)
+				detections, err := detectFrames(frames, ds, trialOptions, func() {})
+				if err != nil {
+					return TuneResult{}, fmt.Errorf("detector: failed to detect the frames: %w", err)
+				}
+
+				predicted := statistics.ShiftClassificationRange(detections, offset, 0, count)
+				cm := statistics.CreateConfusionMatrix(actual, predicted, count)
+
+				return TuneResult{
+					Options:         trialOptions,
+					ConfusionMatrix: cm,
+					Score:           tuneScore(cm, tuner.TuneOptions),
+				}, nil
+			}
+
+			result, err := searchTuneThresholds(createTuneCandidates(frames, entries, strategy, tuner.TuneOptions.ThresholdSteps), evaluate)
+			if err != nil {
+				return TuneResult{}, err
+			}
+
+			tuner.Printer.Debug("Tuned strategy: %s Resolution: %d Score: %g", strategy.String(), resolution, result.Score)
+
+			if result.Score > best.Score {
+				best = result
+			}
+
+			progressStep()
+		}
+	}
+
+	best.Trials = trials
+	return best, nil
+}
+
+// Export the tuned options as the named profile of the configuration file in the output directory.
+func (tuner *tuner) ExportProfile(outputDirectoryPath string, o options.DetectorOptions) (string, error) {
+	values, err := o.GetConfigValues(tuneProfileKeys...)
+	if err != nil {
+		return "", fmt.Errorf("detector: failed to access the tuned options configuration values: %w", err)
+	}
+
+	data, err := options.MarshalConfigProfile(tuner.TuneOptions.ProfileName, values)
+	if err != nil {
+		return "", fmt.Errorf("detector: failed to marshal the tuned configuration profile: %w", err)
+	}
+
+	profilePath := path.Join(outputDirectoryPath, export.YamlTuneProfileFilename)
+	profileFile, err := utils.CreateFileWithTree(profilePath)
+	if err != nil {
+		return "", fmt.Errorf("detector: failed to create the tuned configuration profile file: %w", err)
+	}
+
+	defer func() {
+		if err := profileFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	if _, err := profileFile.Write(data); err != nil {
+		return "", fmt.Errorf("detector: failed to write the tuned configuration profile file: %w", err)
+	}
+
+	return profilePath, nil
+}
+
+// Perform the coordinate search over the threshold candidates of the three frame metrics. Each round is searching the best candidate of
+// a single metric while the other thresholds are fixed. The search is finished when a round is not improving the score.
+func searchTuneThresholds(candidates [3][]float64, evaluate func(values [3]float64) (TuneResult, error)) (TuneResult, error) {
+	var (
+		indexes [3]int
+		values  [3]float64
+	)
+
+	for metric := range candidates {
+		indexes[metric] = len(candidates[metric]) / 2
+		values[metric] = candidates[metric][indexes[metric]]
+	}
+
+	best, err := evaluate(values)
+	if err != nil {
+		return TuneResult{}, err
+	}
+
+	for range tuneMaxRounds {
+		improved := false
+
+		for metric := range candidates {
+			for index, candidate := range candidates[metric] {
+				if index == indexes[metric] {
+					continue
+				}
+
+				trialValues := values
+				trialValues[metric] = candidate
+
+				result, err := evaluate(trialValues)
+				if err != nil {
+					return TuneResult{}, err
+				}
+
+				if result.Score > best.Score {
+					best, values, indexes[metric], improved = result, trialValues, index, true
+				}
+			}
+		}
+
+		if !improved {
+			break
+		}
+	}
+
+	return best, nil
+}
+
+// Create the threshold candidates of the brightness, color difference and binary threshold difference as the evenly spaced percentiles
+// of the positive frame metrics deviations, which are compared to the thresholds by the given detection strategy.
+func createTuneCandidates(frames frame.FrameCollection, entries []statistics.DescriptiveStatisticsEntry, strategy options.DetectionStrategy, steps int) [3][]float64 {
+	var deviations [3][]float64
+
+	for index, f := range frames.GetAll() {
+		s := entries[index]
+
+		var values [3]float64
+		switch strategy {
+		case options.AboveMovingMeanAllWeights:
+			values = [3]float64{f.Brightness - s.BrightnessMovingMeanAtPoint, f.ColorDifference - s.ColorDifferenceMovingMeanAtPoint, f.BinaryThresholdDifference - s.BinaryThresholdDifferenceMovingMeanAtPoint}
+		case options.AboveGlobalMeanAllWeights:
+			values = [3]float64{f.Brightness - s.BrightnessMean, f.ColorDifference - s.ColorDifferenceMean, f.BinaryThresholdDifference - s.BinaryThresholdDifferenceMean}
+		case options.AboveZeroAllWeights:
+			values = [3]float64{f.Brightness, f.ColorDifference, f.BinaryThresholdDifference}
+		case options.AboveMovingMedianAllWeights:
+			values = [3]float64{f.Brightness - s.BrightnessMovingMedianAtPoint, f.ColorDifference - s.ColorDifferenceMovingMedianAtPoint, f.BinaryThresholdDifference - s.BinaryThresholdDifferenceMovingMedianAtPoint}
+		case options.AboveMovingZScoreAllWeights:
+			values = [3]float64{
+				zScore(f.Brightness, s.BrightnessMovingMeanAtPoint, s.BrightnessMovingStdDevAtPoint),
+				zScore(f.ColorDifference, s.ColorDifferenceMovingMeanAtPoint, s.ColorDifferenceMovingStdDevAtPoint),
+				zScore(f.BinaryThresholdDifference, s.BinaryThresholdDifferenceMovingMeanAtPoint, s.BinaryThresholdDifferenceMovingStdDevAtPoint),
+			}
+		default:
+			panic("detector: invalid detection strategy specified")
+		}
+
+		for metric, value := range values {
+			if value > 0 && !math.IsInf(value, 0) {
+				deviations[metric] = append(deviations[metric], value)
+			}
+		}
+	}
+
+	var candidates [3][]float64
+	for metric := range candidates {
+		candidates[metric] = []float64{0}
+
+		if len(deviations[metric]) == 0 {
+			continue
+		}
+
+		for step := range steps {
+			candidates[metric] = append(candidates[metric], utils.Percentile(deviations[metric], 100*float64(step)/float64(steps-1)))
+		}
+
+		slices.Sort(candidates[metric])
+		candidates[metric] = slices.Compact(candidates[metric])
+	}
+
+	return candidates
+}
+
+// Create a copy of the options with the brightness, color difference and binary threshold difference thresholds set. The z-score detection
+// strategy is using the z-score thresholds instead of the absolute ones.
+func withTuneThresholds(o options.DetectorOptions, values [3]float64) options.DetectorOptions {
+	o = o.Clone()
+
+	if o.DetectionStrategy == options.AboveMovingZScoreAllWeights {
+		o.BrightnessDetectionZScore = values[0]
+		o.ColorDifferenceDetectionZScore = values[1]
+		o.BinaryThresholdDifferenceDetectionZScore = values[2]
+	} else {
+		o.BrightnessDetectionThreshold = values[0]
+		o.ColorDifferenceDetectionThreshold = values[1]
+		o.BinaryThresholdDifferenceDetectionThreshold = values[2]
+	}
+
+	return o
+}
+
+// Calculate the score of the detection using the metric specified by the tune options. The recall at minimal precision metric is using
+// the negative precision distance as the score of detections below the minimal precision in order to guide the search.
+func tuneScore(cm statistics.ConfusionMatrix, o options.TuneOptions) float64 {
+	switch o.Metric {
+	case options.FScoreMetric:
+		return cm.Fs
+	case options.MccMetric:
+		return cm.Mcc
+	case options.RecallAtPrecisionMetric:
+		if cm.Ppv >= o.MinPrecision {
+			return cm.Tpr
+		}
+
+		return cm.Ppv - o.MinPrecision
+	default:
+		panic("detector: invalid tune metric specified")
+	}
+}
+
+func tableTuneResult(p printer.Printer, result TuneResult) {
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 6, 64)
+	}
+
+	table := [][]string{
+		{"Detection strategy", result.Options.DetectionStrategy.String()},
+		{"Moving mean resolution", strconv.Itoa(int(result.Options.MovingMeanResolution))},
+	}
+
+	if result.Options.DetectionStrategy == options.AboveMovingZScoreAllWeights {
+		table = append(table,
+			[]string{"Brightness z-score", formatFloat(result.Options.BrightnessDetectionZScore)},
+			[]string{"Color difference z-score", formatFloat(result.Options.ColorDifferenceDetectionZScore)},
+			[]string{"Binary threshold difference z-score", formatFloat(result.Options.BinaryThresholdDifferenceDetectionZScore)})
+	} else {
+		table = append(table,
+			[]string{"Brightness threshold", formatFloat(result.Options.BrightnessDetectionThreshold)},
+			[]string{"Color difference threshold", formatFloat(result.Options.ColorDifferenceDetectionThreshold)},
+			[]string{"Binary threshold difference threshold", formatFloat(result.Options.BinaryThresholdDifferenceDetectionThreshold)})
+	}
+
+	table = append(table,
+		[]string{"True positives", strconv.Itoa(int(result.ConfusionMatrix.Tp))},
+		[]string{"False positives", strconv.Itoa(int(result.ConfusionMatrix.Fp))},
+		[]string{"False negatives", strconv.Itoa(int(result.ConfusionMatrix.Fn))},
+		[]string{"Precision", formatFloat(result.ConfusionMatrix.Ppv)},
+		[]string{"Recall", formatFloat(result.ConfusionMatrix.Tpr)},
+		[]string{"F-Score", formatFloat(result.ConfusionMatrix.Fs)},
+		[]string{"MCC", formatFloat(result.ConfusionMatrix.Mcc)},
+		[]string{"Trials", strconv.Itoa(result.Trials)})
+
+	p.Table(table)
+}
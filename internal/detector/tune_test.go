@@ -0,0 +1,135 @@
+package detector
+
+import (
+	"context"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+)
+
+func mockTuneFrameCollection() (frame.FrameCollection, []int) {
+	fc := frame.NewFrameCollection(600)
+	groundTruth := make([]int, 0)
+
+	for index := range 600 {
+		value := 0.1 + 0.02*math.Sin(float64(index)*1.7)
+		if index%100 == 50 {
+			value = 0.6
+			groundTruth = append(groundTruth, index+1)
+		}
+
+		fc.Push(&frame.Frame{
+			OrdinalNumber:             index + 1,
+			Brightness:                value,
+			ColorDifference:           value,
+			BinaryThresholdDifference: value,
+		})
+	}
+
+	fc.Lock()
+	return fc, groundTruth
+}
+
+func mockTuneOptions() options.TuneOptions {
+	tuneOptions := options.GetDefaultTuneOptions()
+	tuneOptions.GroundTruthExpression = "51"
+	return tuneOptions
+}
+
+func TestTunerShouldFindTheOptionsSeparatingTheGroundTruth(t *testing.T) {
+	fc, groundTruth := mockTuneFrameCollection()
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	metrics := []options.TuneMetric{
+		options.FScoreMetric,
+		options.MccMetric,
+		options.RecallAtPrecisionMetric,
+	}
+
+	for _, metric := range metrics {
+		tuneOptions := mockTuneOptions()
+		tuneOptions.Metric = metric
+		tuneOptions.ThresholdSteps = 10
+		tuneOptions.MovingMeanResolutions = []int{25, 50}
+
+		instance, err := CreateTuner(p, options.GetDefaultDetectorOptions(), tuneOptions)
+		assert.Nil(t, err)
+
+		result, err := instance.(*tuner).Search(context.Background(), fc, groundTruth, func(o options.DetectorOptions) (statistics.DescriptiveStatistics, error) {
+			return statistics.CreateDescriptiveStatistics(fc, int(o.MovingMeanResolution), o.DetectionStrategy == options.AboveMovingMedianAllWeights), nil
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, len(groundTruth), int(result.ConfusionMatrix.Tp))
+		assert.Zero(t, result.ConfusionMatrix.Fp)
+		assert.Greater(t, result.Trials, 0)
+
+		valid, msg := result.Options.AreValid()
+		assert.True(t, valid, msg)
+	}
+}
+
+func TestTunerShouldRespectTheContextCancellation(t *testing.T) {
+	fc, groundTruth := mockTuneFrameCollection()
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	instance, err := CreateTuner(p, options.GetDefaultDetectorOptions(), mockTuneOptions())
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = instance.(*tuner).Search(ctx, fc, groundTruth, func(o options.DetectorOptions) (statistics.DescriptiveStatistics, error) {
+		return statistics.CreateDescriptiveStatistics(fc, int(o.MovingMeanResolution), o.DetectionStrategy == options.AboveMovingMedianAllWeights), nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTunerShouldFailWithoutGroundTruthWithinTheFrames(t *testing.T) {
+	fc, _ := mockTuneFrameCollection()
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	instance, err := CreateTuner(p, options.GetDefaultDetectorOptions(), mockTuneOptions())
+	assert.Nil(t, err)
+
+	_, err = instance.(*tuner).Search(context.Background(), fc, []int{1000, 2000}, func(o options.DetectorOptions) (statistics.DescriptiveStatistics, error) {
+		return statistics.CreateDescriptiveStatistics(fc, int(o.MovingMeanResolution), o.DetectionStrategy == options.AboveMovingMedianAllWeights), nil
+	})
+
+	assert.NotNil(t, err)
+}
+
+func TestCreateTunerShouldValidateTheOptions(t *testing.T) {
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	tuneOptions := mockTuneOptions()
+	tuneOptions.ThresholdSteps = 0
+
+	_, err := CreateTuner(p, options.GetDefaultDetectorOptions(), tuneOptions)
+	assert.NotNil(t, err)
+
+	_, err = CreateTuner(nil, options.GetDefaultDetectorOptions(), mockTuneOptions())
+	assert.NotNil(t, err)
+}
+
+func TestTunerShouldRequireTheVideoForExponentialStatistics(t *testing.T) {
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	o := options.GetDefaultDetectorOptions()
+	o.StatisticsBackend = options.ExponentialStatistics
+	o.CacheDirectoryPath = t.TempDir()
+
+	instance, err := CreateTuner(p, o, mockTuneOptions())
+	assert.Nil(t, err)
+
+	_, err = instance.Run("", t.TempDir(), context.Background())
+	assert.ErrorContains(t, err, "exponential statistics")
+}
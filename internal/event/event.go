@@ -0,0 +1,170 @@
+package event
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+)
+
+// Structure representing a single lightning strike event which is a group of adjacent or near-adjacent detected frames.
+// The frame values are represented as frame ordinal numbers (1 indexed). The UTC times are zero if the video creation time is not known.
+type StrikeEvent struct {
+	Index                         int       `json:"index"`
+	StartFrame                    int       `json:"start-frame"`
+	EndFrame                      int       `json:"end-frame"`
+	PeakFrame                     int       `json:"peak-frame"`
+	DetectedFramesCount           int       `json:"detected-frames-count"`
+	StartTime                     float64   `json:"start-time"`
+	Duration                      float64   `json:"duration"`
+	StartTimeUtc                  time.Time `json:"start-time-utc"`
+	PeakTimeUtc                   time.Time `json:"peak-time-utc"`
+	PeakBrightnessDelta           float64   `json:"peak-brightness-delta"`
+	BrightnessMean                float64   `json:"brightness-mean"`
+	BrightnessMax                 float64   `json:"brightness-max"`
+	ColorDifferenceMean           float64   `json:"color-difference-mean"`
+	ColorDifferenceMax            float64   `json:"color-difference-max"`
+	BinaryThresholdDifferenceMean float64   `json:"binary-threshold-difference-mean"`
+	BinaryThresholdDifferenceMax  float64   `json:"binary-threshold-difference-max"`
+}
+
+// Group the detected frames specified by the 0-based indexes into strike events. Two detections are merged into a single event
+// if the amount of not detected frames between them is not greater than the gap tolerance. The event start time and duration in
+// seconds are calculated using the frames timestamps, which are also valid for variable frame rate videos. The frames per second
+// value is used if the frames are not carrying timestamps and a zero fps value will result in zero time values. The detection indexes
+// are absolute, therefore the frame collection can represent a range of the video which is not starting at the first frame. The
+// origin is the UTC time of the video start used together with the frames timestamps to calculate the event UTC times.
+func CreateStrikeEvents(fc frame.FrameCollection, ds statistics.DescriptiveStatistics, detections []int, fps float64, gapTolerance int, origin time.Time) ([]StrikeEvent, error) {
+	if gapTolerance < 0 {
+		return nil, fmt.Errorf("event: the gap tolerance can not be negative")
+	}
+
+	if fps < 0 {
+		return nil, fmt.Errorf("event: the frames per second value can not be negative")
+	}
+
+	var (
+		frames  []*frame.Frame = fc.GetAll()
+		indexes []int          = slices.Clone(detections)
+		events  []StrikeEvent  = make([]StrikeEvent, 0)
+		offset  int            = 0
+	)
+
+	if len(frames) > 0 {
+		offset = frames[0].OrdinalNumber - 1
+	}
+
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	for position, index := range indexes {
+		index -= offset
+		if index < 0 || index >= len(frames) || index >= len(ds.BrightnessMovingMean) {
+			return nil, fmt.Errorf("event: the detection index is out of the frames range")
+		}
+
+		indexes[position] = index
+	}
+
+	groupStart := 0
+	for position := 1; position <= len(indexes); position += 1 {
+		if position < len(indexes) && indexes[position]-indexes[position-1]-1 <= gapTolerance {
+			continue
+		}
+
+		events = append(events, createStrikeEvent(frames, ds, indexes[groupStart:position], len(events), fps, origin))
+		groupStart = position
+	}
+
+	return events, nil
+}
+
+func createStrikeEvent(frames []*frame.Frame, ds statistics.DescriptiveStatistics, indexes []int, eventIndex int, fps float64, origin time.Time) StrikeEvent {
+	var (
+		startIndex int = indexes[0]
+		endIndex   int = indexes[len(indexes)-1]
+		peakIndex  int = indexes[0]
+		count      int = len(indexes)
+	)
+
+	event := StrikeEvent{
+		Index:               eventIndex,
+		StartFrame:          frames[startIndex].OrdinalNumber,
+		EndFrame:            frames[endIndex].OrdinalNumber,
+		PeakFrame:           frames[startIndex].OrdinalNumber,
+		DetectedFramesCount: count,
+		PeakBrightnessDelta: frames[startIndex].Brightness - ds.BrightnessMovingMean[startIndex],
+	}
+
+	for _, index := range indexes {
+		f := frames[index]
+
+		if delta := f.Brightness - ds.BrightnessMovingMean[index]; delta > event.PeakBrightnessDelta {
+			event.PeakBrightnessDelta = delta
+			event.PeakFrame = f.OrdinalNumber
+			peakIndex = index
+		}
+
+		event.BrightnessMean += f.Brightness
+		event.ColorDifferenceMean += f.ColorDifference
+		event.BinaryThresholdDifferenceMean += f.BinaryThresholdDifference
+
+		event.BrightnessMax = math.Max(event.BrightnessMax, f.Brightness)
+		event.ColorDifferenceMax = math.Max(event.ColorDifferenceMax, f.ColorDifference)
+		event.BinaryThresholdDifferenceMax = math.Max(event.BinaryThresholdDifferenceMax, f.BinaryThresholdDifference)
+	}
+
+	countf := float64(count)
+	event.BrightnessMean /= countf
+	event.ColorDifferenceMean /= countf
+	event.BinaryThresholdDifferenceMean /= countf
+
+	var peakTime float64
+	if hasFrameTimestamps(frames) {
+		event.StartTime = frames[startIndex].Timestamp
+		event.Duration = frames[endIndex].Timestamp + frameDuration(frames, endIndex, fps) - event.StartTime
+		peakTime = frames[peakIndex].Timestamp
+	} else if fps > 0 {
+		event.StartTime = float64(event.StartFrame-1) / fps
+		event.Duration = float64(event.EndFrame-event.StartFrame+1) / fps
+		peakTime = float64(event.PeakFrame-1) / fps
+	}
+
+	if !origin.IsZero() {
+		event.StartTimeUtc = origin.Add(secondsToDuration(event.StartTime)).UTC()
+		event.PeakTimeUtc = origin.Add(secondsToDuration(peakTime)).UTC()
+	}
+
+	return event
+}
+
+// Check if the frames are carrying the presentation timestamps. The timestamps are relative to the first frame of the video,
+// therefore only the first frame of the video can have a zero timestamp.
+func hasFrameTimestamps(frames []*frame.Frame) bool {
+	return len(frames) > 1 && frames[len(frames)-1].Timestamp > 0
+}
+
+// Calculate the display duration of the frame specified by the index using the timestamp of the following frame. The frame
+// rate or the duration of the preceding frame is used for the last frame.
+func frameDuration(frames []*frame.Frame, index int, fps float64) float64 {
+	if index+1 < len(frames) {
+		return frames[index+1].Timestamp - frames[index].Timestamp
+	}
+
+	if fps > 0 {
+		return 1 / fps
+	}
+
+	if index > 0 {
+		return frames[index].Timestamp - frames[index-1].Timestamp
+	}
+
+	return 0
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
@@ -0,0 +1,180 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+)
+
+func TestCreateStrikeEventsShouldNotCreateForInvalidArguments(t *testing.T) {
+	fc, ds := mockFramesAndStatistics([]float64{0.1, 0.2, 0.3})
+
+	_, err := CreateStrikeEvents(fc, ds, []int{0}, 30, -1, time.Time{})
+	assert.NotNil(t, err)
+
+	_, err = CreateStrikeEvents(fc, ds, []int{0}, -1, 0, time.Time{})
+	assert.NotNil(t, err)
+
+	_, err = CreateStrikeEvents(fc, ds, []int{3}, 30, 0, time.Time{})
+	assert.NotNil(t, err)
+
+	_, err = CreateStrikeEvents(fc, ds, []int{-1}, 30, 0, time.Time{})
+	assert.NotNil(t, err)
+}
+
+func TestCreateStrikeEventsShouldGroupDetections(t *testing.T) {
+	cases := []struct {
+		Detections     []int
+		GapTolerance   int
+		ExpectedRanges [][2]int
+	}{
+		{[]int{}, 0, [][2]int{}},
+		{[]int{2}, 0, [][2]int{{3, 3}}},
+		{[]int{2, 3, 4}, 0, [][2]int{{3, 5}}},
+		{[]int{2, 4}, 0, [][2]int{{3, 3}, {5, 5}}},
+		{[]int{2, 4}, 1, [][2]int{{3, 5}}},
+		{[]int{1, 2, 5, 6, 9}, 1, [][2]int{{2, 3}, {6, 7}, {10, 10}}},
+		{[]int{1, 2, 5, 6, 9}, 2, [][2]int{{2, 10}}},
+		{[]int{9, 1, 2, 2}, 0, [][2]int{{2, 3}, {10, 10}}},
+	}
+
+	fc, ds := mockFramesAndStatistics([]float64{0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1})
+
+	for _, c := range cases {
+		events, err := CreateStrikeEvents(fc, ds, c.Detections, 10, c.GapTolerance, time.Time{})
+		assert.Nil(t, err)
+		assert.Len(t, events, len(c.ExpectedRanges))
+
+		for index, expected := range c.ExpectedRanges {
+			assert.Equal(t, index, events[index].Index)
+			assert.Equal(t, expected[0], events[index].StartFrame)
+			assert.Equal(t, expected[1], events[index].EndFrame)
+		}
+	}
+}
+
+func TestCreateStrikeEventsShouldCalculateEventValues(t *testing.T) {
+	fc, ds := mockFramesAndStatistics([]float64{0.1, 0.5, 0.9, 0.7, 0.1})
+
+	events, err := CreateStrikeEvents(fc, ds, []int{1, 2, 3}, 2, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, 2, event.StartFrame)
+	assert.Equal(t, 4, event.EndFrame)
+	assert.Equal(t, 3, event.PeakFrame)
+	assert.Equal(t, 3, event.DetectedFramesCount)
+	assert.InDelta(t, 0.5, event.StartTime, delta)
+	assert.InDelta(t, 1.5, event.Duration, delta)
+	assert.InDelta(t, 0.9-ds.BrightnessMovingMean[2], event.PeakBrightnessDelta, delta)
+	assert.InDelta(t, 0.7, event.BrightnessMean, delta)
+	assert.InDelta(t, 0.9, event.BrightnessMax, delta)
+
+	events, err = CreateStrikeEvents(fc, ds, []int{1, 2, 3}, 0, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 0.0, events[0].StartTime)
+	assert.Equal(t, 0.0, events[0].Duration)
+}
+
+func TestCreateStrikeEventsShouldUseAbsoluteIndexesForOffsetFrames(t *testing.T) {
+	fc, ds := mockOffsetFramesAndStatistics([]float64{0.1, 0.5, 0.9, 0.7, 0.1}, 100)
+
+	_, err := CreateStrikeEvents(fc, ds, []int{1}, 2, 0, time.Time{})
+	assert.NotNil(t, err)
+
+	events, err := CreateStrikeEvents(fc, ds, []int{101, 102, 103}, 2, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, 102, event.StartFrame)
+	assert.Equal(t, 104, event.EndFrame)
+	assert.Equal(t, 103, event.PeakFrame)
+	assert.InDelta(t, 50.5, event.StartTime, delta)
+	assert.InDelta(t, 0.9-ds.BrightnessMovingMean[2], event.PeakBrightnessDelta, delta)
+}
+
+func TestCreateStrikeEventsShouldCalculateUtcTimesFromOrigin(t *testing.T) {
+	fc, ds := mockFramesAndStatistics([]float64{0.1, 0.5, 0.9, 0.7, 0.1})
+	for _, f := range fc.GetAll() {
+		f.Timestamp = float64(f.OrdinalNumber-1) * 0.25
+	}
+
+	origin := time.Date(2024, 7, 14, 21, 3, 10, 0, time.UTC)
+
+	events, err := CreateStrikeEvents(fc, ds, []int{1, 2, 3}, 4, 0, origin)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, origin.Add(250*time.Millisecond), events[0].StartTimeUtc)
+	assert.Equal(t, origin.Add(500*time.Millisecond), events[0].PeakTimeUtc)
+
+	events, err = CreateStrikeEvents(fc, ds, []int{1, 2, 3}, 4, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.True(t, events[0].StartTimeUtc.IsZero())
+	assert.True(t, events[0].PeakTimeUtc.IsZero())
+}
+
+func TestCreateStrikeEventsShouldCalculateUtcTimesWithoutFrameTimestamps(t *testing.T) {
+	fc, ds := mockFramesAndStatistics([]float64{0.1, 0.5, 0.9, 0.7, 0.1})
+
+	origin := time.Date(2024, 7, 14, 21, 3, 10, 0, time.UTC)
+
+	events, err := CreateStrikeEvents(fc, ds, []int{1, 2, 3}, 4, 0, origin)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, origin.Add(250*time.Millisecond), events[0].StartTimeUtc)
+	assert.Equal(t, origin.Add(500*time.Millisecond), events[0].PeakTimeUtc)
+
+	events, err = CreateStrikeEvents(fc, ds, []int{3}, 4, 0, origin)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, origin.Add(750*time.Millisecond), events[0].StartTimeUtc)
+	assert.Equal(t, origin.Add(750*time.Millisecond), events[0].PeakTimeUtc)
+}
+
+func TestCreateStrikeEventsShouldUseFrameTimestampsForVariableFrameRate(t *testing.T) {
+	fc, ds := mockFramesAndStatistics([]float64{0.1, 0.5, 0.9, 0.7, 0.1})
+	for index, f := range fc.GetAll() {
+		f.Timestamp = []float64{0, 0.1, 0.15, 0.4, 0.45}[index]
+	}
+
+	events, err := CreateStrikeEvents(fc, ds, []int{1, 2, 3}, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.InDelta(t, 0.1, events[0].StartTime, delta)
+	assert.InDelta(t, 0.35, events[0].Duration, delta)
+
+	events, err = CreateStrikeEvents(fc, ds, []int{3, 4}, 10, 0, time.Time{})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.InDelta(t, 0.4, events[0].StartTime, delta)
+	assert.InDelta(t, 0.15, events[0].Duration, delta)
+}
+
+const delta float64 = 1e-9
+
+func mockFramesAndStatistics(brightness []float64) (frame.FrameCollection, statistics.DescriptiveStatistics) {
+	return mockOffsetFramesAndStatistics(brightness, 0)
+}
+
+func mockOffsetFramesAndStatistics(brightness []float64, offset int) (frame.FrameCollection, statistics.DescriptiveStatistics) {
+	fc := frame.NewOffsetFrameCollection(len(brightness), offset)
+
+	for index, value := range brightness {
+		fc.Push(&frame.Frame{
+			OrdinalNumber:             offset + index + 1,
+			Brightness:                value,
+			ColorDifference:           value,
+			BinaryThresholdDifference: value,
+		})
+	}
+
+	fc.Lock()
+	return fc, statistics.CreateDescriptiveStatistics(fc, 2, false)
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+type BatchStatus string
+
+const (
+	BatchProcessed   BatchStatus = "processed"
+	BatchSkipped     BatchStatus = "skipped"
+	BatchFailed      BatchStatus = "failed"
+	BatchInterrupted BatchStatus = "interrupted"
+)
+
+// Structure representing the detection results of a single video processed in the batch mode. The paths are relative to the
+// batch input and output directories. The checksum is covering the detection and export options used to process the video.
+type BatchSummaryEntry struct {
+	InputPath       string      `json:"input-path"`
+	OutputDirectory string      `json:"output-directory"`
+	Status          BatchStatus `json:"status"`
+	Frames          int         `json:"frames"`
+	Detections      int         `json:"detections"`
+	Events          int         `json:"events"`
+	Duration        float64     `json:"duration"`
+	Error           string      `json:"error"`
+	Checksum        string      `json:"checksum"`
+}
+
+// Export the batch summary index as a JSON and CSV file to the batch output directory.
+func ExportBatchSummary(outputDirectoryPath string, entries []BatchSummaryEntry) error {
+	if err := exportJsonBatchSummary(outputDirectoryPath, entries); err != nil {
+		return fmt.Errorf("export: failed to export the json batch summary: %w", err)
+	}
+
+	if err := exportCsvBatchSummary(outputDirectoryPath, entries); err != nil {
+		return fmt.Errorf("export: failed to export the csv batch summary: %w", err)
+	}
+
+	return nil
+}
+
+// Import the batch summary index from the JSON file of the batch output directory. A nil slice is returned if the summary does not exist.
+func ImportBatchSummary(outputDirectoryPath string) ([]BatchSummaryEntry, error) {
+	jsonBatchSummaryPath := path.Join(outputDirectoryPath, JsonBatchSummaryFilename)
+	if !utils.FileExists(jsonBatchSummaryPath) {
+		return nil, nil
+	}
+
+	batchSummaryFile, err := os.Open(jsonBatchSummaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to open the json batch summary file: %w", err)
+	}
+
+	defer batchSummaryFile.Close()
+
+	entries := make([]BatchSummaryEntry, 0)
+	if err := json.NewDecoder(batchSummaryFile).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("export: failed to decode the json batch summary: %w", err)
+	}
+
+	return entries, nil
+}
+
+func exportJsonBatchSummary(outputDirectoryPath string, entries []BatchSummaryEntry) error {
+	jsonBatchSummaryPath := path.Join(outputDirectoryPath, JsonBatchSummaryFilename)
+	batchSummaryFile, err := utils.CreateFileWithTree(jsonBatchSummaryPath)
+	if err != nil {
+		return fmt.Errorf("export: failed to create the json batch summary file: %w", err)
+	}
+
+	defer func() {
+		if err := batchSummaryFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	encoder := createEncoder(batchSummaryFile)
+
+	if err := encoder.Encode(entries); err != nil {
+		return fmt.Errorf("export: failed to encode the batch summary entries: %w", err)
+	}
+
+	return nil
+}
+
+func exportCsvBatchSummary(outputDirectoryPath string, entries []BatchSummaryEntry) error {
+	csvBatchSummaryPath := path.Join(outputDirectoryPath, CsvBatchSummaryFilename)
+	batchSummaryFile, err := utils.CreateFileWithTree(csvBatchSummaryPath)
+	if err != nil {
+		return fmt.Errorf("export: failed to create the csv batch summary file: %w", err)
+	}
+
+	defer func() {
+		if err := batchSummaryFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	writer := csv.NewWriter(batchSummaryFile)
+
+	if err := writer.Write([]string{"InputPath", "OutputDirectory", "Status", "Frames", "Detections", "Events", "Duration", "Error", "Checksum"}); err != nil {
+		return fmt.Errorf("export: failed to write the header to the batch summary file: %w", err)
+	}
+
+	for _, entry := range entries {
+		row := []string{
+			entry.InputPath,
+			entry.OutputDirectory,
+			string(entry.Status),
+			strconv.Itoa(entry.Frames),
+			strconv.Itoa(entry.Detections),
+			strconv.Itoa(entry.Events),
+			strconv.FormatFloat(entry.Duration, 'f', -1, 64),
+			entry.Error,
+			entry.Checksum,
+		}
+
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("export: failed to write the entry row to the batch summary file: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
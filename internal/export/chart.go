@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
@@ -16,12 +17,13 @@ type ChartData struct {
 	FrameCollection                             frame.FrameCollection
 	DescriptiveStatistics                       statistics.DescriptiveStatistics
 	Detections                                  []int
+	StrikeEvents                                []event.StrikeEvent
 	BrightnessDetectionThreshold                float64
 	ColorDifferenceDetectionThreshold           float64
 	BinaryThresholdDifferenceDetectionThreshold float64
 }
 
-func exportFramesChart(outputDirectoryPath string, fc frame.FrameCollection, ds statistics.DescriptiveStatistics, det []int, brightnessT float64, colorDiffT float64, btDiffT float64) (string, error) {
+func exportFramesChart(outputDirectoryPath string, fc frame.FrameCollection, ds statistics.DescriptiveStatistics, det []int, events []event.StrikeEvent, brightnessT float64, colorDiffT float64, btDiffT float64) (string, error) {
 	framesChartPath := path.Join(outputDirectoryPath, FramesChartFilename)
 	framesChartFile, err := utils.CreateFileWithTree(framesChartPath)
 	if err != nil {
@@ -66,6 +68,11 @@ func exportFramesChart(outputDirectoryPath string, fc frame.FrameCollection, ds
 
 	frames := fc.GetAll()
 
+	offset := 0
+	if len(frames) > 0 {
+		offset = frames[0].OrdinalNumber - 1
+	}
+
 	detectionsMap := make(map[int]int, len(det))
 	for _, detectionIndex := range det {
 		detectionsMap[detectionIndex] = detectionIndex
@@ -85,10 +92,10 @@ func exportFramesChart(outputDirectoryPath string, fc frame.FrameCollection, ds
 	)
 
 	for frameIndex, frame := range frames {
-		xAxis = append(xAxis, frameIndex+1)
+		xAxis = append(xAxis, frame.OrdinalNumber)
 
 		var symbol string
-		if _, ok := detectionsMap[frameIndex]; ok {
+		if _, ok := detectionsMap[frame.OrdinalNumber-1]; ok {
 			symbol = "arrow"
 		} else {
 			symbol = "circle"
@@ -139,7 +146,8 @@ func exportFramesChart(outputDirectoryPath string, fc frame.FrameCollection, ds
 
 	chart.SetXAxis(xAxis)
 
-	chart.AddSeries("Brightness", brightness, getSeriesOptions("#D4BEE4")...)
+	brightnessSeriesOptions := append(getSeriesOptions("#D4BEE4"), getStrikeEventsMarkAreaOptions(events, offset, "#FFE3A3")...)
+	chart.AddSeries("Brightness", brightness, brightnessSeriesOptions...)
 
 	chart.AddSeries("Brightness moving mean", brightnessMovingMean, getSeriesOptions("#9B7EBD")...)
 
@@ -177,3 +185,30 @@ func getSeriesOptions(color string) []charts.SeriesOpts {
 
 	return options
 }
+
+// NOTE: The x-axis is a category axis, therefore the mark area coordinates are frame indexes and not the frame ordinal numbers.
+// The offset is the amount of frames preceding the first frame of the collection and is subtracted from the event positions.
+func getStrikeEventsMarkAreaOptions(events []event.StrikeEvent, offset int, color string) []charts.SeriesOpts {
+	if len(events) == 0 {
+		return []charts.SeriesOpts{}
+	}
+
+	items := make([]opts.MarkAreaNameCoordItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, opts.MarkAreaNameCoordItem{
+			Name:        fmt.Sprintf("Event %d", event.Index),
+			Coordinate0: []interface{}{event.StartFrame - 1 - offset, "min"},
+			Coordinate1: []interface{}{event.EndFrame - 1 - offset, "max"},
+		})
+	}
+
+	return []charts.SeriesOpts{
+		charts.WithMarkAreaNameCoordItemOpts(items...),
+		charts.WithMarkAreaStyleOpts(opts.MarkAreaStyle{
+			ItemStyle: &opts.ItemStyle{
+				Color:   color,
+				Opacity: 0.4,
+			},
+		}),
+	}
+}
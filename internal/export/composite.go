@@ -0,0 +1,299 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// The maximal amount of frames used to calculate the median background for the lighten blend mode.
+const compositeBackgroundFramesLimit int = 25
+
+func (exporter *exporter) ExportCompositeImages(detections []int, events []event.StrikeEvent) error {
+	compositeExportTime := time.Now()
+	exporter.Printer.Debug("Starting the composite images export stage.")
+
+	if len(detections) == 0 {
+		exporter.Printer.Warning("No detections available. The composite images export will be skipped.")
+		return nil
+	}
+
+	var (
+		mode       options.CompositeBlendMode = exporter.Options.CompositeBlendMode
+		indexes    []int                      = slices.Clone(detections)
+		background *image.RGBA
+		err        error
+	)
+
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	if mode == options.LightenOverMedianBlend {
+		if background, err = exporter.CreateCompositeBackground(indexes, events); err != nil {
+			return fmt.Errorf("export: failed to create the composite background image: %w", err)
+		}
+
+		if background == nil {
+			exporter.Printer.Warning("No frames available to create the composite background. Falling back to the max blend mode.")
+			mode = options.MaxBlend
+		}
+	}
+
+	eventIndexes := make(map[int]int, len(indexes))
+	for _, strikeEvent := range events {
+		for frameIndex := strikeEvent.StartFrame - 1; frameIndex <= strikeEvent.EndFrame-1; frameIndex += 1 {
+			eventIndexes[frameIndex] = strikeEvent.Index
+		}
+	}
+
+	var (
+		stack           *compositeStack
+		eventStack      *compositeStack
+		eventStackIndex int
+		exportTotal     bool = exporter.Options.ExportCompositeImage
+		exportEvent     bool = exporter.Options.ExportStrikeEventCompositeImages && len(events) > 0
+	)
+
+	flushEventStack := func() error {
+		if eventStack == nil {
+			return nil
+		}
+
+		eventCompositePath := path.Join(exporter.OutputDirPath, fmt.Sprintf(StrikeEventCompositeImageFilename, eventStackIndex))
+		if err := utils.ExportImageAsPng(eventCompositePath, eventStack.Result()); err != nil {
+			return fmt.Errorf("export: failed to export the strike event composite image: %w", err)
+		}
+
+		exporter.Printer.Info("Event: [%d/%d]. Strike event composite image exported at: %s", eventStackIndex+1, len(events), eventCompositePath)
+
+		eventStack = nil
+		return nil
+	}
+
+	progressStep, progressFinalize := exporter.Printer.ProgressSteps("Composite images export stage.", len(indexes))
+
+	err = exporter.ReadTargetFrames(indexes, func(frameIndex int, frame *image.RGBA) error {
+		defer progressStep()
+
+		if exportTotal {
+			if stack == nil {
+				stack = newCompositeStack(frame.Bounds().Dx(), frame.Bounds().Dy(), mode, background)
+			}
+
+			if err := stack.Push(frame); err != nil {
+				return fmt.Errorf("export: failed to push the frame to the composite stack: %w", err)
+			}
+		}
+
+		if !exportEvent {
+			return nil
+		}
+
+		eventIndex, ok := eventIndexes[frameIndex]
+		if eventStack != nil && (!ok || eventIndex != eventStackIndex) {
+			if err := flushEventStack(); err != nil {
+				return err
+			}
+		}
+
+		if !ok {
+			return nil
+		}
+
+		if eventStack == nil {
+			eventStack = newCompositeStack(frame.Bounds().Dx(), frame.Bounds().Dy(), mode, background)
+			eventStackIndex = eventIndex
+		}
+
+		if err := eventStack.Push(frame); err != nil {
+			return fmt.Errorf("export: failed to push the frame to the strike event composite stack: %w", err)
+		}
+
+		if frameIndex != events[eventIndex].EndFrame-1 {
+			return nil
+		}
+
+		return flushEventStack()
+	})
+
+	progressFinalize()
+
+	if err != nil {
+		return fmt.Errorf("export: failed to read the composite frames: %w", err)
+	}
+
+	// NOTE: The video reading may stop before the last frame of the strike event (Example: the video is shorter than
+	// the probed frames count), therefore the partially read strike event composite image is exported on its own.
+	if eventStack != nil {
+		exporter.Printer.Warning("Not all frames of the strike event %d were read. The strike event composite image is incomplete.", eventStackIndex)
+
+		if err := flushEventStack(); err != nil {
+			return err
+		}
+	}
+
+	if exportTotal && stack != nil {
+		compositePath := path.Join(exporter.OutputDirPath, CompositeImageFilename)
+		if err := utils.ExportImageAsPng(compositePath, stack.Result()); err != nil {
+			return fmt.Errorf("export: failed to export the composite image: %w", err)
+		}
+
+		exporter.Printer.Info("Composite image of %d frames exported at: %s", stack.Count, compositePath)
+	}
+
+	exporter.Printer.Debug("Composite images export stage finished. Stage took: %s", time.Since(compositeExportTime))
+	return nil
+}
+
+// Create the median background image from the not detected frames directly preceding the strike events. Return nil
+// if there are no frames available to create the background.
+func (exporter *exporter) CreateCompositeBackground(detections []int, events []event.StrikeEvent) (*image.RGBA, error) {
+	indexes := make([]int, 0, len(events))
+	for _, strikeEvent := range events {
+		frameIndex := strikeEvent.StartFrame - 2
+		if frameIndex < 0 {
+			continue
+		}
+
+		if _, found := slices.BinarySearch(detections, frameIndex); found {
+			continue
+		}
+
+		indexes = append(indexes, frameIndex)
+	}
+
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+
+	if len(indexes) > compositeBackgroundFramesLimit {
+		sampled := make([]int, 0, compositeBackgroundFramesLimit)
+		for sample := 0; sample < compositeBackgroundFramesLimit; sample += 1 {
+			sampled = append(sampled, indexes[sample*len(indexes)/compositeBackgroundFramesLimit])
+		}
+
+		indexes = sampled
+	}
+
+	frames := make([]*image.RGBA, 0, len(indexes))
+	err := exporter.ReadTargetFrames(indexes, func(_ int, frame *image.RGBA) error {
+		frameCopy, err := utils.CopyAsRgba(frame)
+		if err != nil {
+			return fmt.Errorf("export: failed to copy the background frame: %w", err)
+		}
+
+		frames = append(frames, frameCopy)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to read the background frames: %w", err)
+	}
+
+	if len(frames) == 0 {
+		return nil, nil
+	}
+
+	return utils.MedianRgba(frames)
+}
+
+// Read the full resolution video frames specified by the sorted 0-based indexes. The frame image passed to the handler is reused between calls.
+func (exporter *exporter) ReadTargetFrames(indexes []int, handler func(frameIndex int, frame *image.RGBA) error) error {
+	video, err := exporter.VideoOpener(exporter.InputVideoPath, exporter.Options.VideoStreamIndex, exporter.Options.ImageSequenceFps)
+	if err != nil {
+		return fmt.Errorf("export: failed to open the video file for the frames reading: %w", err)
+	}
+
+	defer video.Close()
+
+	targetWidth, targetHeight := video.GetOutputDimensions()
+
+	frame := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	if err := video.SetFrameBuffer(frame.Pix); err != nil {
+		return fmt.Errorf("export: failed to apply the given buffer as the video frame buffer: %w", err)
+	}
+
+	if err := video.SetTargetFrames(indexes...); err != nil {
+		return fmt.Errorf("export: failed to set the video target frames: %w", err)
+	}
+
+	for _, frameIndex := range indexes {
+		if err := video.Read(); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("export: failed to read the video frame: %w", err)
+		}
+
+		if err := handler(frameIndex, frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type compositeStack struct {
+	Mode  options.CompositeBlendMode
+	Image *image.RGBA
+	Sum   []uint32
+	Count int
+}
+
+func (stack *compositeStack) Push(frame *image.RGBA) error {
+	switch stack.Mode {
+	case options.MaxBlend, options.LightenOverMedianBlend:
+		if err := utils.BlendLighten(stack.Image, frame); err != nil {
+			return fmt.Errorf("export: failed to blend the frame: %w", err)
+		}
+	case options.AverageBlend:
+		if len(frame.Pix) != len(stack.Sum) {
+			return fmt.Errorf("export: the frame bounds are not matching the composite stack bounds")
+		}
+
+		for index, value := range frame.Pix {
+			stack.Sum[index] += uint32(value)
+		}
+	default:
+		return fmt.Errorf("export: invalid composite blend mode specified")
+	}
+
+	stack.Count += 1
+	return nil
+}
+
+func (stack *compositeStack) Result() *image.RGBA {
+	if stack.Mode == options.AverageBlend && stack.Count > 0 {
+		count := uint32(stack.Count)
+		for index, sum := range stack.Sum {
+			stack.Image.Pix[index] = uint8((sum + count/2) / count)
+		}
+	}
+
+	return stack.Image
+}
+
+func newCompositeStack(width, height int, mode options.CompositeBlendMode, background *image.RGBA) *compositeStack {
+	stack := &compositeStack{
+		Mode:  mode,
+		Image: image.NewRGBA(image.Rect(0, 0, width, height)),
+		Sum:   nil,
+		Count: 0,
+	}
+
+	if mode == options.AverageBlend {
+		stack.Sum = make([]uint32, len(stack.Image.Pix))
+	}
+
+	if mode == options.LightenOverMedianBlend && background != nil {
+		copy(stack.Image.Pix, background.Pix)
+	}
+
+	return stack
+}
@@ -0,0 +1,255 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/video"
+)
+
+func TestCompositeStackShouldBlendTheFrames(t *testing.T) {
+	frames := []*image.RGBA{
+		mockImage(10, 200, 30, 255),
+		mockImage(20, 100, 31, 255),
+		mockImage(30, 50, 30, 255),
+	}
+
+	background := mockImage(25, 0, 40, 255)
+
+	cases := []struct {
+		Mode       options.CompositeBlendMode
+		Background *image.RGBA
+		Frames     []*image.RGBA
+		Expected   []uint8
+	}{
+		{options.MaxBlend, nil, frames, []uint8{30, 200, 31, 255}},
+		{options.MaxBlend, background, frames[2:], []uint8{30, 50, 30, 255}},
+		{options.LightenOverMedianBlend, background, frames, []uint8{30, 200, 40, 255}},
+		{options.LightenOverMedianBlend, background, frames[1:2], []uint8{25, 100, 40, 255}},
+		{options.LightenOverMedianBlend, nil, frames[:1], []uint8{10, 200, 30, 255}},
+		{options.AverageBlend, nil, frames, []uint8{20, 117, 30, 255}},
+		{options.AverageBlend, nil, frames[:2], []uint8{15, 150, 31, 255}},
+		{options.AverageBlend, background, frames[2:], []uint8{30, 50, 30, 255}},
+	}
+
+	for _, c := range cases {
+		stack := newCompositeStack(2, 1, c.Mode, c.Background)
+
+		for _, frame := range c.Frames {
+			assert.Nil(t, stack.Push(frame))
+		}
+
+		assert.Equal(t, len(c.Frames), stack.Count)
+
+		result := stack.Result()
+		assert.Equal(t, append(c.Expected, c.Expected...), result.Pix, "blend mode: %s", c.Mode)
+	}
+
+	assert.Equal(t, []uint8{25, 0, 40, 255, 25, 0, 40, 255}, background.Pix)
+}
+
+func TestCompositeStackShouldNotPushFramesWithDifferentBounds(t *testing.T) {
+	stack := newCompositeStack(2, 1, options.AverageBlend, nil)
+	assert.NotNil(t, stack.Push(image.NewRGBA(image.Rect(0, 0, 3, 1))))
+
+	stack = newCompositeStack(2, 1, options.MaxBlend, nil)
+	assert.NotNil(t, stack.Push(image.NewRGBA(image.Rect(0, 0, 3, 1))))
+}
+
+func TestExportCompositeImagesShouldExportTheStrikeEventStacks(t *testing.T) {
+	frames := make([]*image.RGBA, 10)
+	for index := range frames {
+		frames[index] = mockImage(uint8(10*index), uint8(100-10*index), 0, 255)
+	}
+
+	events := []event.StrikeEvent{
+		{Index: 0, StartFrame: 2, EndFrame: 3},
+		{Index: 1, StartFrame: 4, EndFrame: 4},
+		{Index: 2, StartFrame: 7, EndFrame: 8},
+	}
+
+	detections := []int{6, 1, 2, 3, 7, 9}
+
+	o := options.GetDefaultDetectorOptions()
+	o.ExportCompositeImage = true
+	o.ExportStrikeEventCompositeImages = true
+	o.CompositeBlendMode = options.MaxBlend
+
+	exporter := mockCompositeExporter(t, o, frames)
+	assert.Nil(t, exporter.ExportCompositeImages(detections, events))
+
+	expected := map[string][]uint8{
+		CompositeImageFilename:                            {90, 90, 0, 255},
+		fmt.Sprintf(StrikeEventCompositeImageFilename, 0): {20, 90, 0, 255},
+		fmt.Sprintf(StrikeEventCompositeImageFilename, 1): {30, 70, 0, 255},
+		fmt.Sprintf(StrikeEventCompositeImageFilename, 2): {70, 40, 0, 255},
+	}
+
+	for filename, pixel := range expected {
+		assert.Equal(t, append(pixel, pixel...), readPngImage(t, path.Join(exporter.OutputDirPath, filename)).Pix, "file: %s", filename)
+	}
+
+	entries, err := os.ReadDir(exporter.OutputDirPath)
+	assert.Nil(t, err)
+	assert.Len(t, entries, len(expected))
+}
+
+func TestExportCompositeImagesShouldExportTheIncompleteStrikeEventStack(t *testing.T) {
+	frames := make([]*image.RGBA, 4)
+	for index := range frames {
+		frames[index] = mockImage(uint8(10*index), 0, 0, 255)
+	}
+
+	// NOTE: The strike event exceeds the frames of the video, therefore the reading stops before the last event frame
+	events := []event.StrikeEvent{{Index: 0, StartFrame: 3, EndFrame: 6}}
+
+	o := options.GetDefaultDetectorOptions()
+	o.ExportCompositeImage = false
+	o.ExportStrikeEventCompositeImages = true
+	o.CompositeBlendMode = options.AverageBlend
+
+	exporter := mockCompositeExporter(t, o, frames)
+	assert.Nil(t, exporter.ExportCompositeImages([]int{2, 3, 4, 5}, events))
+
+	assert.Equal(t, []uint8{25, 0, 0, 255, 25, 0, 0, 255}, readPngImage(t, path.Join(exporter.OutputDirPath, fmt.Sprintf(StrikeEventCompositeImageFilename, 0))).Pix)
+	assert.NoFileExists(t, path.Join(exporter.OutputDirPath, CompositeImageFilename))
+}
+
+func TestExportCompositeImagesShouldBlendOverTheMedianBackground(t *testing.T) {
+	frames := []*image.RGBA{
+		mockImage(10, 10, 10, 255),
+		mockImage(90, 20, 5, 255),
+		mockImage(20, 30, 0, 255),
+		mockImage(50, 60, 0, 255),
+		mockImage(30, 40, 20, 255),
+		mockImage(0, 0, 90, 255),
+	}
+
+	// NOTE: The background is the median of the frames 0, 2 and 4 directly preceding the strike events
+	events := []event.StrikeEvent{
+		{Index: 0, StartFrame: 2, EndFrame: 2},
+		{Index: 1, StartFrame: 4, EndFrame: 4},
+		{Index: 2, StartFrame: 6, EndFrame: 6},
+	}
+
+	o := options.GetDefaultDetectorOptions()
+	o.ExportCompositeImage = true
+	o.ExportStrikeEventCompositeImages = true
+	o.CompositeBlendMode = options.LightenOverMedianBlend
+
+	exporter := mockCompositeExporter(t, o, frames)
+
+	background, err := exporter.CreateCompositeBackground([]int{1, 3, 5}, events)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint8{20, 30, 10, 255, 20, 30, 10, 255}, background.Pix)
+
+	assert.Nil(t, exporter.ExportCompositeImages([]int{1, 3, 5}, events))
+
+	expected := map[string][]uint8{
+		CompositeImageFilename:                            {90, 60, 90, 255},
+		fmt.Sprintf(StrikeEventCompositeImageFilename, 0): {90, 30, 10, 255},
+		fmt.Sprintf(StrikeEventCompositeImageFilename, 1): {50, 60, 10, 255},
+		fmt.Sprintf(StrikeEventCompositeImageFilename, 2): {20, 30, 90, 255},
+	}
+
+	for filename, pixel := range expected {
+		assert.Equal(t, append(pixel, pixel...), readPngImage(t, path.Join(exporter.OutputDirPath, filename)).Pix, "file: %s", filename)
+	}
+}
+
+func mockImage(r, g, b, a uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	copy(img.Pix, []uint8{r, g, b, a, r, g, b, a})
+
+	return img
+}
+
+func readPngImage(t *testing.T, path string) *image.RGBA {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	assert.Nil(t, err)
+
+	rgba, err := utils.CopyAsRgba(img)
+	assert.Nil(t, err)
+
+	return rgba
+}
+
+func mockCompositeExporter(t *testing.T, o options.DetectorOptions, frames []*image.RGBA) *exporter {
+	p := printer.NewPrinter(printer.PrinterConfig{LogLevel: options.Quiet, OutStream: io.Discard})
+
+	exporter := NewExporter("video.mp4", t.TempDir(), o, p).(*exporter)
+	exporter.VideoOpener = func(path string, streamIndex int, sequenceFps float64) (video.Video, error) {
+		return &mockFramesVideo{Frames: frames}, nil
+	}
+
+	return exporter
+}
+
+// Mock video providing the specified frames images without the video processing binaries.
+type mockFramesVideo struct {
+	Frames  []*image.RGBA
+	Buffer  []byte
+	Targets []int
+}
+
+func (v *mockFramesVideo) GetInputDimensions() (int, int) { return v.GetOutputDimensions() }
+func (v *mockFramesVideo) GetOutputDimensions() (int, int) {
+	return v.Frames[0].Bounds().Dx(), v.Frames[0].Bounds().Dy()
+}
+func (v *mockFramesVideo) SetScale(s float64) error                         { return nil }
+func (v *mockFramesVideo) SetScaleAlgorithm(a options.ScaleAlgorithm) error { return nil }
+func (v *mockFramesVideo) SetBbox(x, y, w, h int) error                     { return nil }
+func (v *mockFramesVideo) SetFrameRange(first, last int) error {
+	return fmt.Errorf("mock: not supported")
+}
+func (v *mockFramesVideo) FramesCountApprox() int    { return len(v.Frames) }
+func (v *mockFramesVideo) FramesPerSecond() float64  { return 25 }
+func (v *mockFramesVideo) IsVariableFrameRate() bool { return false }
+func (v *mockFramesVideo) FrameTimestamps() ([]float64, error) {
+	return nil, fmt.Errorf("mock: not supported")
+}
+func (v *mockFramesVideo) SetFrameTimestamps(timestamps []float64) error { return nil }
+func (v *mockFramesVideo) CreationTime() time.Time                       { return time.Time{} }
+func (v *mockFramesVideo) Close()                                        {}
+
+func (v *mockFramesVideo) SetTargetFrames(n ...int) error {
+	v.Targets = n
+	return nil
+}
+
+func (v *mockFramesVideo) SetFrameBuffer(buffer []byte) error {
+	v.Buffer = buffer
+	return nil
+}
+
+func (v *mockFramesVideo) Read() error {
+	return v.ReadInto(v.Buffer)
+}
+
+func (v *mockFramesVideo) ReadInto(buffer []byte) error {
+	if len(v.Targets) == 0 || v.Targets[0] >= len(v.Frames) {
+		return io.EOF
+	}
+
+	copy(buffer, v.Frames[v.Targets[0]].Pix)
+	v.Targets = v.Targets[1:]
+
+	return nil
+}
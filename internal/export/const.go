@@ -5,6 +5,7 @@ const (
 	JsonDescriptiveStatisticsReportFilename string = "statistics-report.json"
 	JsonConfusionMatrixReportFilename       string = "confusion-matrix.json"
 	JsonDetectionThresholdReportFilename    string = "detection-thresholds-report.json"
+	JsonStrikeEventsReportFilename          string = "strike-events-report.json"
 )
 
 const (
@@ -12,8 +13,23 @@ const (
 	CsvDescriptiveStatisticsReportFilename string = "statistics-report.csv"
 	CsvConfusionMatrixReportFilename       string = "confusion-matrix.csv"
 	CsvDetectionThresholdReportFilename    string = "detection-thresholds-report.csv"
+	CsvStrikeEventsReportFilename          string = "strike-events-report.csv"
 )
 
 const (
 	FramesChartFilename string = "chart-report.html"
 )
+
+const (
+	CompositeImageFilename            string = "composite.png"
+	StrikeEventCompositeImageFilename string = "composite-event-%d.png"
+)
+
+const (
+	JsonBatchSummaryFilename string = "batch-summary.json"
+	CsvBatchSummaryFilename  string = "batch-summary.csv"
+)
+
+const (
+	YamlTuneProfileFilename string = "tune-profile.yaml"
+)
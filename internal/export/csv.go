@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"path"
 	"strconv"
+	"time"
 
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
@@ -27,18 +29,19 @@ func exportCsvFrames(outputDirectoryPath string, fc frame.FrameCollection) (stri
 
 	writer := csv.NewWriter(framesReportFile)
 
-	if err := writer.Write([]string{"Frame", "Brightness", "ColorDifference", "BinaryThresholdDifference"}); err != nil {
+	if err := writer.Write([]string{"Frame", "Timestamp", "Brightness", "ColorDifference", "BinaryThresholdDifference"}); err != nil {
 		return "", fmt.Errorf("export: failed to write the header to the frames report file: %w", err)
 	}
 
 	var (
 		frames    []*frame.Frame = fc.GetAll()
-		rowBuffer []string       = make([]string, 4)
+		rowBuffer []string       = make([]string, 5)
 	)
 
 	for _, frame := range frames {
 		rowBuffer = rowBuffer[:0]
 		rowBuffer = append(rowBuffer, strconv.Itoa(frame.OrdinalNumber))
+		rowBuffer = append(rowBuffer, strconv.FormatFloat(frame.Timestamp, 'f', -1, 64))
 		rowBuffer = append(rowBuffer, strconv.FormatFloat(frame.Brightness, 'f', -1, 64))
 		rowBuffer = append(rowBuffer, strconv.FormatFloat(frame.ColorDifference, 'f', -1, 64))
 		rowBuffer = append(rowBuffer, strconv.FormatFloat(frame.BinaryThresholdDifference, 'f', -1, 64))
@@ -53,7 +56,9 @@ func exportCsvFrames(outputDirectoryPath string, fc frame.FrameCollection) (stri
 	return csvFramesReportPath, nil
 }
 
-func exportCsvDescriptiveStatistics(outputDirectoryPath string, ds statistics.DescriptiveStatistics) (string, error) {
+// Export the descriptive statistics report. The moving statistics rows are numbered using the ordinal numbers of the frames, therefore
+// the rows of the analyzed frames range are matching the frames report.
+func exportCsvDescriptiveStatistics(outputDirectoryPath string, fc frame.FrameCollection, ds statistics.DescriptiveStatistics) (string, error) {
 	csvDescriptiveStatisticsReportPath := path.Join(outputDirectoryPath, CsvDescriptiveStatisticsReportFilename)
 	statisticsReportFile, err := utils.CreateFileWithTree(csvDescriptiveStatisticsReportPath)
 	if err != nil {
@@ -86,13 +91,36 @@ func exportCsvDescriptiveStatistics(outputDirectoryPath string, ds statistics.De
 		}
 	}
 
-	if err := writer.Write([]string{"Frame (Moving mean center point)", "Brightness moving mean", "Brightness moving stddev", "ColorDifference moving mean", "ColorDifference moving stddev", "BinaryThresholdDifference moving mean", "BinaryThresholdDifference moving stddev"}); err != nil {
+	// NOTE: The moving median and MAD columns are skipped if the statistics were created without the moving median
+	header := []string{"Frame (Moving mean center point)", "Brightness moving mean", "Brightness moving stddev", "Brightness moving median", "Brightness moving MAD", "ColorDifference moving mean", "ColorDifference moving stddev", "ColorDifference moving median", "ColorDifference moving MAD", "BinaryThresholdDifference moving mean", "BinaryThresholdDifference moving stddev", "BinaryThresholdDifference moving median", "BinaryThresholdDifference moving MAD"}
+	if !ds.HasMovingMedian() {
+		header = []string{"Frame (Moving mean center point)", "Brightness moving mean", "Brightness moving stddev", "ColorDifference moving mean", "ColorDifference moving stddev", "BinaryThresholdDifference moving mean", "BinaryThresholdDifference moving stddev"}
+	}
+
+	if err := writer.Write(header); err != nil {
 		return "", fmt.Errorf("export: failed to write the moving mean header to the descriptive statistics report file: %w", err)
 	}
 
+	offset := 0
+	if frames := fc.GetAll(); len(frames) != 0 {
+		offset = frames[0].OrdinalNumber - 1
+	}
+
 	for index := 0; index < len(ds.BrightnessMovingMean); index += 1 {
-		values := valuesToCsvRow(0, ds.BrightnessMovingMean[index], ds.BrightnessMovingStdDev[index], ds.ColorDifferenceMovingMean[index], ds.ColorDifferenceMovingStdDev[index], ds.BinaryThresholdDifferenceMovingMean[index], ds.BinaryThresholdDifferenceMovingStdDev[index])
-		values = append([]string{strconv.Itoa(index + 1)}, values...)
+		var values []string
+		if ds.HasMovingMedian() {
+			values = valuesToCsvRow(0,
+				ds.BrightnessMovingMean[index], ds.BrightnessMovingStdDev[index], ds.BrightnessMovingMedian[index], ds.BrightnessMovingMad[index],
+				ds.ColorDifferenceMovingMean[index], ds.ColorDifferenceMovingStdDev[index], ds.ColorDifferenceMovingMedian[index], ds.ColorDifferenceMovingMad[index],
+				ds.BinaryThresholdDifferenceMovingMean[index], ds.BinaryThresholdDifferenceMovingStdDev[index], ds.BinaryThresholdDifferenceMovingMedian[index], ds.BinaryThresholdDifferenceMovingMad[index])
+		} else {
+			values = valuesToCsvRow(0,
+				ds.BrightnessMovingMean[index], ds.BrightnessMovingStdDev[index],
+				ds.ColorDifferenceMovingMean[index], ds.ColorDifferenceMovingStdDev[index],
+				ds.BinaryThresholdDifferenceMovingMean[index], ds.BinaryThresholdDifferenceMovingStdDev[index])
+		}
+
+		values = append([]string{strconv.Itoa(offset + index + 1)}, values...)
 
 		if err := writer.Write(values); err != nil {
 			return "", fmt.Errorf("export: failed to write moving mean row to the descriptive statistics report file: %w", err)
@@ -170,6 +198,61 @@ func exportCsvDetectionThresholds(outputDirectoryPath string, opt options.Detect
 	return csvDetectionThresholdsReportPath, nil
 }
 
+func exportCsvStrikeEvents(outputDirectoryPath string, events []event.StrikeEvent) (string, error) {
+	csvStrikeEventsReportPath := path.Join(outputDirectoryPath, CsvStrikeEventsReportFilename)
+	strikeEventsReportFile, err := utils.CreateFileWithTree(csvStrikeEventsReportPath)
+	if err != nil {
+		return "", fmt.Errorf("export: failed to create the csv strike events report file: %w", err)
+	}
+
+	defer func() {
+		if err := strikeEventsReportFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	writer := csv.NewWriter(strikeEventsReportFile)
+
+	header := []string{
+		"Event", "StartFrame", "EndFrame", "PeakFrame", "DetectedFramesCount", "StartTime", "Duration", "StartTimeUtc", "PeakTimeUtc", "PeakBrightnessDelta",
+		"BrightnessMean", "BrightnessMax", "ColorDifferenceMean", "ColorDifferenceMax", "BinaryThresholdDifferenceMean", "BinaryThresholdDifferenceMax",
+	}
+
+	if err := writer.Write(header); err != nil {
+		return "", fmt.Errorf("export: failed to write the header to the strike events report file: %w", err)
+	}
+
+	for _, event := range events {
+		row := []string{
+			strconv.Itoa(event.Index),
+			strconv.Itoa(event.StartFrame),
+			strconv.Itoa(event.EndFrame),
+			strconv.Itoa(event.PeakFrame),
+			strconv.Itoa(event.DetectedFramesCount),
+		}
+
+		row = append(row, valuesToCsvRow(0, event.StartTime, event.Duration)...)
+		row = append(row, formatCsvTime(event.StartTimeUtc), formatCsvTime(event.PeakTimeUtc))
+
+		row = append(row, valuesToCsvRow(0,
+			event.PeakBrightnessDelta,
+			event.BrightnessMean,
+			event.BrightnessMax,
+			event.ColorDifferenceMean,
+			event.ColorDifferenceMax,
+			event.BinaryThresholdDifferenceMean,
+			event.BinaryThresholdDifferenceMax)...)
+
+		if err := writer.Write(row); err != nil {
+			return "", fmt.Errorf("export: failed to write the event row to the strike events report file: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return csvStrikeEventsReportPath, nil
+}
+
 func valuesToCsvRow(leftPadding int, values ...float64) []string {
 	buffer := make([]string, 0, len(values)+leftPadding)
 	for index := 0; index < leftPadding; index += 1 {
@@ -182,3 +265,12 @@ func valuesToCsvRow(leftPadding int, values ...float64) []string {
 
 	return buffer
 }
+
+// Format the time as a RFC3339 string with the fractional seconds. A zero time is represented as an empty value.
+func formatCsvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
+)
+
+func TestExportCsvDescriptiveStatisticsShouldUseTheFrameOrdinalNumbers(t *testing.T) {
+	fc := frame.NewOffsetFrameCollection(5, 100)
+	for index, brightness := range []float64{0.1, 0.5, 0.9, 0.7, 0.1} {
+		assert.Nil(t, fc.Push(&frame.Frame{OrdinalNumber: 101 + index, Brightness: brightness}))
+	}
+
+	fc.Lock()
+
+	ds := statistics.CreateDescriptiveStatistics(fc, 2, true)
+
+	path, err := exportCsvDescriptiveStatistics(t.TempDir(), fc, ds)
+	assert.Nil(t, err)
+
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	assert.Nil(t, err)
+
+	frames := make([]string, 0, fc.Count())
+	for index, row := range rows {
+		if strings.HasPrefix(row[0], "Frame") {
+			for _, frameRow := range rows[index+1:] {
+				frames = append(frames, frameRow[0])
+			}
+		}
+	}
+
+	assert.Equal(t, []string{"101", "102", "103", "104", "105"}, frames)
+}
+
+func TestExportCsvDescriptiveStatisticsShouldSkipTheUnavailableMovingMedian(t *testing.T) {
+	fc := frame.NewFrameCollection(3)
+	for index, brightness := range []float64{0.1, 0.5, 0.9} {
+		assert.Nil(t, fc.Push(&frame.Frame{OrdinalNumber: index + 1, Brightness: brightness}))
+	}
+
+	fc.Lock()
+
+	for movingMedian, columns := range map[bool]int{true: 13, false: 7} {
+		ds := statistics.CreateDescriptiveStatistics(fc, 2, movingMedian)
+
+		path, err := exportCsvDescriptiveStatistics(t.TempDir(), fc, ds)
+		assert.Nil(t, err)
+
+		content, err := os.ReadFile(path)
+		assert.Nil(t, err)
+
+		reader := csv.NewReader(strings.NewReader(string(content)))
+		reader.FieldsPerRecord = -1
+
+		rows, err := reader.ReadAll()
+		assert.Nil(t, err)
+
+		for _, row := range rows[len(rows)-4:] {
+			assert.Len(t, row, columns)
+		}
+	}
+}
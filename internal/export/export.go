@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"image"
 	"io"
+	"math"
 	"path"
 	"slices"
 	"time"
 
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/printer"
@@ -17,7 +19,7 @@ import (
 )
 
 type Exporter interface {
-	Export(fc frame.FrameCollection, ds statistics.DescriptiveStatistics, detections []int) error
+	Export(fc frame.FrameCollection, ds statistics.DescriptiveStatistics, detections []int, events []event.StrikeEvent) error
 }
 
 type exporter struct {
@@ -25,9 +27,10 @@ type exporter struct {
 	OutputDirPath  string
 	Options        options.DetectorOptions
 	Printer        printer.Printer
+	VideoOpener    func(path string, streamIndex int, sequenceFps float64) (video.Video, error)
 }
 
-func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.DescriptiveStatistics, detections []int) error {
+func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.DescriptiveStatistics, detections []int, events []event.StrikeEvent) error {
 	exportTime := time.Now()
 
 	if err := tableDescriptiveStatistics(exporter.Printer, ds, options.Verbose); err != nil {
@@ -40,6 +43,18 @@ func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.Descrip
 		}
 	}
 
+	if exporter.Options.ExportStrikeEventClips {
+		if err := exporter.ExportStrikeEventClips(events); err != nil {
+			return fmt.Errorf("export: failed to perform the strike event clips export: %w", err)
+		}
+	}
+
+	if exporter.Options.ExportCompositeImage || exporter.Options.ExportStrikeEventCompositeImages {
+		if err := exporter.ExportCompositeImages(detections, events); err != nil {
+			return fmt.Errorf("export: failed to perform the composite images export: %w", err)
+		}
+	}
+
 	var confusionMatrix statistics.ConfusionMatrix
 	if exporter.Options.ExportConfusionMatrix {
 		actualClassification, err := utils.ParseRangeExpression(exporter.Options.ConfusionMatrixActualDetectionsExpression)
@@ -49,7 +64,17 @@ func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.Descrip
 
 		exporter.Printer.Debug("Frames used as actual detection classification: %v", actualClassification)
 
-		confusionMatrix = statistics.CreateConfusionMatrix(actualClassification, detections, fc.Count())
+		// NOTE: The confusion matrix is calculated only for the analyzed frames range, therefore the actual and predicted
+		// classifications are shifted to be relative to the first frame of the collection.
+		offset, count := 0, fc.Count()
+		if count > 0 {
+			offset = fc.GetAll()[0].OrdinalNumber - 1
+		}
+
+		actualClassification = statistics.ShiftClassificationRange(actualClassification, offset, 1, count)
+		predictedClassification := statistics.ShiftClassificationRange(detections, offset, 0, count)
+
+		confusionMatrix = statistics.CreateConfusionMatrix(actualClassification, predictedClassification, count)
 
 		if err := tableConfusionMatrix(exporter.Printer, confusionMatrix, options.Verbose); err != nil {
 			return fmt.Errorf("export: failed to export the confusion matrix: %w", err)
@@ -66,7 +91,13 @@ func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.Descrip
 			exporter.Printer.Info("Frames report in CSV format exported to: %s", path)
 		}
 
-		if path, err := exportCsvDescriptiveStatistics(exporter.OutputDirPath, ds); err != nil {
+		if path, err := exportCsvStrikeEvents(exporter.OutputDirPath, events); err != nil {
+			return fmt.Errorf("export: failed to export csv strike events report: %w", err)
+		} else {
+			exporter.Printer.Info("Strike events report in CSV format exported to: %s", path)
+		}
+
+		if path, err := exportCsvDescriptiveStatistics(exporter.OutputDirPath, fc, ds); err != nil {
 			return fmt.Errorf("export: failed to export csv descriptive statistics report: %w", err)
 		} else {
 			exporter.Printer.Info("Descriptive statistics in CSV format exported to %s", path)
@@ -99,6 +130,12 @@ func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.Descrip
 			exporter.Printer.Info("Frames report in JSON format exported to: %s", path)
 		}
 
+		if path, err := exportJsonStrikeEvents(exporter.OutputDirPath, events); err != nil {
+			return fmt.Errorf("export: failed to export json strike events report: %w", err)
+		} else {
+			exporter.Printer.Info("Strike events report in JSON format exported to: %s", path)
+		}
+
 		if path, err := exportJsonDescriptiveStatistics(exporter.OutputDirPath, ds); err != nil {
 			return fmt.Errorf("export: failed to export json descriptive statistics report: %w", err)
 		} else {
@@ -131,6 +168,7 @@ func (exporter *exporter) Export(fc frame.FrameCollection, ds statistics.Descrip
 			fc,
 			ds,
 			detections,
+			events,
 			exporter.Options.BrightnessDetectionThreshold,
 			exporter.Options.ColorDifferenceDetectionThreshold,
 			exporter.Options.BinaryThresholdDifferenceDetectionThreshold)
@@ -155,7 +193,7 @@ func (exporter *exporter) ExportPngFrameImages(detections []int) error {
 
 	slices.Sort(detections)
 
-	video, err := video.NewVideo(exporter.InputVideoPath)
+	video, err := exporter.VideoOpener(exporter.InputVideoPath, exporter.Options.VideoStreamIndex, exporter.Options.ImageSequenceFps)
 	if err != nil {
 		return fmt.Errorf("export: failed to open the video file for the frame export stage: %w", err)
 	}
@@ -197,11 +235,62 @@ func (exporter *exporter) ExportPngFrameImages(detections []int) error {
 	return nil
 }
 
+func (exporter *exporter) ExportStrikeEventClips(events []event.StrikeEvent) error {
+	clipsExportTime := time.Now()
+	exporter.Printer.Debug("Starting the strike event clips export stage.")
+
+	if video.IsImageSequence(exporter.InputVideoPath) {
+		exporter.Printer.Warning("The strike event clips can not be cut from the image sequence. The clips export is skipped.")
+		return nil
+	}
+
+	exporter.Printer.Info("About to export %d strike event clips.", len(events))
+
+	clipExtension := path.Ext(exporter.InputVideoPath)
+	if len(clipExtension) == 0 {
+		clipExtension = ".mp4"
+	}
+
+	progressStep, progressFinalize := exporter.Printer.ProgressSteps("Strike event clips export stage.", len(events))
+
+	for _, event := range events {
+		var (
+			from float64 = math.Max(0, event.StartTime-exporter.Options.StrikeEventClipPreRoll)
+			to   float64 = event.StartTime + event.Duration + exporter.Options.StrikeEventClipPostRoll
+		)
+
+		clipName := fmt.Sprintf("event-%d-%s%s", event.Index, formatClipTimestamp(event.StartTime), clipExtension)
+		clipPath := path.Join(exporter.OutputDirPath, clipName)
+		if err := video.CutVideoClip(exporter.InputVideoPath, clipPath, exporter.Options.VideoStreamIndex, from, to); err != nil {
+			return fmt.Errorf("export: failed to export the strike event clip: %w", err)
+		}
+
+		progressStep()
+		exporter.Printer.Info("Event: [%d/%d]. Strike event clip exported at: %s", event.Index+1, len(events), clipPath)
+	}
+
+	progressFinalize()
+	exporter.Printer.Debug("Strike event clips export stage finished. Stage took: %s", time.Since(clipsExportTime))
+	return nil
+}
+
+// Format the timestamp in seconds to a file name friendly representation. Example: 01h02m03s450ms
+func formatClipTimestamp(seconds float64) string {
+	milliseconds := int64(math.Round(seconds * 1000))
+
+	return fmt.Sprintf("%02dh%02dm%02ds%03dms",
+		milliseconds/3600000,
+		milliseconds/60000%60,
+		milliseconds/1000%60,
+		milliseconds%1000)
+}
+
 func NewExporter(inputVideo, outputDir string, o options.DetectorOptions, p printer.Printer) Exporter {
 	return &exporter{
 		InputVideoPath: inputVideo,
 		OutputDirPath:  outputDir,
 		Options:        o,
 		Printer:        p,
+		VideoOpener:    video.OpenVideo,
 	}
 }
@@ -0,0 +1,21 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatClipTimestampShouldFormatTheTimeComponents(t *testing.T) {
+	cases := map[float64]string{
+		0.0:      "00h00m00s000ms",
+		0.0004:   "00h00m00s000ms",
+		1.5:      "00h00m01s500ms",
+		3723.45:  "01h02m03s450ms",
+		86399.99: "23h59m59s990ms",
+	}
+
+	for seconds, expected := range cases {
+		assert.Equal(t, expected, formatClipTimestamp(seconds))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/Krzysztofz01/video-lightning-detector/internal/event"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/statistics"
@@ -108,6 +109,28 @@ func exportJsonDetectionThresholds(outputDirectoryPath string, opt options.Detec
 	return jsonDetectionThresholdsReportPath, nil
 }
 
+func exportJsonStrikeEvents(outputDirectoryPath string, events []event.StrikeEvent) (string, error) {
+	jsonStrikeEventsReportPath := path.Join(outputDirectoryPath, JsonStrikeEventsReportFilename)
+	strikeEventsReportFile, err := utils.CreateFileWithTree(jsonStrikeEventsReportPath)
+	if err != nil {
+		return "", fmt.Errorf("export: failed to create the json strike events report file: %w", err)
+	}
+
+	defer func() {
+		if err := strikeEventsReportFile.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	encoder := createEncoder(strikeEventsReportFile)
+
+	if err := encoder.Encode(events); err != nil {
+		return "", fmt.Errorf("export: failed to encode the strike events to json report file: %w", err)
+	}
+
+	return jsonStrikeEventsReportPath, nil
+}
+
 func createEncoder(file *os.File) *json.Encoder {
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "    ")
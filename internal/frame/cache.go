@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"slices"
 )
 
@@ -28,25 +29,74 @@ import (
 // | Data          | variable <Length> | 42                     |
 // +---------------+-------------------+------------------------+
 //
+// The version 1 data is a sequence of frame entries containing the ordinal number (uint32), brightness, color difference and
+// binary threshold difference (float64).
+//
+// Version 2
+//
+// +---------------+-------------------+------------------------+
+// | Name          | Bytes             | Offset                 |
+// +---------------+-------------------+------------------------+
+// | Magic         | 4                 | 0                      |
+// | Version       | 1                 | 4                      |
+// | Magic         | 4                 | 5                      |
+// | Checksum      | 20                | 9                      |
+// | Magic         | 4                 | 29                     |
+// | Fingerprint   | 20                | 33                     |
+// | Magic         | 4                 | 53                     |
+// | Compression   | 1                 | 57                     |
+// | Magic         | 4                 | 58                     |
+// | Length        | 4                 | 62                     |
+// | Data          | variable <Length> | 66                     |
+// +---------------+-------------------+------------------------+
+//
+// The version 2 row data is a sequence of frame entries containing the ordinal number (uint32), timestamp, brightness, color difference
+// and binary threshold difference (float64). The fingerprint is identifying the content of the analyzed video.
+//
+// The compression value is specifying both the compression and the layout of the data. The row data (0xF0, 0xF1) is read only, the
+// column data (0xF2, 0xF3) is written by the version 2. The column data is starting with the frames count (uint32) followed by the
+// ordinal numbers encoded as varint deltas and the timestamp, brightness, color difference and binary threshold difference columns.
+// Each float64 column is XOR encoded with the preceding value and stored as eight byte planes, therefore the slowly changing values
+// are producing long runs of zeros which are compressed with flate if the frames count exceeds the plain data entry threshold.
+//
+// The analysis checkpoint (.vld-checkpoint) is a sequence of version 2 caches sharing the header, where each appended cache contains
+// the frames following the frames of the preceding cache, therefore the checkpoint is stored without rewriting the stored frames.
+//
 
 const (
-	chceksumDecodedLength   int = 20
-	plainDataEntryThreshold int = 5000
+	chceksumDecodedLength    int = 20
+	fingerprintDecodedLength int = 20
+	plainDataEntryThreshold  int = 5000
+)
+
+const (
+	FormatVersion1 uint8 = 0x01
+	FormatVersion2 uint8 = 0x02
 )
 
 var (
-	magicSequence []uint8 = []uint8{0x56, 0x4C, 0x44, 0x21}
-	formatVersion []uint8 = []uint8{0x01}
-	plainData     uint8   = 0xF0
-	flateData     uint8   = 0xF1
+	magicSequence   []uint8 = []uint8{0x56, 0x4C, 0x44, 0x21}
+	plainData       uint8   = 0xF0
+	flateData       uint8   = 0xF1
+	plainColumnData uint8   = 0xF2
+	flateColumnData uint8   = 0xF3
 )
 
-func ExportCachedFrameCollection(f io.Writer, fc FrameCollection, checksum string) error {
+// Structure representing the header of the preanalyzed frames cache. The fingerprint is empty for the version 1 caches.
+type CacheHeader struct {
+	Version     uint8
+	Checksum    string
+	Fingerprint string
+}
+
+// Export the frames collection as the version 2 cache with the options checksum and the video content fingerprint. An empty
+// fingerprint is encoded as zeros.
+func ExportCachedFrameCollection(f io.Writer, fc FrameCollection, checksum, fingerprint string) error {
 	if _, err := f.Write(magicSequence); err != nil {
 		return fmt.Errorf("frame: failed to encode the magic sequence: %w", err)
 	}
 
-	if _, err := f.Write(formatVersion); err != nil {
+	if _, err := f.Write([]uint8{FormatVersion2}); err != nil {
 		return fmt.Errorf("frame: failed to encode the format version: %w", err)
 	}
 
@@ -59,6 +109,10 @@ func ExportCachedFrameCollection(f io.Writer, fc FrameCollection, checksum strin
 		return fmt.Errorf("frame: failed to decode the checksum hex: %w", err)
 	}
 
+	if len(checksumDecoded) != chceksumDecodedLength {
+		return fmt.Errorf("frame: invalid checksum length")
+	}
+
 	if _, err := f.Write(checksumDecoded); err != nil {
 		return fmt.Errorf("frame: failed to encode decoded hex checksum: %w", err)
 	}
@@ -67,33 +121,50 @@ func ExportCachedFrameCollection(f io.Writer, fc FrameCollection, checksum strin
 		return fmt.Errorf("frame: failed to encode magic sequence: %w", err)
 	}
 
+	fingerprintDecoded := make([]uint8, fingerprintDecodedLength)
+	if len(fingerprint) != 0 {
+		if fingerprintDecoded, err = hex.DecodeString(fingerprint); err != nil {
+			return fmt.Errorf("frame: failed to decode the fingerprint hex: %w", err)
+		}
+
+		if len(fingerprintDecoded) != fingerprintDecodedLength {
+			return fmt.Errorf("frame: invalid fingerprint length")
+		}
+	}
+
+	if _, err := f.Write(fingerprintDecoded); err != nil {
+		return fmt.Errorf("frame: failed to encode decoded hex fingerprint: %w", err)
+	}
+
+	if _, err := f.Write(magicSequence); err != nil {
+		return fmt.Errorf("frame: failed to encode magic sequence: %w", err)
+	}
+
 	var (
 		dataBuffer      *bytes.Buffer = &bytes.Buffer{}
 		dataCompression uint8
 	)
 
-	// NOTE: The compression feature is disabled for now. The specific handling of EOF in flate needs to be investigated.
-	if false && fc.Count() > plainDataEntryThreshold {
-		dataCompression = flateData
+	if fc.Count() > plainDataEntryThreshold {
+		dataCompression = flateColumnData
 
 		flateWriter, err := flate.NewWriter(dataBuffer, flate.BestCompression)
 		if err != nil {
 			return fmt.Errorf("frame: failed to create the flate compress writer: %w", err)
 		}
 
-		defer flateWriter.Close()
-
-		if err := encodeFrameCollectionPlain(flateWriter, fc); err != nil {
+		if err := encodeFrameCollectionColumns(flateWriter, fc); err != nil {
 			return fmt.Errorf("frame: failed to encode the compressed frames data to buffer: %w", err)
 		}
 
-		if err := flateWriter.Flush(); err != nil {
-			return fmt.Errorf("frame: failed to flush the encoded compressed frame data: %w", err)
+		// NOTE: The writer must be closed instead of flushed in order to write the final block, otherwise the reader is not reaching the EOF
+		if err := flateWriter.Close(); err != nil {
+			return fmt.Errorf("frame: failed to close the encoded compressed frame data: %w", err)
 		}
 	} else {
-		dataCompression = plainData
+		dataCompression = plainColumnData
 
-		if err := encodeFrameCollectionPlain(dataBuffer, fc); err != nil {
+		if err := encodeFrameCollectionColumns(dataBuffer, fc); err != nil {
 			return fmt.Errorf("frame: failed to encode the plain frame data to buffer: %w", err)
 		}
 	}
@@ -121,125 +192,179 @@ func ExportCachedFrameCollection(f io.Writer, fc FrameCollection, checksum strin
 	return nil
 }
 
-func ImportCachedFrameCollection(f io.Reader) (FrameCollection, string, error) {
+// Import the frames collection from the version 1 or version 2 cache. The frames timestamps are available only in the version 2 cache.
+func ImportCachedFrameCollection(f io.Reader) (FrameCollection, CacheHeader, error) {
 	var (
-		magicBuffer           []uint8 = make([]uint8, len(magicSequence))
-		versionBuffer         []uint8 = make([]uint8, 1)
-		checksumBuffer        []uint8 = make([]uint8, chceksumDecodedLength)
 		dataCompressionBuffer []uint8 = make([]uint8, 1)
+		magicBuffer           []uint8 = make([]uint8, len(magicSequence))
 		dataBuffer            []uint8
 	)
 
-	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return nil, "", fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
-	}
-
-	if _, err := io.ReadFull(f, versionBuffer); err != nil {
-		return nil, "", fmt.Errorf("frame: failed to decode the version: %w", err)
-	}
-
-	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return nil, "", fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
-	}
-
-	if _, err := io.ReadFull(f, checksumBuffer); err != nil {
-		return nil, "", fmt.Errorf("frame: failed to decode the checksum: %w", err)
-	}
-
-	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return nil, "", fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+	header, err := decodeCacheHeader(f)
+	if err != nil {
+		return nil, CacheHeader{}, err
 	}
 
 	if _, err := io.ReadFull(f, dataCompressionBuffer); err != nil {
-		return nil, "", fmt.Errorf("frame: failed to decode data compression: %w", err)
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode data compression: %w", err)
 	}
 
-	var compression bool
+	var compression, columns bool
 	switch dataCompressionBuffer[0] {
 	case plainData:
-		compression = false
+		compression, columns = false, false
 	case flateData:
-		compression = true
+		compression, columns = true, false
+	case plainColumnData:
+		compression, columns = false, true
+	case flateColumnData:
+		compression, columns = true, true
 	default:
-		return nil, "", fmt.Errorf("frame: invalid or corrupted data compression value")
+		return nil, CacheHeader{}, fmt.Errorf("frame: invalid or corrupted data compression value")
 	}
 
 	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return nil, "", fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
 	}
 
 	var length uint32
 	if err := binary.Read(f, binary.LittleEndian, &length); err != nil {
-		return nil, "", fmt.Errorf("frame: failed to decode the data length: %w", err)
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode the data length: %w", err)
 	}
 
 	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return nil, "", fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+	}
+
+	// NOTE: The data is read incrementally instead of allocating the buffer of the declared length, which may be corrupted
+	dataBuffer, err = io.ReadAll(io.LimitReader(f, int64(length)))
+	if err != nil {
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode the data: %w", err)
 	}
 
-	dataBuffer = make([]uint8, length)
-	if _, err := io.ReadFull(f, dataBuffer); err != nil {
-		return nil, "", fmt.Errorf("frame: failed to decode the data: %w", err)
+	if len(dataBuffer) != int(length) {
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode the data: %w", io.ErrUnexpectedEOF)
 	}
 
 	var (
-		dataBufferReader *bytes.Reader = bytes.NewReader(dataBuffer)
-		frames           FrameCollection
+		dataReader io.Reader = bytes.NewReader(dataBuffer)
+		timestamps bool      = header.Version >= FormatVersion2
+		frames     FrameCollection
 	)
 
 	if compression {
-		flateReader := flate.NewReader(dataBufferReader)
+		flateReader := flate.NewReader(dataReader)
 		defer flateReader.Close()
 
-		if fc, err := decodeFrameCollectionPlain(flateReader); err != nil {
-			return nil, "", fmt.Errorf("frame: failed to decode the compressed frame data: %w", err)
-		} else {
-			frames = fc
-		}
+		dataReader = flateReader
+	}
+
+	if columns {
+		frames, err = decodeFrameCollectionColumns(dataReader)
 	} else {
-		if fc, err := decodeFrameCollectionPlain(dataBufferReader); err != nil {
-			return nil, "", fmt.Errorf("frame: failed to decode the plain frame data: %w", err)
-		} else {
-			frames = fc
+		frames, err = decodeFrameCollectionPlain(dataReader, timestamps)
+	}
+
+	if err != nil {
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to decode the frame data: %w", err)
+	}
+
+	return frames, header, nil
+}
+
+// Import the frames collection from the sequence of caches appended to each other, which share the header and are continuing the
+// ordinal numbers of the preceding cache. The first cache is required, the following caches are read until the end of the data or
+// until the first cache which is not continuing the sequence, because the incomplete cache may be left by an interrupted append.
+func ImportAppendedFrameCollection(f io.Reader) (FrameCollection, CacheHeader, error) {
+	fc, header, err := ImportCachedFrameCollection(f)
+	if err != nil {
+		return nil, CacheHeader{}, err
+	}
+
+	frames := slices.Clone(fc.GetAll())
+	for {
+		appendedFc, appendedHeader, err := ImportCachedFrameCollection(f)
+		if err != nil || appendedHeader != header {
+			break
+		}
+
+		appendedFrames := appendedFc.GetAll()
+		if len(appendedFrames) == 0 {
+			continue
+		}
+
+		if len(frames) != 0 && appendedFrames[0].OrdinalNumber != frames[len(frames)-1].OrdinalNumber+1 {
+			break
 		}
+
+		frames = append(frames, appendedFrames...)
+	}
+
+	if fc, err = createDecodedFrameCollection(frames); err != nil {
+		return nil, CacheHeader{}, fmt.Errorf("frame: failed to create the appended frames collection: %w", err)
 	}
 
-	return frames, hex.EncodeToString(checksumBuffer), nil
+	return fc, header, nil
+}
+
+// Decode only the header of the version 1 or version 2 cache without decoding the frames data.
+func PeekCacheHeader(f io.Reader) (CacheHeader, error) {
+	return decodeCacheHeader(f)
 }
 
-func ChecksumEqualPeek(f io.Reader, checksum string) (bool, error) {
+func decodeCacheHeader(f io.Reader) (CacheHeader, error) {
 	var (
-		magicBuffer    = make([]uint8, len(magicSequence))
-		versionBuffer  = make([]uint8, 1)
-		checksumBuffer = make([]uint8, chceksumDecodedLength)
+		magicBuffer       = make([]uint8, len(magicSequence))
+		versionBuffer     = make([]uint8, 1)
+		checksumBuffer    = make([]uint8, chceksumDecodedLength)
+		fingerprintBuffer = make([]uint8, fingerprintDecodedLength)
+		header            CacheHeader
 	)
 
 	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return false, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
 	}
 
 	if _, err := io.ReadFull(f, versionBuffer); err != nil {
-		return false, fmt.Errorf("frame: failed to decode the version: %w", err)
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode the version: %w", err)
+	}
+
+	header.Version = versionBuffer[0]
+	if header.Version != FormatVersion1 && header.Version != FormatVersion2 {
+		return CacheHeader{}, fmt.Errorf("frame: unsupported cache format version %d", header.Version)
 	}
 
 	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return false, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
 	}
 
 	if _, err := io.ReadFull(f, checksumBuffer); err != nil {
-		return false, fmt.Errorf("frame: failed to decode the checksum: %w", err)
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode the checksum: %w", err)
 	}
 
+	header.Checksum = hex.EncodeToString(checksumBuffer)
+
 	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
-		return false, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
 	}
 
-	targetChecksum, err := hex.DecodeString(checksum)
-	if err != nil {
-		return false, fmt.Errorf("frame: failed to decode the target checksum: %w", err)
+	if header.Version == FormatVersion1 {
+		return header, nil
 	}
 
-	return slices.Equal(targetChecksum, checksumBuffer), nil
+	if _, err := io.ReadFull(f, fingerprintBuffer); err != nil {
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode the fingerprint: %w", err)
+	}
+
+	if slices.ContainsFunc(fingerprintBuffer, func(b uint8) bool { return b != 0 }) {
+		header.Fingerprint = hex.EncodeToString(fingerprintBuffer)
+	}
+
+	if _, err := io.ReadFull(f, magicBuffer); err != nil || !slices.Equal(magicBuffer, magicSequence) {
+		return CacheHeader{}, fmt.Errorf("frame: failed to decode and check the magic sequence: %w", err)
+	}
+
+	return header, nil
 }
 
 func encodeFrameCollectionPlain(f io.Writer, fc FrameCollection) error {
@@ -248,6 +373,10 @@ func encodeFrameCollectionPlain(f io.Writer, fc FrameCollection) error {
 			return fmt.Errorf("frame: failed to binary encode the frame ordinal number: %w", err)
 		}
 
+		if err := binary.Write(f, binary.LittleEndian, frame.Timestamp); err != nil {
+			return fmt.Errorf("frame: failed to binary encode the frame timestamp: %w", err)
+		}
+
 		if err := binary.Write(f, binary.LittleEndian, frame.Brightness); err != nil {
 			return fmt.Errorf("frame: failed to binary encode the frame brightness: %w", err)
 		}
@@ -264,10 +393,11 @@ func encodeFrameCollectionPlain(f io.Writer, fc FrameCollection) error {
 	return nil
 }
 
-func decodeFrameCollectionPlain(r io.Reader) (FrameCollection, error) {
+func decodeFrameCollectionPlain(r io.Reader, timestamps bool) (FrameCollection, error) {
 	var (
 		frames                    []*Frame = make([]*Frame, 0, plainDataEntryThreshold)
 		ordinalNumber             uint32
+		timestamp                 float64
 		brightness                float64
 		colorDifference           float64
 		binaryThresholdDifference float64
@@ -282,6 +412,12 @@ func decodeFrameCollectionPlain(r io.Reader) (FrameCollection, error) {
 			return nil, fmt.Errorf("frame: failed to decode the frame ordinal number: %w", err)
 		}
 
+		if timestamps {
+			if err := binary.Read(r, binary.LittleEndian, &timestamp); err != nil {
+				return nil, fmt.Errorf("frame: failed to decode the frame timestamp: %w", err)
+			}
+		}
+
 		if err := binary.Read(r, binary.LittleEndian, &brightness); err != nil {
 			return nil, fmt.Errorf("frame: failed to decode the frame brightness: %w", err)
 		}
@@ -296,13 +432,148 @@ func decodeFrameCollectionPlain(r io.Reader) (FrameCollection, error) {
 
 		frames = append(frames, &Frame{
 			OrdinalNumber:             int(ordinalNumber),
+			Timestamp:                 timestamp,
 			ColorDifference:           colorDifference,
 			BinaryThresholdDifference: binaryThresholdDifference,
 			Brightness:                brightness,
 		})
 	}
 
-	fc := NewFrameCollection(len(frames))
+	return createDecodedFrameCollection(frames)
+}
+
+func encodeFrameCollectionColumns(f io.Writer, fc FrameCollection) error {
+	var (
+		frames []*Frame = fc.GetAll()
+		count  int      = len(frames)
+		buffer []uint8  = make([]uint8, 0, 4+2*count)
+	)
+
+	buffer = binary.LittleEndian.AppendUint32(buffer, uint32(count))
+
+	// NOTE: The ordinal numbers are consecutive, therefore the deltas are encoded as single byte varints. The wrapping arithmetic is
+	// used to encode the unexpected decreasing ordinal numbers.
+	var previousOrdinalNumber uint64
+	for _, frame := range frames {
+		buffer = binary.AppendUvarint(buffer, uint64(frame.OrdinalNumber)-previousOrdinalNumber)
+		previousOrdinalNumber = uint64(frame.OrdinalNumber)
+	}
+
+	if _, err := f.Write(buffer); err != nil {
+		return fmt.Errorf("frame: failed to encode the frame ordinal numbers: %w", err)
+	}
+
+	planes := make([]uint8, 8*count)
+	for _, column := range frameColumns {
+		var previousBits uint64
+		for index, frame := range frames {
+			bits := math.Float64bits(*column(frame))
+			delta := bits ^ previousBits
+			previousBits = bits
+
+			for plane := range 8 {
+				planes[plane*count+index] = uint8(delta >> (8 * plane))
+			}
+		}
+
+		if _, err := f.Write(planes); err != nil {
+			return fmt.Errorf("frame: failed to encode the frame data column: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func decodeFrameCollectionColumns(r io.Reader) (FrameCollection, error) {
+	countBuffer := make([]uint8, 4)
+	if _, err := io.ReadFull(r, countBuffer); err != nil {
+		return nil, fmt.Errorf("frame: failed to decode the frames count: %w", err)
+	}
+
+	count := int(binary.LittleEndian.Uint32(countBuffer))
+
+	// NOTE: The data is read up to the maximal length of the declared frames count, therefore the corrupted compressed data
+	// can not be decompressed without a limit. Each ordinal number delta is fitting a uint32 varint.
+	maxLength := int64(count) * int64(binary.MaxVarintLen32+8*len(frameColumns))
+
+	data, err := io.ReadAll(io.LimitReader(r, maxLength+1))
+	if err != nil {
+		return nil, fmt.Errorf("frame: failed to read the column data: %w", err)
+	}
+
+	if int64(len(data)) > maxLength {
+		return nil, fmt.Errorf("frame: the column data length is exceeding the frames count")
+	}
+
+	// NOTE: Each ordinal number is encoded using at least one byte, therefore the count is validated before the allocation
+	if count > len(data) {
+		return nil, fmt.Errorf("frame: the frames count is exceeding the column data length")
+	}
+
+	var (
+		frames                []*Frame = make([]*Frame, count)
+		previousOrdinalNumber uint64
+	)
+
+	for index := range frames {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("frame: failed to decode the frame ordinal number")
+		}
+
+		data = data[n:]
+
+		ordinalNumber := previousOrdinalNumber + delta
+		if ordinalNumber == 0 || ordinalNumber > math.MaxUint32 {
+			return nil, fmt.Errorf("frame: invalid decoded frame ordinal number")
+		}
+
+		frames[index] = &Frame{OrdinalNumber: int(ordinalNumber)}
+		previousOrdinalNumber = ordinalNumber
+	}
+
+	if len(data) != 8*count*len(frameColumns) {
+		return nil, fmt.Errorf("frame: the column data length is not matching the frames count")
+	}
+
+	for _, column := range frameColumns {
+		var previousBits uint64
+		for index, frame := range frames {
+			var delta uint64
+			for plane := range 8 {
+				delta |= uint64(data[plane*count+index]) << (8 * plane)
+			}
+
+			previousBits ^= delta
+			*column(frame) = math.Float64frombits(previousBits)
+		}
+
+		data = data[8*count:]
+	}
+
+	return createDecodedFrameCollection(frames)
+}
+
+// The frame fields stored as the float64 columns of the column data in the order of encoding.
+var frameColumns []func(f *Frame) *float64 = []func(f *Frame) *float64{
+	func(f *Frame) *float64 { return &f.Timestamp },
+	func(f *Frame) *float64 { return &f.Brightness },
+	func(f *Frame) *float64 { return &f.ColorDifference },
+	func(f *Frame) *float64 { return &f.BinaryThresholdDifference },
+}
+
+func createDecodedFrameCollection(frames []*Frame) (FrameCollection, error) {
+	offset := 0
+	if len(frames) > 0 {
+		if frames[0].OrdinalNumber < 1 {
+			return nil, fmt.Errorf("frame: invalid decoded frame ordinal number")
+		}
+
+		offset = frames[0].OrdinalNumber - 1
+	}
+
+	// NOTE: The collection capacity must be greater than zero, therefore the empty collection is created with a single slot capacity
+	fc := NewOffsetFrameCollection(max(len(frames), 1), offset)
 	defer fc.Lock()
 
 	for _, frame := range frames {
@@ -2,7 +2,13 @@ package frame
 
 import (
 	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/hex"
 	"image/color"
+	"io"
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,26 +34,27 @@ func TestExportCachedFrameCollectionShouldExportAndImport(t *testing.T) {
 		var (
 			collection       FrameCollection = mockFrameCollection(c)
 			checksum         string          = "abcdef12345678900987654321abcdef12345678"
+			fingerprint      string          = "12345678900987654321abcdef12345678abcdef"
 			frames           []*Frame        = collection.GetAll()
 			importCollection FrameCollection
-			importChecksum   string
+			importHeader     CacheHeader
 			importFrames     []*Frame
 		)
 
-		err := ExportCachedFrameCollection(file, collection, checksum)
+		err := ExportCachedFrameCollection(file, collection, checksum, fingerprint)
 		assert.Nil(t, err)
 
 		if _, err := filePeek.Write(file.Bytes()); err != nil {
 			panic(err)
 		}
 
-		checksumEqual, err := ChecksumEqualPeek(filePeek, checksum)
+		peekHeader, err := PeekCacheHeader(filePeek)
 		assert.Nil(t, err)
-		assert.True(t, checksumEqual)
+		assert.Equal(t, CacheHeader{Version: FormatVersion2, Checksum: checksum, Fingerprint: fingerprint}, peekHeader)
 
-		importCollection, importChecksum, err = ImportCachedFrameCollection(file)
+		importCollection, importHeader, err = ImportCachedFrameCollection(file)
 		assert.Nil(t, err)
-		assert.Equal(t, checksum, importChecksum)
+		assert.Equal(t, peekHeader, importHeader)
 		assert.Equal(t, collection.Count(), importCollection.Count())
 
 		importFrames = importCollection.GetAll()
@@ -59,6 +66,7 @@ func TestExportCachedFrameCollectionShouldExportAndImport(t *testing.T) {
 			)
 
 			assert.Equal(t, frame.OrdinalNumber, importFrame.OrdinalNumber)
+			assert.Equal(t, frame.Timestamp, importFrame.Timestamp)
 			assert.Equal(t, frame.Brightness, importFrame.Brightness)
 			assert.Equal(t, frame.ColorDifference, importFrame.ColorDifference)
 			assert.Equal(t, frame.BinaryThresholdDifference, importFrame.BinaryThresholdDifference)
@@ -66,13 +74,318 @@ func TestExportCachedFrameCollectionShouldExportAndImport(t *testing.T) {
 	}
 }
 
+func TestExportCachedFrameCollectionShouldEncodeEmptyFingerprint(t *testing.T) {
+	file := &bytes.Buffer{}
+
+	err := ExportCachedFrameCollection(file, mockFrameCollection(3), "abcdef12345678900987654321abcdef12345678", "")
+	assert.Nil(t, err)
+
+	_, header, err := ImportCachedFrameCollection(file)
+	assert.Nil(t, err)
+	assert.Empty(t, header.Fingerprint)
+
+	err = ExportCachedFrameCollection(&bytes.Buffer{}, mockFrameCollection(3), "abcdef", "")
+	assert.NotNil(t, err)
+
+	err = ExportCachedFrameCollection(&bytes.Buffer{}, mockFrameCollection(3), "abcdef12345678900987654321abcdef12345678", "abcdef")
+	assert.NotNil(t, err)
+}
+
+func TestImportCachedFrameCollectionShouldImportVersion1(t *testing.T) {
+	var (
+		collection FrameCollection = mockFrameCollection(10)
+		checksum   []uint8         = make([]uint8, chceksumDecodedLength)
+		data       *bytes.Buffer   = &bytes.Buffer{}
+		file       *bytes.Buffer   = &bytes.Buffer{}
+	)
+
+	for index := range checksum {
+		checksum[index] = uint8(index)
+	}
+
+	for _, frame := range collection.GetAll() {
+		binary.Write(data, binary.LittleEndian, uint32(frame.OrdinalNumber))
+		binary.Write(data, binary.LittleEndian, frame.Brightness)
+		binary.Write(data, binary.LittleEndian, frame.ColorDifference)
+		binary.Write(data, binary.LittleEndian, frame.BinaryThresholdDifference)
+	}
+
+	file.Write(magicSequence)
+	file.Write([]uint8{FormatVersion1})
+	file.Write(magicSequence)
+	file.Write(checksum)
+	file.Write(magicSequence)
+	file.Write([]uint8{plainData})
+	file.Write(magicSequence)
+	binary.Write(file, binary.LittleEndian, uint32(data.Len()))
+	file.Write(magicSequence)
+	file.Write(data.Bytes())
+
+	importCollection, header, err := ImportCachedFrameCollection(file)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheHeader{Version: FormatVersion1, Checksum: hex.EncodeToString(checksum)}, header)
+	assert.Equal(t, collection.Count(), importCollection.Count())
+
+	for index, frame := range collection.GetAll() {
+		assert.Equal(t, frame.OrdinalNumber, importCollection.GetAll()[index].OrdinalNumber)
+		assert.Equal(t, frame.Brightness, importCollection.GetAll()[index].Brightness)
+	}
+}
+
+func TestPeekCacheHeaderShouldRejectUnsupportedVersion(t *testing.T) {
+	file := &bytes.Buffer{}
+	file.Write(magicSequence)
+	file.Write([]uint8{0x03})
+	file.Write(magicSequence)
+
+	_, err := PeekCacheHeader(file)
+	assert.NotNil(t, err)
+}
+
 func mockFrameCollection(capacity int) FrameCollection {
 	fc := NewFrameCollection(capacity)
 	defer fc.Lock()
 
 	for index := 0; index < capacity; index += 1 {
-		fc.Push(CreateNewFrame(mockImage(color.White), mockImage(color.White), index+1, BinaryThresholdParam))
+		frame := CreateNewFrame(mockImage(color.White), mockImage(color.White), index+1, BinaryThresholdParam)
+		frame.Timestamp = float64(index) / 30
+
+		fc.Push(frame)
+	}
+
+	return fc
+}
+
+func TestExportCachedFrameCollectionShouldCompressLargeCollections(t *testing.T) {
+	const (
+		checksum    string = "abcdef12345678900987654321abcdef12345678"
+		fingerprint string = "12345678900987654321abcdef12345678abcdef"
+	)
+
+	for _, count := range []int{10, plainDataEntryThreshold + 1, 100000} {
+		collection := mockSignalFrameCollection(count)
+		file := &bytes.Buffer{}
+
+		err := ExportCachedFrameCollection(file, collection, checksum, fingerprint)
+		assert.Nil(t, err)
+
+		compression := file.Bytes()[57]
+		if count > plainDataEntryThreshold {
+			assert.Equal(t, flateColumnData, compression)
+			assert.Less(t, file.Len(), count*(4+4*8)/2)
+		} else {
+			assert.Equal(t, plainColumnData, compression)
+		}
+
+		importCollection, _, err := ImportCachedFrameCollection(file)
+		assert.Nil(t, err)
+		assertFrameCollectionsEqual(t, collection, importCollection)
+	}
+}
+
+func TestImportCachedFrameCollectionShouldImportRowData(t *testing.T) {
+	collection := mockSignalFrameCollection(plainDataEntryThreshold + 1)
+
+	for _, compression := range []uint8{plainData, flateData} {
+		data := &bytes.Buffer{}
+
+		if compression == flateData {
+			flateWriter, err := flate.NewWriter(data, flate.BestSpeed)
+			assert.Nil(t, err)
+			assert.Nil(t, encodeFrameCollectionPlain(flateWriter, collection))
+			assert.Nil(t, flateWriter.Close())
+		} else {
+			assert.Nil(t, encodeFrameCollectionPlain(data, collection))
+		}
+
+		file := &bytes.Buffer{}
+		file.Write(magicSequence)
+		file.Write([]uint8{FormatVersion2})
+		file.Write(magicSequence)
+		file.Write(make([]uint8, chceksumDecodedLength))
+		file.Write(magicSequence)
+		file.Write(make([]uint8, fingerprintDecodedLength))
+		file.Write(magicSequence)
+		file.Write([]uint8{compression})
+		file.Write(magicSequence)
+		binary.Write(file, binary.LittleEndian, uint32(data.Len()))
+		file.Write(magicSequence)
+		file.Write(data.Bytes())
+
+		importCollection, _, err := ImportCachedFrameCollection(file)
+		assert.Nil(t, err)
+		assertFrameCollectionsEqual(t, collection, importCollection)
+	}
+}
+
+func TestImportCachedFrameCollectionShouldImportEmptyCollection(t *testing.T) {
+	file := &bytes.Buffer{}
+
+	collection := NewFrameCollection(1)
+	collection.Lock()
+
+	err := ExportCachedFrameCollection(file, collection, "abcdef12345678900987654321abcdef12345678", "")
+	assert.Nil(t, err)
+
+	importCollection, _, err := ImportCachedFrameCollection(file)
+	assert.Nil(t, err)
+	assert.Zero(t, importCollection.Count())
+}
+
+func TestImportCachedFrameCollectionShouldLimitTheDecompressedData(t *testing.T) {
+	data := &bytes.Buffer{}
+
+	flateWriter, err := flate.NewWriter(data, flate.BestCompression)
+	assert.Nil(t, err)
+
+	binary.Write(flateWriter, binary.LittleEndian, uint32(1))
+	flateWriter.Write(make([]uint8, 1024*1024))
+	assert.Nil(t, flateWriter.Close())
+
+	file := &bytes.Buffer{}
+	file.Write(magicSequence)
+	file.Write([]uint8{FormatVersion2})
+	file.Write(magicSequence)
+	file.Write(make([]uint8, chceksumDecodedLength))
+	file.Write(magicSequence)
+	file.Write(make([]uint8, fingerprintDecodedLength))
+	file.Write(magicSequence)
+	file.Write([]uint8{flateColumnData})
+	file.Write(magicSequence)
+	binary.Write(file, binary.LittleEndian, uint32(data.Len()))
+	file.Write(magicSequence)
+	file.Write(data.Bytes())
+
+	_, _, err = ImportCachedFrameCollection(file)
+	assert.ErrorContains(t, err, "exceeding the frames count")
+}
+
+func TestImportAppendedFrameCollectionShouldImportTheAppendedCaches(t *testing.T) {
+	const checksum string = "abcdef12345678900987654321abcdef12345678"
+
+	var (
+		collection FrameCollection = mockSignalFrameCollection(30)
+		frames     []*Frame        = collection.GetAll()
+		file       *bytes.Buffer   = &bytes.Buffer{}
+	)
+
+	exportFrames := func(w io.Writer, frames []*Frame, checksum string) {
+		fc := NewOffsetFrameCollection(len(frames), frames[0].OrdinalNumber-1)
+		for _, frame := range frames {
+			assert.Nil(t, fc.Push(frame))
+		}
+
+		fc.Lock()
+		assert.Nil(t, ExportCachedFrameCollection(w, fc, checksum, ""))
+	}
+
+	for first := 0; first < len(frames); first += 10 {
+		exportFrames(file, frames[first:first+10], checksum)
+	}
+
+	incomplete := &bytes.Buffer{}
+	exportFrames(incomplete, mockSignalFrameCollection(40).GetAll()[30:], checksum)
+
+	cases := [][]byte{
+		nil,
+		incomplete.Bytes()[:incomplete.Len()-1],
+	}
+
+	for _, c := range cases {
+		importCollection, importHeader, err := ImportAppendedFrameCollection(bytes.NewReader(append(slices.Clone(file.Bytes()), c...)))
+		assert.Nil(t, err)
+		assert.Equal(t, checksum, importHeader.Checksum)
+		assertFrameCollectionsEqual(t, collection, importCollection)
+	}
+
+	notContinuing := &bytes.Buffer{}
+	exportFrames(notContinuing, frames[:10], checksum)
+	exportFrames(notContinuing, frames[20:], checksum)
+
+	importCollection, _, err := ImportAppendedFrameCollection(notContinuing)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, importCollection.Count())
+
+	otherHeader := &bytes.Buffer{}
+	exportFrames(otherHeader, frames[:10], checksum)
+	exportFrames(otherHeader, frames[10:], "12345678900987654321abcdef12345678abcdef")
+
+	importCollection, _, err = ImportAppendedFrameCollection(otherHeader)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, importCollection.Count())
+
+	_, _, err = ImportAppendedFrameCollection(bytes.NewReader(incomplete.Bytes()[:incomplete.Len()-1]))
+	assert.NotNil(t, err)
+}
+
+func FuzzImportCachedFrameCollection(f *testing.F) {
+	for _, count := range []int{0, 1, 16, plainDataEntryThreshold + 1} {
+		collection := NewFrameCollection(max(count, 1))
+		for _, frame := range mockSignalFrameCollection(max(count, 1)).GetAll()[:count] {
+			collection.Push(frame)
+		}
+
+		collection.Lock()
+
+		file := &bytes.Buffer{}
+		if err := ExportCachedFrameCollection(file, collection, "abcdef12345678900987654321abcdef12345678", ""); err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(file.Bytes())
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		collection, header, err := ImportCachedFrameCollection(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		file := &bytes.Buffer{}
+		if err := ExportCachedFrameCollection(file, collection, header.Checksum, header.Fingerprint); err != nil {
+			t.Fatalf("failed to export the imported collection: %s", err)
+		}
+
+		importCollection, importHeader, err := ImportCachedFrameCollection(file)
+		if err != nil {
+			t.Fatalf("failed to import the exported collection: %s", err)
+		}
+
+		assert.Equal(t, header.Checksum, importHeader.Checksum)
+		assert.Equal(t, header.Fingerprint, importHeader.Fingerprint)
+		assertFrameCollectionsEqual(t, collection, importCollection)
+	})
+}
+
+func mockSignalFrameCollection(count int) FrameCollection {
+	fc := NewOffsetFrameCollection(count, 100)
+	defer fc.Lock()
+
+	for index := 0; index < count; index += 1 {
+		value := 0.1 + 0.02*math.Sin(float64(index)*0.01)
+
+		fc.Push(&Frame{
+			OrdinalNumber:             101 + index,
+			Timestamp:                 float64(100+index) / 25,
+			Brightness:                value,
+			ColorDifference:           value / 2,
+			BinaryThresholdDifference: 0,
+		})
 	}
 
 	return fc
 }
+
+func assertFrameCollectionsEqual(t *testing.T, expected, actual FrameCollection) {
+	assert.Equal(t, expected.Count(), actual.Count())
+
+	for index, frame := range expected.GetAll() {
+		actualFrame := actual.GetAll()[index]
+
+		assert.Equal(t, frame.OrdinalNumber, actualFrame.OrdinalNumber)
+
+		for _, column := range frameColumns {
+			assert.Equal(t, math.Float64bits(*column(frame)), math.Float64bits(*column(actualFrame)))
+		}
+	}
+}
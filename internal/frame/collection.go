@@ -17,6 +17,7 @@ type FrameCollection interface {
 type frameCollection struct {
 	Frames   []*Frame
 	Index    int
+	Offset   int
 	Capacity int
 	Locked   bool
 }
@@ -30,7 +31,7 @@ func (fc *frameCollection) Push(frame *Frame) error {
 		return fmt.Errorf("frame: frame collection is locked")
 	}
 
-	if fc.Index != frame.OrdinalNumber-1 {
+	if fc.Index != frame.OrdinalNumber-1-fc.Offset {
 		return fmt.Errorf("frame: collection indexing and provided frame order missmatch")
 	}
 
@@ -67,13 +68,24 @@ func (fc *frameCollection) Lock() {
 }
 
 func NewFrameCollection(cap int) FrameCollection {
+	return NewOffsetFrameCollection(cap, 0)
+}
+
+// Create a new frame collection which is expecting the first pushed frame to be the frame following the given number
+// of offset frames. The collection is used to store frames of a video range that is not starting at the first frame.
+func NewOffsetFrameCollection(cap, offset int) FrameCollection {
 	if cap <= 0 {
 		panic("frame: frame collection capacity must be greater than zero")
 	}
 
+	if offset < 0 {
+		panic("frame: frame collection offset can not be negative")
+	}
+
 	return &frameCollection{
 		Frames:   make([]*Frame, cap, baseFrameCollectionCapacity+cap),
 		Index:    0,
+		Offset:   offset,
 		Capacity: cap,
 		Locked:   false,
 	}
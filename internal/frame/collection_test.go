@@ -85,6 +85,35 @@ func TestFramesCollectionShouldNotPushInvalidFrame(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestFramesCollectionWithOffsetShouldPushFramesFollowingTheOffset(t *testing.T) {
+	assert.Panics(t, func() {
+		NewOffsetFrameCollection(1, -1)
+	})
+
+	collection := NewOffsetFrameCollection(2, 10)
+
+	frame := CreateNewFrame(mockImage(color.White), mockImage(color.White), 1, BinaryThresholdParam)
+	err := collection.Push(frame)
+	assert.NotNil(t, err)
+
+	frame = CreateNewFrame(mockImage(color.White), mockImage(color.White), 11, BinaryThresholdParam)
+	err = collection.Push(frame)
+	assert.Nil(t, err)
+
+	frame = CreateNewFrame(mockImage(color.White), mockImage(color.White), 13, BinaryThresholdParam)
+	err = collection.Push(frame)
+	assert.NotNil(t, err)
+
+	frame = CreateNewFrame(mockImage(color.White), mockImage(color.White), 12, BinaryThresholdParam)
+	err = collection.Push(frame)
+	assert.Nil(t, err)
+
+	collection.Lock()
+
+	assert.Equal(t, 2, collection.Count())
+	assert.Equal(t, 11, collection.GetAll()[0].OrdinalNumber)
+}
+
 func TestFramesCollectionShouldCorrectlyHandleAccess(t *testing.T) {
 	collection := NewFrameCollection(1)
 
@@ -17,6 +17,7 @@ const (
 // Strucutre representing a single video frame and its calculated parameters.
 type Frame struct {
 	OrdinalNumber             int     `json:"ordinal-number"`
+	Timestamp                 float64 `json:"timestamp"`
 	ColorDifference           float64 `json:"color-difference"`
 	BinaryThresholdDifference float64 `json:"binary-threshold-difference"`
 	Brightness                float64 `json:"brightness"`
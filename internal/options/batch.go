@@ -0,0 +1,54 @@
+package options
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// Structure representing the options for the batch detector processing multiple video files of a directory.
+type BatchOptions struct {
+	IncludePatterns []string
+	ExcludePatterns []string
+	Recursive       bool
+	Concurrency     int
+}
+
+// Return a boolean value representing if the batch options are valid. If any validation errors occured
+// a message will be stored in the string return value.
+func (options *BatchOptions) AreValid() (bool, string) {
+	if len(options.IncludePatterns) == 0 {
+		return false, "at least one include pattern must be specified"
+	}
+
+	for _, pattern := range slices.Concat(options.IncludePatterns, options.ExcludePatterns) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return false, "the include and exclude patterns must be valid glob patterns"
+		}
+	}
+
+	if options.Concurrency < 1 {
+		return false, "the batch concurrency must be greater than zero"
+	}
+
+	return true, ""
+}
+
+// Create a copy of the batch options
+func (options *BatchOptions) Clone() BatchOptions {
+	return BatchOptions{
+		IncludePatterns: slices.Clone(options.IncludePatterns),
+		ExcludePatterns: slices.Clone(options.ExcludePatterns),
+		Recursive:       options.Recursive,
+		Concurrency:     options.Concurrency,
+	}
+}
+
+// Return the default batch options.
+func GetDefaultBatchOptions() BatchOptions {
+	return BatchOptions{
+		IncludePatterns: []string{"*.mp4", "*.mov", "*.mkv", "*.avi", "*.mts", "*.m4v"},
+		ExcludePatterns: []string{},
+		Recursive:       false,
+		Concurrency:     1,
+	}
+}
@@ -0,0 +1,49 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldValidateDefaultBatchOptions(t *testing.T) {
+	options := GetDefaultBatchOptions()
+
+	valid, msg := options.AreValid()
+	assert.True(t, valid)
+	assert.Empty(t, msg)
+}
+
+func TestShouldNotValidateInvalidBatchPatterns(t *testing.T) {
+	cases := []struct {
+		Include []string
+		Exclude []string
+	}{
+		{[]string{}, []string{}},
+		{[]string{"[.mp4"}, []string{}},
+		{[]string{"*.mp4"}, []string{"[a-"}},
+	}
+
+	for _, c := range cases {
+		options := GetDefaultBatchOptions()
+		options.IncludePatterns = c.Include
+		options.ExcludePatterns = c.Exclude
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidBatchConcurrency(t *testing.T) {
+	cases := []int{0, -1}
+
+	for _, value := range cases {
+		options := GetDefaultBatchOptions()
+		options.Concurrency = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
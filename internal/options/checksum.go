@@ -10,7 +10,8 @@ import (
 
 var byteOrder binary.ByteOrder = binary.LittleEndian
 
-// Generate a SHA1, hex-encoded checksum of the non data dependent detector options
+// Generate a SHA1, hex-encoded checksum of the non data dependent detector options. The checksum is covering only the options affecting
+// the frames analysis, therefore the frames analysed once can be reused by the detection using any thresholds.
 func CalculateChecksum(options DetectorOptions) (string, error) {
 	if ok, _ := options.AreValid(); !ok {
 		return "", fmt.Errorf("options: failed to calculate the checksum for invalid options")
@@ -18,6 +19,50 @@ func CalculateChecksum(options DetectorOptions) (string, error) {
 
 	buffer := &bytes.Buffer{}
 
+	if err := binary.Write(buffer, byteOrder, int64(options.Denoise)); err != nil {
+		return "", fmt.Errorf("options: failed to binary encode the Denoise: %w", err)
+	}
+
+	if err := binary.Write(buffer, byteOrder, options.FrameScalingFactor); err != nil {
+		return "", fmt.Errorf("options: failed to binary encode the FrameScalingFactor: %w", err)
+	}
+
+	if err := binary.Write(buffer, byteOrder, int64(options.ScaleAlgorithm)); err != nil {
+		return "", fmt.Errorf("options: failed to binary encode the ScaleAlgorithm: %w", err)
+	}
+
+	if err := binary.Write(buffer, byteOrder, int64(options.VideoStreamIndex)); err != nil {
+		return "", fmt.Errorf("options: failed to binary encode the VideoStreamIndex: %w", err)
+	}
+
+	// NOTE: The default image sequence frame rate is not encoded to keep the checksums of the video file caches unchanged
+	if options.ImageSequenceFps != 0 {
+		if err := binary.Write(buffer, byteOrder, options.ImageSequenceFps); err != nil {
+			return "", fmt.Errorf("options: failed to binary encode the ImageSequenceFps: %w", err)
+		}
+	}
+
+	// NOTE: The expressions are separated with a character which is not a part of the expressions syntax to prevent ambiguity
+	expressions := options.DetectionBoundsExpression + "|" + options.AnalysisStartExpression + "|" + options.AnalysisEndExpression
+	if _, err := buffer.WriteString(expressions); err != nil {
+		return "", fmt.Errorf("options: failed to encode the DetectionBoundsExpression, AnalysisStartExpression and AnalysisEndExpression: %w", err)
+	}
+
+	hash := sha1.Sum(buffer.Bytes())
+	hashHex := hex.EncodeToString(hash[:])
+
+	return hashHex, nil
+}
+
+// Generate a SHA1, hex-encoded checksum of the non data dependent detector options used by the version 1 of the preanalyzed
+// frames cache. The checksum is not covering all options affecting the analysis and is used only to read the legacy caches.
+func CalculateLegacyChecksum(options DetectorOptions) (string, error) {
+	if ok, _ := options.AreValid(); !ok {
+		return "", fmt.Errorf("options: failed to calculate the checksum for invalid options")
+	}
+
+	buffer := &bytes.Buffer{}
+
 	if !options.AutoThresholds {
 		if err := binary.Write(buffer, byteOrder, options.BinaryThresholdDifferenceDetectionThreshold); err != nil {
 			return "", fmt.Errorf("options: failed to binary encode the BinaryThresholdDifferenceDetectionThreshold: %w", err)
@@ -40,8 +85,41 @@ func CalculateChecksum(options DetectorOptions) (string, error) {
 		return "", fmt.Errorf("options: failed to binary encode the FrameScalingFactor: %w", err)
 	}
 
+	if len(options.AnalysisStartExpression) != 0 || len(options.AnalysisEndExpression) != 0 {
+		if _, err := buffer.WriteString(options.AnalysisStartExpression + "/" + options.AnalysisEndExpression); err != nil {
+			return "", fmt.Errorf("options: failed to encode the AnalysisStartExpression and AnalysisEndExpression: %w", err)
+		}
+	}
+
+	if options.VideoStreamIndex >= 0 {
+		if err := binary.Write(buffer, byteOrder, int64(options.VideoStreamIndex)); err != nil {
+			return "", fmt.Errorf("options: failed to binary encode the VideoStreamIndex: %w", err)
+		}
+	}
+
 	hash := sha1.Sum(buffer.Bytes())
 	hashHex := hex.EncodeToString(hash[:])
 
 	return hashHex, nil
 }
+
+// Generate a SHA1, hex-encoded checksum of the detector options affecting the detection results and the exported files. In contrast
+// to the analysis checksum, the thresholds, the detection strategy and the export options are covered. The options affecting only the
+// execution of the detector such as the workers count, the checkpoints and the cache location are not covered.
+func CalculateDetectionChecksum(options DetectorOptions) (string, error) {
+	if ok, _ := options.AreValid(); !ok {
+		return "", fmt.Errorf("options: failed to calculate the checksum for invalid options")
+	}
+
+	o := options.Clone()
+	o.AnalysisWorkers = 0
+	o.CheckpointInterval = 0
+	o.CacheDirectoryPath = ""
+	o.ImportPreanalyzed = false
+
+	// NOTE: The options structure contains only value types, therefore its formatted representation is deterministic
+	hash := sha1.Sum([]byte(fmt.Sprintf("%+v", o)))
+	hashHex := hex.EncodeToString(hash[:])
+
+	return hashHex, nil
+}
@@ -26,3 +26,99 @@ func TestDefaultOptionsShouldProduceTheSameChecksum(t *testing.T) {
 
 	assert.Equal(t, a, b)
 }
+
+func TestChecksumShouldCoverTheAnalysisOptions(t *testing.T) {
+	base, err := CalculateChecksum(GetDefaultDetectorOptions())
+	assert.Nil(t, err)
+
+	cases := []func(o *DetectorOptions){
+		func(o *DetectorOptions) { o.Denoise = StackBlur8 },
+		func(o *DetectorOptions) { o.FrameScalingFactor = 0.25 },
+		func(o *DetectorOptions) { o.ScaleAlgorithm = Bicubic },
+		func(o *DetectorOptions) { o.DetectionBoundsExpression = "0:0:10:10" },
+		func(o *DetectorOptions) { o.AnalysisStartExpression = "10" },
+		func(o *DetectorOptions) { o.AnalysisEndExpression = "10" },
+		func(o *DetectorOptions) { o.VideoStreamIndex = 1 },
+		func(o *DetectorOptions) { o.ImageSequenceFps = 10 },
+	}
+
+	for _, c := range cases {
+		o := GetDefaultDetectorOptions()
+		c(&o)
+
+		checksum, err := CalculateChecksum(o)
+		assert.Nil(t, err)
+		assert.NotEqual(t, base, checksum)
+	}
+
+	cases = []func(o *DetectorOptions){
+		func(o *DetectorOptions) { o.AnalysisWorkers = 4 },
+		func(o *DetectorOptions) { o.AutoThresholds = false },
+		func(o *DetectorOptions) {
+			o.AutoThresholds = false
+			o.BrightnessDetectionThreshold = 0.5
+			o.ColorDifferenceDetectionThreshold = 0.5
+			o.BinaryThresholdDifferenceDetectionThreshold = 0.5
+		},
+	}
+
+	for _, c := range cases {
+		o := GetDefaultDetectorOptions()
+		c(&o)
+
+		checksum, err := CalculateChecksum(o)
+		assert.Nil(t, err)
+		assert.Equal(t, base, checksum)
+	}
+}
+
+func TestLegacyChecksumShouldDifferFromTheChecksum(t *testing.T) {
+	legacy, err := CalculateLegacyChecksum(GetDefaultDetectorOptions())
+	assert.Nil(t, err)
+
+	checksum, err := CalculateChecksum(GetDefaultDetectorOptions())
+	assert.Nil(t, err)
+
+	assert.Len(t, legacy, 40)
+	assert.NotEqual(t, checksum, legacy)
+}
+
+func TestDetectionChecksumShouldCoverTheDetectionAndExportOptions(t *testing.T) {
+	base, err := CalculateDetectionChecksum(GetDefaultDetectorOptions())
+	assert.Nil(t, err)
+
+	cases := []func(o *DetectorOptions){
+		func(o *DetectorOptions) { o.Denoise = StackBlur8 },
+		func(o *DetectorOptions) { o.AutoThresholds = !o.AutoThresholds },
+		func(o *DetectorOptions) { o.BrightnessDetectionThreshold = 0.5 },
+		func(o *DetectorOptions) { o.VotingScoreThreshold = 0.75 },
+		func(o *DetectorOptions) { o.StrikeEventGapTolerance = 5 },
+		func(o *DetectorOptions) { o.ExportCsvReport = !o.ExportCsvReport },
+		func(o *DetectorOptions) { o.ExportCompositeImage = !o.ExportCompositeImage },
+	}
+
+	for _, c := range cases {
+		o := GetDefaultDetectorOptions()
+		c(&o)
+
+		checksum, err := CalculateDetectionChecksum(o)
+		assert.Nil(t, err)
+		assert.NotEqual(t, base, checksum)
+	}
+
+	cases = []func(o *DetectorOptions){
+		func(o *DetectorOptions) { o.AnalysisWorkers = 4 },
+		func(o *DetectorOptions) { o.CheckpointInterval = 100 },
+		func(o *DetectorOptions) { o.CacheDirectoryPath = "cache" },
+		func(o *DetectorOptions) { o.ImportPreanalyzed = !o.ImportPreanalyzed },
+	}
+
+	for _, c := range cases {
+		o := GetDefaultDetectorOptions()
+		c(&o)
+
+		checksum, err := CalculateDetectionChecksum(o)
+		assert.Nil(t, err)
+		assert.Equal(t, base, checksum)
+	}
+}
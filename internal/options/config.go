@@ -0,0 +1,360 @@
+package options
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configTagName = "config"
+
+// The reserved configuration file key containing the named profiles.
+const configProfilesKey = "profiles"
+
+// Structure representing the detector options configuration file. The file keys are matching the names of the command flags and
+// are mapped onto both the detector options and the stream detector options. The top-level values are applied first and are
+// followed by the values of the selected named profile.
+type Config struct {
+	Values   map[string]any
+	Profiles map[string]map[string]any
+}
+
+// Load the YAML or JSON configuration file specified by the path. The keys are validated against the known option keys and the
+// error message is pointing to the offending key.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("options: failed to read the configuration file: %w", err)
+	}
+
+	return ParseConfig(data)
+}
+
+// Parse the YAML or JSON configuration file content. JSON is handled by the YAML parser as it is a subset of YAML.
+func ParseConfig(data []byte) (Config, error) {
+	raw := make(map[string]any)
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return Config{}, fmt.Errorf("options: failed to parse the configuration file: %w", err)
+	}
+
+	config := Config{
+		Values:   make(map[string]any),
+		Profiles: make(map[string]map[string]any),
+	}
+
+	for key, value := range raw {
+		if key != configProfilesKey {
+			if !IsValidConfigKey(key) {
+				return Config{}, fmt.Errorf("options: invalid unknown configuration key %q", key)
+			}
+
+			config.Values[key] = value
+			continue
+		}
+
+		profiles, ok := value.(map[string]any)
+		if !ok {
+			return Config{}, fmt.Errorf("options: the configuration key %q must be a mapping of profile names", configProfilesKey)
+		}
+
+		for name, profileValue := range profiles {
+			profile, ok := profileValue.(map[string]any)
+			if !ok && profileValue != nil {
+				return Config{}, fmt.Errorf("options: the configuration profile %q must be a mapping of keys", name)
+			}
+
+			for key := range profile {
+				if !IsValidConfigKey(key) {
+					return Config{}, fmt.Errorf("options: invalid unknown configuration key %q in profile %q", key, name)
+				}
+			}
+
+			config.Profiles[name] = profile
+		}
+	}
+
+	return config, nil
+}
+
+// Get the configuration values merged with the values of the named profile. The profiles defined in the configuration file take
+// precedence over the built-in profiles. An empty profile name is selecting only the top-level values.
+func (config *Config) GetValues(profile string) (map[string]any, error) {
+	values := make(map[string]any, len(config.Values))
+	for key, value := range config.Values {
+		values[key] = value
+	}
+
+	if len(profile) == 0 {
+		return values, nil
+	}
+
+	profileValues, ok := config.Profiles[profile]
+	if !ok {
+		if profileValues, ok = builtInProfiles[profile]; !ok {
+			return nil, fmt.Errorf("options: invalid unknown configuration profile %q", profile)
+		}
+	}
+
+	for key, value := range profileValues {
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// Return a boolean value representing if the key is mapped onto the detector options or the stream detector options.
+func IsValidConfigKey(key string) bool {
+	_, detectorOk := configFieldIndex(reflect.TypeFor[DetectorOptions](), key)
+	_, streamOk := configFieldIndex(reflect.TypeFor[StreamDetectorOptions](), key)
+	return detectorOk || streamOk
+}
+
+// Get the names of the built-in and configuration file profiles.
+func (config *Config) GetProfileNames() []string {
+	names := GetBuiltInProfileNames()
+	for name := range config.Profiles {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// Apply the configuration values onto the detector options. The keys not mapped onto the detector options are ignored. The options are
+// validated after applying the values and the error message is pointing to the offending key.
+func (options *DetectorOptions) ApplyConfig(values map[string]any) error {
+	return applyConfig(options, values)
+}
+
+// Apply the configuration values onto the stream detector options. The keys not mapped onto the stream detector options are ignored. The
+// options are validated after applying the values and the error message is pointing to the offending key.
+func (options *StreamDetectorOptions) ApplyConfig(values map[string]any) error {
+	return applyConfig(options, values)
+}
+
+// Get the configuration values of the detector options specified by the keys. The enumeration values are represented by their names.
+func (options *DetectorOptions) GetConfigValues(keys ...string) (map[string]any, error) {
+	return getConfigValues(reflect.ValueOf(options).Elem(), keys)
+}
+
+// Marshal the YAML configuration file containing the single named profile with the given values.
+func MarshalConfigProfile(name string, values map[string]any) ([]byte, error) {
+	data, err := yaml.Marshal(map[string]any{
+		configProfilesKey: map[string]any{
+			name: values,
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("options: failed to marshal the configuration profile: %w", err)
+	}
+
+	return data, nil
+}
+
+func getConfigValues(options reflect.Value, keys []string) (map[string]any, error) {
+	values := make(map[string]any, len(keys))
+	for _, key := range keys {
+		index, ok := configFieldIndex(options.Type(), key)
+		if !ok {
+			return nil, fmt.Errorf("options: invalid unknown configuration key %q", key)
+		}
+
+		field := options.Field(index)
+
+		if stringer, ok := field.Addr().Interface().(fmt.Stringer); ok {
+			values[key] = stringer.String()
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.Int, reflect.Int32:
+			values[key] = int(field.Int())
+		default:
+			values[key] = field.Interface()
+		}
+	}
+
+	return values, nil
+}
+
+type validatableOptions interface {
+	AreValid() (bool, string)
+}
+
+func applyConfig[T any, PT interface {
+	*T
+	validatableOptions
+}](options PT, values map[string]any) error {
+	base := *options
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if _, ok := configFieldIndex(reflect.TypeFor[T](), key); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if err := setConfigValue(reflect.ValueOf(options).Elem(), key, values[key]); err != nil {
+			return err
+		}
+	}
+
+	ok, msg := options.AreValid()
+	if ok {
+		return nil
+	}
+
+	// NOTE: The validation is not pointing to the fields, therefore the offending key is the one without which the options are valid
+	for _, offendingKey := range keys {
+		candidate := base
+		for _, key := range keys {
+			if key != offendingKey {
+				_ = setConfigValue(reflect.ValueOf(&candidate).Elem(), key, values[key])
+			}
+		}
+
+		if ok, _ := PT(&candidate).AreValid(); ok {
+			return fmt.Errorf("options: invalid configuration key %q: %s", offendingKey, msg)
+		}
+	}
+
+	return fmt.Errorf("options: invalid configuration: %s", msg)
+}
+
+func configFieldIndex(t reflect.Type, key string) (int, bool) {
+	for index := range t.NumField() {
+		if t.Field(index).Tag.Get(configTagName) == key {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+type configValueSetter interface {
+	Set(string) error
+}
+
+func setConfigValue(options reflect.Value, key string, value any) error {
+	index, ok := configFieldIndex(options.Type(), key)
+	if !ok {
+		return fmt.Errorf("options: invalid unknown configuration key %q", key)
+	}
+
+	field := options.Field(index)
+
+	if setter, ok := field.Addr().Interface().(configValueSetter); ok {
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("options: the configuration key %q must be a string", key)
+		}
+
+		if err := setter.Set(name); err != nil {
+			return fmt.Errorf("options: invalid configuration key %q value %q: %w", key, name, err)
+		}
+
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("options: the configuration key %q must be a boolean", key)
+		}
+
+		field.SetBool(v)
+	case reflect.Float64:
+		switch v := value.(type) {
+		case int:
+			field.SetFloat(float64(v))
+		case float64:
+			field.SetFloat(v)
+		default:
+			return fmt.Errorf("options: the configuration key %q must be a number", key)
+		}
+	case reflect.Int, reflect.Int32:
+		var v int64
+		switch n := value.(type) {
+		case int:
+			v = int64(n)
+		case float64:
+			if n != math.Trunc(n) {
+				return fmt.Errorf("options: the configuration key %q must be an integer", key)
+			}
+
+			v = int64(n)
+		default:
+			return fmt.Errorf("options: the configuration key %q must be an integer", key)
+		}
+
+		if field.OverflowInt(v) {
+			return fmt.Errorf("options: the configuration key %q value is out of range", key)
+		}
+
+		field.SetInt(v)
+	case reflect.String:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("options: the configuration key %q must be a string", key)
+		}
+
+		field.SetString(v)
+	default:
+		panic(fmt.Sprintf("options: unsupported configuration field kind %s", field.Kind()))
+	}
+
+	return nil
+}
+
+// Get the names of the built-in profiles.
+func GetBuiltInProfileNames() []string {
+	names := make([]string, 0, len(builtInProfiles))
+	for name := range builtInProfiles {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// The built-in profiles for the common recording setups. The keys not applicable to the detector are ignored.
+var builtInProfiles = map[string]map[string]any{
+	// Static camera recording the dark sky. The noise of high sensitivity sensors is reduced and the long moving window is used
+	// as the background is changing slowly.
+	"night-tripod": {
+		"auto-thresholds":        true,
+		"denoise":                "stackblur8",
+		"moving-mean-resolution": 100,
+		"statistics-backend":     "moving-window",
+	},
+	// Moving camera recording a rapidly changing scene. The statistics are adapting quickly to the scene and the color difference
+	// caused by the camera motion is not required to classify the frame.
+	"dashcam": {
+		"detection-strategy":    "z-score",
+		"statistics-backend":    "exponential",
+		"exponential-half-life": 1.0,
+		"voting-rule":           "k-of-n",
+		"voting-rule-k":         2,
+		"denoise":               "stackblur16",
+		"scaling-factor":        0.3,
+	},
+	// Static low quality camera with a low frame rate and strong compression artifacts.
+	"webcam": {
+		"auto-thresholds":            true,
+		"denoise":                    "stackblur16",
+		"moving-mean-resolution":     30,
+		"classification-window-size": 3,
+		"classification-max-gap":     1,
+	},
+}
@@ -0,0 +1,187 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldMapAllOptionsFieldsToConfigKeys(t *testing.T) {
+	for _, optionsType := range []reflect.Type{reflect.TypeFor[DetectorOptions](), reflect.TypeFor[StreamDetectorOptions]()} {
+		keys := make(map[string]bool)
+
+		for index := range optionsType.NumField() {
+			key := optionsType.Field(index).Tag.Get(configTagName)
+			assert.NotEmpty(t, key)
+			assert.False(t, keys[key])
+
+			keys[key] = true
+		}
+	}
+}
+
+func TestShouldParseYamlConfig(t *testing.T) {
+	data := []byte(`
+scaling-factor: 0.25
+denoise: stackblur8
+profiles:
+  custom:
+    voting-rule: k-of-n
+    voting-rule-k: 2
+    diagnostic: true
+`)
+
+	config, err := ParseConfig(data)
+	assert.Nil(t, err)
+
+	values, err := config.GetValues("custom")
+	assert.Nil(t, err)
+
+	detectorOptions := GetDefaultDetectorOptions()
+	assert.Nil(t, detectorOptions.ApplyConfig(values))
+	assert.Equal(t, 0.25, detectorOptions.FrameScalingFactor)
+	assert.Equal(t, StackBlur8, detectorOptions.Denoise)
+	assert.Equal(t, KOfNVoting, detectorOptions.VotingRule)
+	assert.Equal(t, 2, detectorOptions.VotingRuleK)
+
+	streamOptions := GetDefaultStreamDetectorOptions()
+	assert.Nil(t, streamOptions.ApplyConfig(values))
+	assert.Equal(t, 0.25, streamOptions.FrameScalingFactor)
+	assert.True(t, streamOptions.DiagnosticMode)
+}
+
+func TestShouldParseJsonConfig(t *testing.T) {
+	data := []byte(`{ "auto-thresholds": true, "moving-mean-resolution": 80, "start": "10s" }`)
+
+	config, err := ParseConfig(data)
+	assert.Nil(t, err)
+
+	values, err := config.GetValues("")
+	assert.Nil(t, err)
+
+	options := GetDefaultDetectorOptions()
+	assert.Nil(t, options.ApplyConfig(values))
+	assert.True(t, options.AutoThresholds)
+	assert.Equal(t, int32(80), options.MovingMeanResolution)
+	assert.Equal(t, "10s", options.AnalysisStartExpression)
+}
+
+func TestShouldNotParseConfigWithUnknownKeys(t *testing.T) {
+	cases := []string{
+		"unknown-key: 1",
+		"profiles:\n  custom:\n    unknown-key: 1",
+		"profiles: 1",
+		"profiles:\n  custom: 1",
+	}
+
+	for _, data := range cases {
+		_, err := ParseConfig([]byte(data))
+		assert.NotNil(t, err)
+	}
+}
+
+func TestShouldNotApplyConfigWithInvalidValueTypes(t *testing.T) {
+	cases := []map[string]any{
+		{"auto-thresholds": "yes"},
+		{"scaling-factor": "half"},
+		{"moving-mean-resolution": 1.5},
+		{"moving-mean-resolution": 1 << 40},
+		{"denoise": 1},
+		{"denoise": "unknown"},
+		{"start": 10},
+	}
+
+	for _, values := range cases {
+		options := GetDefaultDetectorOptions()
+		assert.NotNil(t, options.ApplyConfig(values))
+	}
+}
+
+func TestShouldPointToTheOffendingConfigKey(t *testing.T) {
+	options := GetDefaultDetectorOptions()
+	err := options.ApplyConfig(map[string]any{
+		"scaling-factor":             0.5,
+		"classification-window-size": 6,
+		"classification-max-gap":     5,
+	})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `"classification-max-gap"`)
+
+	options = GetDefaultDetectorOptions()
+	err = options.ApplyConfig(map[string]any{
+		"scaling-factor": 2,
+		"denoise":        "stackblur8",
+	})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `"scaling-factor"`)
+}
+
+func TestShouldIgnoreConfigKeysNotApplicableToTheOptions(t *testing.T) {
+	options := GetDefaultStreamDetectorOptions()
+	assert.Nil(t, options.ApplyConfig(map[string]any{"auto-thresholds": true, "workers": 4}))
+	assert.Equal(t, GetDefaultStreamDetectorOptions(), options)
+}
+
+func TestShouldPreferConfigFileProfilesOverBuiltInProfiles(t *testing.T) {
+	config, err := ParseConfig([]byte("profiles:\n  dashcam:\n    scaling-factor: 0.1"))
+	assert.Nil(t, err)
+
+	values, err := config.GetValues("dashcam")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"scaling-factor": 0.1}, values)
+
+	_, err = config.GetValues("unknown")
+	assert.NotNil(t, err)
+
+	assert.Contains(t, config.GetProfileNames(), "dashcam")
+	assert.Contains(t, config.GetProfileNames(), "webcam")
+}
+
+func TestShouldValidateBuiltInProfiles(t *testing.T) {
+	config := Config{}
+
+	for _, name := range GetBuiltInProfileNames() {
+		values, err := config.GetValues(name)
+		assert.Nil(t, err)
+
+		for key := range values {
+			assert.True(t, IsValidConfigKey(key))
+		}
+
+		detectorOptions := GetDefaultDetectorOptions()
+		assert.Nil(t, detectorOptions.ApplyConfig(values))
+
+		streamOptions := GetDefaultStreamDetectorOptions()
+		assert.Nil(t, streamOptions.ApplyConfig(values))
+	}
+}
+
+func TestShouldMarshalConfigProfileOfOptions(t *testing.T) {
+	options := GetDefaultDetectorOptions()
+	options.DetectionStrategy = AboveMovingZScoreAllWeights
+	options.MovingMeanResolution = 80
+	options.BrightnessDetectionThreshold = 0.125
+
+	values, err := options.GetConfigValues("detection-strategy", "moving-mean-resolution", "brightness-threshold", "auto-thresholds")
+	assert.Nil(t, err)
+
+	data, err := MarshalConfigProfile("tuned", values)
+	assert.Nil(t, err)
+
+	config, err := ParseConfig(data)
+	assert.Nil(t, err)
+
+	profileValues, err := config.GetValues("tuned")
+	assert.Nil(t, err)
+
+	actual := GetDefaultDetectorOptions()
+	actual.AutoThresholds = true
+	assert.Nil(t, actual.ApplyConfig(profileValues))
+	assert.Equal(t, options, actual)
+
+	_, err = options.GetConfigValues("unknown-key")
+	assert.NotNil(t, err)
+}
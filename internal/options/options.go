@@ -6,23 +6,61 @@ import (
 
 // Structure representing the options for the detector.
 type DetectorOptions struct {
-	AutoThresholds                              bool
-	BrightnessDetectionThreshold                float64
-	ColorDifferenceDetectionThreshold           float64
-	BinaryThresholdDifferenceDetectionThreshold float64
-	MovingMeanResolution                        int32
-	ExportCsvReport                             bool
-	ExportJsonReport                            bool
-	ExportChartReport                           bool
-	ExportConfusionMatrix                       bool
-	ConfusionMatrixActualDetectionsExpression   string
-	SkipFramesExport                            bool
-	Denoise                                     DenoiseAlgorithm
-	FrameScalingFactor                          float64
-	ImportPreanalyzed                           bool
-	StrictExplicitThreshold                     bool
-	DetectionBoundsExpression                   string
-	ScaleAlgorithm                              ScaleAlgorithm
+	AutoThresholds                                          bool                  `config:"auto-thresholds"`
+	AutoThresholdStrategy                                   AutoThresholdStrategy `config:"auto-threshold-strategy"`
+	AutoThresholdPercentile                                 float64               `config:"auto-threshold-percentile"`
+	AutoThresholdOtsuBins                                   int                   `config:"auto-threshold-otsu-bins"`
+	BrightnessAutoThresholdMeanCoefficient                  float64               `config:"brightness-auto-threshold-mean-coefficient"`
+	BrightnessAutoThresholdStdDevCoefficient                float64               `config:"brightness-auto-threshold-stddev-coefficient"`
+	ColorDifferenceAutoThresholdMeanCoefficient             float64               `config:"color-difference-auto-threshold-mean-coefficient"`
+	ColorDifferenceAutoThresholdStdDevCoefficient           float64               `config:"color-difference-auto-threshold-stddev-coefficient"`
+	BinaryThresholdDifferenceAutoThresholdMeanCoefficient   float64               `config:"binary-threshold-difference-auto-threshold-mean-coefficient"`
+	BinaryThresholdDifferenceAutoThresholdStdDevCoefficient float64               `config:"binary-threshold-difference-auto-threshold-stddev-coefficient"`
+	BrightnessDetectionThreshold                            float64               `config:"brightness-threshold"`
+	ColorDifferenceDetectionThreshold                       float64               `config:"color-difference-threshold"`
+	BinaryThresholdDifferenceDetectionThreshold             float64               `config:"binary-threshold-difference-threshold"`
+	DetectionStrategy                                       DetectionStrategy     `config:"detection-strategy"`
+	BrightnessDetectionZScore                               float64               `config:"brightness-z-score"`
+	ColorDifferenceDetectionZScore                          float64               `config:"color-difference-z-score"`
+	BinaryThresholdDifferenceDetectionZScore                float64               `config:"binary-threshold-difference-z-score"`
+	VotingRule                                              VotingRule            `config:"voting-rule"`
+	VotingRuleK                                             int                   `config:"voting-rule-k"`
+	BrightnessVotingWeight                                  float64               `config:"brightness-voting-weight"`
+	ColorDifferenceVotingWeight                             float64               `config:"color-difference-voting-weight"`
+	BinaryThresholdDifferenceVotingWeight                   float64               `config:"binary-threshold-difference-voting-weight"`
+	VotingScoreThreshold                                    float64               `config:"voting-score-threshold"`
+	ClassificationWindowSize                                int                   `config:"classification-window-size"`
+	ClassificationMaxGap                                    int                   `config:"classification-max-gap"`
+	MovingMeanResolution                                    int32                 `config:"moving-mean-resolution"`
+	StatisticsBackend                                       StatisticsBackend     `config:"statistics-backend"`
+	ExponentialHalfLife                                     float64               `config:"exponential-half-life"`
+	ExportCsvReport                                         bool                  `config:"export-csv-report"`
+	ExportJsonReport                                        bool                  `config:"export-json-report"`
+	ExportChartReport                                       bool                  `config:"export-chart-report"`
+	ExportConfusionMatrix                                   bool                  `config:"export-confusion-matrix"`
+	ConfusionMatrixActualDetectionsExpression               string                `config:"confusion-matrix-actual-detections-expression"`
+	SkipFramesExport                                        bool                  `config:"skip-frames-export"`
+	Denoise                                                 DenoiseAlgorithm      `config:"denoise"`
+	FrameScalingFactor                                      float64               `config:"scaling-factor"`
+	ImportPreanalyzed                                       bool                  `config:"import-preanalyzed"`
+	StrictExplicitThreshold                                 bool                  `config:"strict-explicit-threshold"`
+	DetectionBoundsExpression                               string                `config:"detection-bounds-expression"`
+	AnalysisStartExpression                                 string                `config:"start"`
+	AnalysisEndExpression                                   string                `config:"end"`
+	AnalysisWorkers                                         int                   `config:"workers"`
+	CheckpointInterval                                      int                   `config:"checkpoint-interval"`
+	AllowIncompleteAnalysis                                 bool                  `config:"allow-incomplete-analysis"`
+	CacheDirectoryPath                                      string                `config:"cache-directory"`
+	VideoStreamIndex                                        int                   `config:"video-stream-index"`
+	ImageSequenceFps                                        float64               `config:"image-sequence-fps"`
+	ScaleAlgorithm                                          ScaleAlgorithm        `config:"scaling-algorithm"`
+	StrikeEventGapTolerance                                 int                   `config:"strike-event-gap-tolerance"`
+	ExportStrikeEventClips                                  bool                  `config:"export-strike-event-clips"`
+	StrikeEventClipPreRoll                                  float64               `config:"strike-event-clip-pre-roll"`
+	StrikeEventClipPostRoll                                 float64               `config:"strike-event-clip-post-roll"`
+	ExportCompositeImage                                    bool                  `config:"export-composite-image"`
+	ExportStrikeEventCompositeImages                        bool                  `config:"export-strike-event-composite-images"`
+	CompositeBlendMode                                      CompositeBlendMode    `config:"composite-blend-mode"`
 }
 
 // Return a boolean value representing if the detector options are valid. If any validation errors occured
@@ -40,6 +78,58 @@ func (options *DetectorOptions) AreValid() (bool, string) {
 		return false, "the frame binary threshold difference detection threshold must be between zero and one"
 	}
 
+	if !IsValidAutoThresholdStrategy(options.AutoThresholdStrategy) {
+		return false, "the specified auto threshold strategy is invalid"
+	}
+
+	if options.AutoThresholdPercentile <= 0.0 || options.AutoThresholdPercentile > 100.0 {
+		return false, "the auto threshold percentile must be greater than zero and not greater than one hundred"
+	}
+
+	if options.AutoThresholdOtsuBins < 2 {
+		return false, "the auto threshold otsu histogram bins count must be at least two"
+	}
+
+	if options.BrightnessAutoThresholdMeanCoefficient < 0.0 || options.ColorDifferenceAutoThresholdMeanCoefficient < 0.0 || options.BinaryThresholdDifferenceAutoThresholdMeanCoefficient < 0.0 {
+		return false, "the auto threshold mean coefficients can not be negative"
+	}
+
+	if options.BrightnessAutoThresholdStdDevCoefficient < 0.0 || options.ColorDifferenceAutoThresholdStdDevCoefficient < 0.0 || options.BinaryThresholdDifferenceAutoThresholdStdDevCoefficient < 0.0 {
+		return false, "the auto threshold standard deviation coefficients can not be negative"
+	}
+
+	if !IsValidDetectionStrategy(options.DetectionStrategy) {
+		return false, "the specified detection strategy is invalid"
+	}
+
+	if options.BrightnessDetectionZScore < 0.0 || options.ColorDifferenceDetectionZScore < 0.0 || options.BinaryThresholdDifferenceDetectionZScore < 0.0 {
+		return false, "the z-score detection thresholds can not be negative"
+	}
+
+	if !IsValidVotingRule(options.VotingRule) {
+		return false, "the specified voting rule is invalid"
+	}
+
+	if options.VotingRuleK < 1 || options.VotingRuleK > 3 {
+		return false, "the voting rule k must be between one and three"
+	}
+
+	if options.BrightnessVotingWeight < 0.0 || options.ColorDifferenceVotingWeight < 0.0 || options.BinaryThresholdDifferenceVotingWeight < 0.0 {
+		return false, "the voting weights can not be negative"
+	}
+
+	if options.VotingScoreThreshold <= 0.0 {
+		return false, "the voting score threshold must be greater than zero"
+	}
+
+	if options.ClassificationWindowSize < 2 {
+		return false, "the classification window size must be at least two"
+	}
+
+	if options.ClassificationMaxGap < 0 || options.ClassificationMaxGap > options.ClassificationWindowSize-2 {
+		return false, "the classification max gap must be between zero and the classification window size minus two"
+	}
+
 	if options.FrameScalingFactor < 0.0 || options.FrameScalingFactor > 1.0 {
 		return false, "the scaling factor must be between zero and one"
 	}
@@ -56,6 +146,18 @@ func (options *DetectorOptions) AreValid() (bool, string) {
 		return false, "the moving mean/stddev resolution can not be negative"
 	}
 
+	if !IsValidStatisticsBackend(options.StatisticsBackend) {
+		return false, "the specified statistics backend is invalid"
+	}
+
+	if options.ExponentialHalfLife <= 0.0 {
+		return false, "the exponential statistics half-life must be greater than zero"
+	}
+
+	if options.StatisticsBackend == ExponentialStatistics && options.DetectionStrategy == AboveMovingMedianAllWeights {
+		return false, "the moving median detection strategy is not supported by the exponential statistics backend"
+	}
+
 	if !IsValidDenoiseAlgorithm(options.Denoise) {
 		return false, "the specified denoise algorithm is invalid"
 	}
@@ -64,55 +166,178 @@ func (options *DetectorOptions) AreValid() (bool, string) {
 		return false, "the detection bounds expression has a invalid format"
 	}
 
+	if len(options.AnalysisStartExpression) != 0 && !utils.IsPositionExpressionValid(options.AnalysisStartExpression) {
+		return false, "the analysis start position expression has a invalid format"
+	}
+
+	if len(options.AnalysisEndExpression) != 0 && !utils.IsPositionExpressionValid(options.AnalysisEndExpression) {
+		return false, "the analysis end position expression has a invalid format"
+	}
+
+	// NOTE: Only the positions of the same kind can be compared without the video frame rate. The frame position and the time
+	// position are compared by the analyzer after the conversion to frames.
+	if len(options.AnalysisStartExpression) != 0 && len(options.AnalysisEndExpression) != 0 {
+		start, startFrame, _ := utils.ParsePositionExpression(options.AnalysisStartExpression)
+		end, endFrame, _ := utils.ParsePositionExpression(options.AnalysisEndExpression)
+
+		if startFrame == endFrame && start > end {
+			return false, "the analysis start position must not be after the analysis end position"
+		}
+	}
+
+	if options.AnalysisWorkers < 1 {
+		return false, "the analysis workers count must be greater than zero"
+	}
+
+	if options.CheckpointInterval < 0 {
+		return false, "the analysis checkpoint interval can not be negative"
+	}
+
+	if options.VideoStreamIndex < -1 {
+		return false, "the video stream index must not be negative or must be -1 to require a single video stream"
+	}
+
+	if options.ImageSequenceFps < 0 {
+		return false, "the image sequence frame rate can not be negative"
+	}
+
 	if !IsValidScaleAlgorithm(options.ScaleAlgorithm) {
 		return false, "the specified scale algorithm is invalid"
 	}
 
+	if options.StrikeEventGapTolerance < 0 {
+		return false, "the strike event gap tolerance can not be negative"
+	}
+
+	if options.StrikeEventClipPreRoll < 0.0 || options.StrikeEventClipPostRoll < 0.0 {
+		return false, "the strike event clip pre-roll and post-roll can not be negative"
+	}
+
+	if !IsValidCompositeBlendMode(options.CompositeBlendMode) {
+		return false, "the specified composite blend mode is invalid"
+	}
+
 	return true, ""
 }
 
 // Create a copy of the detector option
 func (options *DetectorOptions) Clone() DetectorOptions {
 	return DetectorOptions{
-		AutoThresholds:                              options.AutoThresholds,
-		BrightnessDetectionThreshold:                options.BrightnessDetectionThreshold,
-		ColorDifferenceDetectionThreshold:           options.ColorDifferenceDetectionThreshold,
-		BinaryThresholdDifferenceDetectionThreshold: options.BinaryThresholdDifferenceDetectionThreshold,
-		MovingMeanResolution:                        options.MovingMeanResolution,
-		ExportCsvReport:                             options.ExportCsvReport,
-		ExportJsonReport:                            options.ExportJsonReport,
-		ExportChartReport:                           options.ExportChartReport,
-		ExportConfusionMatrix:                       options.ExportConfusionMatrix,
-		ConfusionMatrixActualDetectionsExpression:   options.ConfusionMatrixActualDetectionsExpression,
-		SkipFramesExport:                            options.SkipFramesExport,
-		Denoise:                                     options.Denoise,
-		FrameScalingFactor:                          options.FrameScalingFactor,
-		ImportPreanalyzed:                           options.ImportPreanalyzed,
-		StrictExplicitThreshold:                     options.StrictExplicitThreshold,
-		DetectionBoundsExpression:                   options.DetectionBoundsExpression,
-		ScaleAlgorithm:                              options.ScaleAlgorithm,
+		AutoThresholds:                                          options.AutoThresholds,
+		AutoThresholdStrategy:                                   options.AutoThresholdStrategy,
+		AutoThresholdPercentile:                                 options.AutoThresholdPercentile,
+		AutoThresholdOtsuBins:                                   options.AutoThresholdOtsuBins,
+		BrightnessAutoThresholdMeanCoefficient:                  options.BrightnessAutoThresholdMeanCoefficient,
+		BrightnessAutoThresholdStdDevCoefficient:                options.BrightnessAutoThresholdStdDevCoefficient,
+		ColorDifferenceAutoThresholdMeanCoefficient:             options.ColorDifferenceAutoThresholdMeanCoefficient,
+		ColorDifferenceAutoThresholdStdDevCoefficient:           options.ColorDifferenceAutoThresholdStdDevCoefficient,
+		BinaryThresholdDifferenceAutoThresholdMeanCoefficient:   options.BinaryThresholdDifferenceAutoThresholdMeanCoefficient,
+		BinaryThresholdDifferenceAutoThresholdStdDevCoefficient: options.BinaryThresholdDifferenceAutoThresholdStdDevCoefficient,
+		BrightnessDetectionThreshold:                            options.BrightnessDetectionThreshold,
+		ColorDifferenceDetectionThreshold:                       options.ColorDifferenceDetectionThreshold,
+		BinaryThresholdDifferenceDetectionThreshold:             options.BinaryThresholdDifferenceDetectionThreshold,
+		DetectionStrategy:                                       options.DetectionStrategy,
+		BrightnessDetectionZScore:                               options.BrightnessDetectionZScore,
+		ColorDifferenceDetectionZScore:                          options.ColorDifferenceDetectionZScore,
+		BinaryThresholdDifferenceDetectionZScore:                options.BinaryThresholdDifferenceDetectionZScore,
+		VotingRule:                                              options.VotingRule,
+		VotingRuleK:                                             options.VotingRuleK,
+		BrightnessVotingWeight:                                  options.BrightnessVotingWeight,
+		ColorDifferenceVotingWeight:                             options.ColorDifferenceVotingWeight,
+		BinaryThresholdDifferenceVotingWeight:                   options.BinaryThresholdDifferenceVotingWeight,
+		VotingScoreThreshold:                                    options.VotingScoreThreshold,
+		ClassificationWindowSize:                                options.ClassificationWindowSize,
+		ClassificationMaxGap:                                    options.ClassificationMaxGap,
+		MovingMeanResolution:                                    options.MovingMeanResolution,
+		StatisticsBackend:                                       options.StatisticsBackend,
+		ExponentialHalfLife:                                     options.ExponentialHalfLife,
+		ExportCsvReport:                                         options.ExportCsvReport,
+		ExportJsonReport:                                        options.ExportJsonReport,
+		ExportChartReport:                                       options.ExportChartReport,
+		ExportConfusionMatrix:                                   options.ExportConfusionMatrix,
+		ConfusionMatrixActualDetectionsExpression:               options.ConfusionMatrixActualDetectionsExpression,
+		SkipFramesExport:                                        options.SkipFramesExport,
+		Denoise:                                                 options.Denoise,
+		FrameScalingFactor:                                      options.FrameScalingFactor,
+		ImportPreanalyzed:                                       options.ImportPreanalyzed,
+		StrictExplicitThreshold:                                 options.StrictExplicitThreshold,
+		DetectionBoundsExpression:                               options.DetectionBoundsExpression,
+		AnalysisStartExpression:                                 options.AnalysisStartExpression,
+		AnalysisEndExpression:                                   options.AnalysisEndExpression,
+		AnalysisWorkers:                                         options.AnalysisWorkers,
+		CheckpointInterval:                                      options.CheckpointInterval,
+		AllowIncompleteAnalysis:                                 options.AllowIncompleteAnalysis,
+		CacheDirectoryPath:                                      options.CacheDirectoryPath,
+		VideoStreamIndex:                                        options.VideoStreamIndex,
+		ImageSequenceFps:                                        options.ImageSequenceFps,
+		ScaleAlgorithm:                                          options.ScaleAlgorithm,
+		StrikeEventGapTolerance:                                 options.StrikeEventGapTolerance,
+		ExportStrikeEventClips:                                  options.ExportStrikeEventClips,
+		StrikeEventClipPreRoll:                                  options.StrikeEventClipPreRoll,
+		StrikeEventClipPostRoll:                                 options.StrikeEventClipPostRoll,
+		ExportCompositeImage:                                    options.ExportCompositeImage,
+		ExportStrikeEventCompositeImages:                        options.ExportStrikeEventCompositeImages,
+		CompositeBlendMode:                                      options.CompositeBlendMode,
 	}
 }
 
 // Return the default detector options.
 func GetDefaultDetectorOptions() DetectorOptions {
 	return DetectorOptions{
-		AutoThresholds:                              false,
-		BrightnessDetectionThreshold:                0.0,
-		ColorDifferenceDetectionThreshold:           0.0,
-		BinaryThresholdDifferenceDetectionThreshold: 0.0,
-		MovingMeanResolution:                        50,
-		ExportCsvReport:                             false,
-		ExportJsonReport:                            false,
-		ExportChartReport:                           false,
-		ExportConfusionMatrix:                       false,
-		ConfusionMatrixActualDetectionsExpression:   "",
-		SkipFramesExport:                            false,
-		Denoise:                                     NoDenoise,
-		FrameScalingFactor:                          0.5,
-		ImportPreanalyzed:                           false,
-		StrictExplicitThreshold:                     true,
-		DetectionBoundsExpression:                   "",
-		ScaleAlgorithm:                              Default,
+		AutoThresholds:                                          false,
+		AutoThresholdStrategy:                                   AboveMeanOfDeviations,
+		AutoThresholdPercentile:                                 99.5,
+		AutoThresholdOtsuBins:                                   256,
+		BrightnessAutoThresholdMeanCoefficient:                  1.0,
+		BrightnessAutoThresholdStdDevCoefficient:                0.0,
+		ColorDifferenceAutoThresholdMeanCoefficient:             1.0,
+		ColorDifferenceAutoThresholdStdDevCoefficient:           0.0,
+		BinaryThresholdDifferenceAutoThresholdMeanCoefficient:   0.25,
+		BinaryThresholdDifferenceAutoThresholdStdDevCoefficient: 0.15,
+		BrightnessDetectionThreshold:                            0.0,
+		ColorDifferenceDetectionThreshold:                       0.0,
+		BinaryThresholdDifferenceDetectionThreshold:             0.0,
+		DetectionStrategy:                                       AboveMovingMeanAllWeights,
+		BrightnessDetectionZScore:                               3.0,
+		ColorDifferenceDetectionZScore:                          3.0,
+		BinaryThresholdDifferenceDetectionZScore:                3.0,
+		VotingRule:                                              AllOfVoting,
+		VotingRuleK:                                             2,
+		BrightnessVotingWeight:                                  1.0,
+		ColorDifferenceVotingWeight:                             1.0,
+		BinaryThresholdDifferenceVotingWeight:                   1.0,
+		VotingScoreThreshold:                                    2.0,
+		ClassificationWindowSize:                                4,
+		ClassificationMaxGap:                                    2,
+		MovingMeanResolution:                                    50,
+		StatisticsBackend:                                       MovingWindowStatistics,
+		ExponentialHalfLife:                                     5.0,
+		ExportCsvReport:                                         false,
+		ExportJsonReport:                                        false,
+		ExportChartReport:                                       false,
+		ExportConfusionMatrix:                                   false,
+		ConfusionMatrixActualDetectionsExpression:               "",
+		SkipFramesExport:                                        false,
+		Denoise:                                                 NoDenoise,
+		FrameScalingFactor:                                      0.5,
+		ImportPreanalyzed:                                       false,
+		StrictExplicitThreshold:                                 true,
+		DetectionBoundsExpression:                               "",
+		AnalysisStartExpression:                                 "",
+		AnalysisEndExpression:                                   "",
+		AnalysisWorkers:                                         1,
+		CheckpointInterval:                                      2500,
+		AllowIncompleteAnalysis:                                 false,
+		CacheDirectoryPath:                                      "",
+		VideoStreamIndex:                                        -1,
+		ImageSequenceFps:                                        0,
+		ScaleAlgorithm:                                          Default,
+		StrikeEventGapTolerance:                                 2,
+		ExportStrikeEventClips:                                  false,
+		StrikeEventClipPreRoll:                                  1.0,
+		StrikeEventClipPostRoll:                                 1.0,
+		ExportCompositeImage:                                    false,
+		ExportStrikeEventCompositeImages:                        false,
+		CompositeBlendMode:                                      MaxBlend,
 	}
 }
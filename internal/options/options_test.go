@@ -65,3 +65,237 @@ func TestShouldNotValidateInvalidFrameScalingFactor(t *testing.T) {
 		assert.NotEmpty(t, msg)
 	}
 }
+
+func TestShouldNotValidateInvalidStrikeEventGapTolerance(t *testing.T) {
+	options := GetDefaultDetectorOptions()
+	options.StrikeEventGapTolerance = -1
+
+	valid, msg := options.AreValid()
+	assert.False(t, valid)
+	assert.NotEmpty(t, msg)
+}
+
+func TestShouldNotValidateInvalidStrikeEventClipRolls(t *testing.T) {
+	cases := [][2]float64{{-1.0, 0.0}, {0.0, -1.0}, {-1.0, -1.0}}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.StrikeEventClipPreRoll = value[0]
+		options.StrikeEventClipPostRoll = value[1]
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidDetectionZScores(t *testing.T) {
+	cases := [][3]float64{{-1.0, 3.0, 3.0}, {3.0, -1.0, 3.0}, {3.0, 3.0, -1.0}}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.BrightnessDetectionZScore = value[0]
+		options.ColorDifferenceDetectionZScore = value[1]
+		options.BinaryThresholdDifferenceDetectionZScore = value[2]
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidVotingRuleK(t *testing.T) {
+	cases := []int{0, 4}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.VotingRuleK = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidVotingWeights(t *testing.T) {
+	cases := [][4]float64{{-1.0, 1.0, 1.0, 2.0}, {1.0, -1.0, 1.0, 2.0}, {1.0, 1.0, -1.0, 2.0}, {1.0, 1.0, 1.0, -1.0}}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.BrightnessVotingWeight = value[0]
+		options.ColorDifferenceVotingWeight = value[1]
+		options.BinaryThresholdDifferenceVotingWeight = value[2]
+		options.VotingScoreThreshold = value[3]
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidVotingScoreThreshold(t *testing.T) {
+	cases := []float64{0.0, -1.0}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.VotingScoreThreshold = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidClassificationWindow(t *testing.T) {
+	cases := [][2]int{{1, 0}, {4, -1}, {4, 3}}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.ClassificationWindowSize = value[0]
+		options.ClassificationMaxGap = value[1]
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidExponentialHalfLife(t *testing.T) {
+	cases := []float64{0.0, -1.0}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.ExponentialHalfLife = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateMovingMedianStrategyWithExponentialBackend(t *testing.T) {
+	options := GetDefaultDetectorOptions()
+	options.StatisticsBackend = ExponentialStatistics
+	options.DetectionStrategy = AboveMovingMedianAllWeights
+
+	valid, msg := options.AreValid()
+	assert.False(t, valid)
+	assert.NotEmpty(t, msg)
+
+	options.DetectionStrategy = AboveMovingMeanAllWeights
+
+	valid, _ = options.AreValid()
+	assert.True(t, valid)
+}
+
+func TestShouldNotValidateInvalidAnalysisRange(t *testing.T) {
+	cases := [][2]string{{"abc", ""}, {"", "-1"}, {"100", "50"}, {"00:01:00", "30s"}}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.AnalysisStartExpression = value[0]
+		options.AnalysisEndExpression = value[1]
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidVideoStreamIndex(t *testing.T) {
+	cases := []int{-2, -10}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.VideoStreamIndex = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidAnalysisWorkers(t *testing.T) {
+	cases := []int{0, -1}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.AnalysisWorkers = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidImageSequenceFps(t *testing.T) {
+	cases := []float64{-1, -0.5, -25}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.ImageSequenceFps = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidCheckpointInterval(t *testing.T) {
+	cases := []int{-1, -100}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.CheckpointInterval = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidAutoThresholdPercentile(t *testing.T) {
+	cases := []float64{0, -1, 100.1}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.AutoThresholdPercentile = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateInvalidAutoThresholdOtsuBins(t *testing.T) {
+	cases := []int{1, 0, -1}
+
+	for _, value := range cases {
+		options := GetDefaultDetectorOptions()
+		options.AutoThresholdOtsuBins = value
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
+
+func TestShouldNotValidateNegativeAutoThresholdCoefficients(t *testing.T) {
+	cases := []func(*DetectorOptions){
+		func(o *DetectorOptions) { o.BrightnessAutoThresholdMeanCoefficient = -0.1 },
+		func(o *DetectorOptions) { o.BrightnessAutoThresholdStdDevCoefficient = -0.1 },
+		func(o *DetectorOptions) { o.ColorDifferenceAutoThresholdMeanCoefficient = -0.1 },
+		func(o *DetectorOptions) { o.ColorDifferenceAutoThresholdStdDevCoefficient = -0.1 },
+		func(o *DetectorOptions) { o.BinaryThresholdDifferenceAutoThresholdMeanCoefficient = -0.1 },
+		func(o *DetectorOptions) { o.BinaryThresholdDifferenceAutoThresholdStdDevCoefficient = -0.1 },
+	}
+
+	for _, apply := range cases {
+		options := GetDefaultDetectorOptions()
+		apply(&options)
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
@@ -4,18 +4,32 @@ import "github.com/Krzysztofz01/video-lightning-detector/internal/utils"
 
 // Structure representing the options for the stream detector.
 type StreamDetectorOptions struct {
-	BrightnessDetectionThreshold                float64
-	ColorDifferenceDetectionThreshold           float64
-	BinaryThresholdDifferenceDetectionThreshold float64
-	MovingMeanResolution                        int32
-	Denoise                                     DenoiseAlgorithm
-	FrameScalingFactor                          float64
-	StrictExplicitThreshold                     bool
-	DetectionBoundsExpression                   string
-	ScaleAlgorithm                              ScaleAlgorithm
-	FrameDetectionPlotResolution                int
-	FrameDetectionPlotThreshold                 float64
-	DiagnosticMode                              bool
+	BrightnessDetectionThreshold                float64           `config:"brightness-threshold"`
+	ColorDifferenceDetectionThreshold           float64           `config:"color-difference-threshold"`
+	BinaryThresholdDifferenceDetectionThreshold float64           `config:"binary-threshold-difference-threshold"`
+	DetectionStrategy                           DetectionStrategy `config:"detection-strategy"`
+	BrightnessDetectionZScore                   float64           `config:"brightness-z-score"`
+	ColorDifferenceDetectionZScore              float64           `config:"color-difference-z-score"`
+	BinaryThresholdDifferenceDetectionZScore    float64           `config:"binary-threshold-difference-z-score"`
+	VotingRule                                  VotingRule        `config:"voting-rule"`
+	VotingRuleK                                 int               `config:"voting-rule-k"`
+	BrightnessVotingWeight                      float64           `config:"brightness-voting-weight"`
+	ColorDifferenceVotingWeight                 float64           `config:"color-difference-voting-weight"`
+	BinaryThresholdDifferenceVotingWeight       float64           `config:"binary-threshold-difference-voting-weight"`
+	VotingScoreThreshold                        float64           `config:"voting-score-threshold"`
+	ClassificationWindowSize                    int               `config:"classification-window-size"`
+	ClassificationMaxGap                        int               `config:"classification-max-gap"`
+	MovingMeanResolution                        int32             `config:"moving-mean-resolution"`
+	StatisticsBackend                           StatisticsBackend `config:"statistics-backend"`
+	ExponentialHalfLife                         float64           `config:"exponential-half-life"`
+	Denoise                                     DenoiseAlgorithm  `config:"denoise"`
+	FrameScalingFactor                          float64           `config:"scaling-factor"`
+	StrictExplicitThreshold                     bool              `config:"strict-explicit-threshold"`
+	DetectionBoundsExpression                   string            `config:"detection-bounds-expression"`
+	ScaleAlgorithm                              ScaleAlgorithm    `config:"scaling-algorithm"`
+	FrameDetectionPlotResolution                int               `config:"frame-detection-plot-resolution"`
+	FrameDetectionPlotThreshold                 float64           `config:"frame-detection-plot-threshold"`
+	DiagnosticMode                              bool              `config:"diagnostic"`
 }
 
 // Return a boolean value representing if the stream detector options are valid. If any validation errors occured
@@ -33,6 +47,38 @@ func (options *StreamDetectorOptions) AreValid() (bool, string) {
 		return false, "the frame binary threshold difference detection threshold must be between zero and one"
 	}
 
+	if !IsValidDetectionStrategy(options.DetectionStrategy) {
+		return false, "the specified detection strategy is invalid"
+	}
+
+	if options.BrightnessDetectionZScore < 0.0 || options.ColorDifferenceDetectionZScore < 0.0 || options.BinaryThresholdDifferenceDetectionZScore < 0.0 {
+		return false, "the z-score detection thresholds can not be negative"
+	}
+
+	if !IsValidVotingRule(options.VotingRule) {
+		return false, "the specified voting rule is invalid"
+	}
+
+	if options.VotingRuleK < 1 || options.VotingRuleK > 3 {
+		return false, "the voting rule k must be between one and three"
+	}
+
+	if options.BrightnessVotingWeight < 0.0 || options.ColorDifferenceVotingWeight < 0.0 || options.BinaryThresholdDifferenceVotingWeight < 0.0 {
+		return false, "the voting weights can not be negative"
+	}
+
+	if options.VotingScoreThreshold <= 0.0 {
+		return false, "the voting score threshold must be greater than zero"
+	}
+
+	if options.ClassificationWindowSize < 2 {
+		return false, "the classification window size must be at least two"
+	}
+
+	if options.ClassificationMaxGap < 0 || options.ClassificationMaxGap > options.ClassificationWindowSize-2 {
+		return false, "the classification max gap must be between zero and the classification window size minus two"
+	}
+
 	if options.FrameScalingFactor < 0.0 || options.FrameScalingFactor > 1.0 {
 		return false, "the scaling factor must be between zero and one"
 	}
@@ -41,6 +87,18 @@ func (options *StreamDetectorOptions) AreValid() (bool, string) {
 		return false, "the moving mean/stddev resolution can not be negative"
 	}
 
+	if !IsValidStatisticsBackend(options.StatisticsBackend) {
+		return false, "the specified statistics backend is invalid"
+	}
+
+	if options.ExponentialHalfLife <= 0.0 {
+		return false, "the exponential statistics half-life must be greater than zero"
+	}
+
+	if options.StatisticsBackend == ExponentialStatistics && options.DetectionStrategy == AboveMovingMedianAllWeights {
+		return false, "the moving median detection strategy is not supported by the exponential statistics backend"
+	}
+
 	if !IsValidDenoiseAlgorithm(options.Denoise) {
 		return false, "the specified denoise algorithm is invalid"
 	}
@@ -70,7 +128,21 @@ func (options *StreamDetectorOptions) Clone() StreamDetectorOptions {
 		BrightnessDetectionThreshold:                options.BrightnessDetectionThreshold,
 		ColorDifferenceDetectionThreshold:           options.ColorDifferenceDetectionThreshold,
 		BinaryThresholdDifferenceDetectionThreshold: options.BinaryThresholdDifferenceDetectionThreshold,
+		DetectionStrategy:                           options.DetectionStrategy,
+		BrightnessDetectionZScore:                   options.BrightnessDetectionZScore,
+		ColorDifferenceDetectionZScore:              options.ColorDifferenceDetectionZScore,
+		BinaryThresholdDifferenceDetectionZScore:    options.BinaryThresholdDifferenceDetectionZScore,
+		VotingRule:                                  options.VotingRule,
+		VotingRuleK:                                 options.VotingRuleK,
+		BrightnessVotingWeight:                      options.BrightnessVotingWeight,
+		ColorDifferenceVotingWeight:                 options.ColorDifferenceVotingWeight,
+		BinaryThresholdDifferenceVotingWeight:       options.BinaryThresholdDifferenceVotingWeight,
+		VotingScoreThreshold:                        options.VotingScoreThreshold,
+		ClassificationWindowSize:                    options.ClassificationWindowSize,
+		ClassificationMaxGap:                        options.ClassificationMaxGap,
 		MovingMeanResolution:                        options.MovingMeanResolution,
+		StatisticsBackend:                           options.StatisticsBackend,
+		ExponentialHalfLife:                         options.ExponentialHalfLife,
 		Denoise:                                     options.Denoise,
 		FrameScalingFactor:                          options.FrameScalingFactor,
 		StrictExplicitThreshold:                     options.StrictExplicitThreshold,
@@ -88,7 +160,21 @@ func GetDefaultStreamDetectorOptions() StreamDetectorOptions {
 		BrightnessDetectionThreshold:                0.0,
 		ColorDifferenceDetectionThreshold:           0.0,
 		BinaryThresholdDifferenceDetectionThreshold: 0.0,
+		DetectionStrategy:                           AboveMovingMeanAllWeights,
+		BrightnessDetectionZScore:                   3.0,
+		ColorDifferenceDetectionZScore:              3.0,
+		BinaryThresholdDifferenceDetectionZScore:    3.0,
+		VotingRule:                                  AllOfVoting,
+		VotingRuleK:                                 2,
+		BrightnessVotingWeight:                      1.0,
+		ColorDifferenceVotingWeight:                 1.0,
+		BinaryThresholdDifferenceVotingWeight:       1.0,
+		VotingScoreThreshold:                        2.0,
+		ClassificationWindowSize:                    4,
+		ClassificationMaxGap:                        2,
 		MovingMeanResolution:                        50,
+		StatisticsBackend:                           MovingWindowStatistics,
+		ExponentialHalfLife:                         5.0,
 		Denoise:                                     NoDenoise,
 		FrameScalingFactor:                          0.5,
 		StrictExplicitThreshold:                     true,
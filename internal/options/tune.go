@@ -0,0 +1,101 @@
+package options
+
+import (
+	"slices"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// Structure representing the options for the tuner searching the detector options maximizing the metric of the detection
+// compared to the ground-truth frames.
+type TuneOptions struct {
+	GroundTruthExpression string
+	Metric                TuneMetric
+	MinPrecision          float64
+	ThresholdSteps        int
+	MovingMeanResolutions []int
+	DetectionStrategies   []DetectionStrategy
+	ProfileName           string
+}
+
+// Return a boolean value representing if the tune options are valid. If any validation errors occured
+// a message will be stored in the string return value.
+func (options *TuneOptions) AreValid() (bool, string) {
+	if len(options.GroundTruthExpression) == 0 {
+		return false, "the ground-truth frames expression must be specified"
+	}
+
+	if !utils.IsRangeExpressionValid(options.GroundTruthExpression) {
+		return false, "the ground-truth frames expression has a invalid format"
+	}
+
+	if !IsValidTuneMetric(options.Metric) {
+		return false, "the specified tune metric is invalid"
+	}
+
+	if options.MinPrecision < 0.0 || options.MinPrecision > 1.0 {
+		return false, "the minimal precision must be between zero and one"
+	}
+
+	if options.ThresholdSteps < 2 {
+		return false, "the threshold steps count must be at least two"
+	}
+
+	if len(options.MovingMeanResolutions) == 0 {
+		return false, "at least one moving mean resolution must be specified"
+	}
+
+	for _, resolution := range options.MovingMeanResolutions {
+		if resolution < 1 {
+			return false, "the moving mean resolutions must be greater than zero"
+		}
+	}
+
+	if len(options.DetectionStrategies) == 0 {
+		return false, "at least one detection strategy must be specified"
+	}
+
+	for _, strategy := range options.DetectionStrategies {
+		if !IsValidDetectionStrategy(strategy) {
+			return false, "the specified detection strategies are invalid"
+		}
+	}
+
+	if len(options.ProfileName) == 0 {
+		return false, "the profile name must be specified"
+	}
+
+	return true, ""
+}
+
+// Create a copy of the tune options
+func (options *TuneOptions) Clone() TuneOptions {
+	return TuneOptions{
+		GroundTruthExpression: options.GroundTruthExpression,
+		Metric:                options.Metric,
+		MinPrecision:          options.MinPrecision,
+		ThresholdSteps:        options.ThresholdSteps,
+		MovingMeanResolutions: slices.Clone(options.MovingMeanResolutions),
+		DetectionStrategies:   slices.Clone(options.DetectionStrategies),
+		ProfileName:           options.ProfileName,
+	}
+}
+
+// Return the default tune options.
+func GetDefaultTuneOptions() TuneOptions {
+	return TuneOptions{
+		GroundTruthExpression: "",
+		Metric:                FScoreMetric,
+		MinPrecision:          0.9,
+		ThresholdSteps:        20,
+		MovingMeanResolutions: []int{25, 50, 100, 200},
+		DetectionStrategies: []DetectionStrategy{
+			AboveMovingMeanAllWeights,
+			AboveGlobalMeanAllWeights,
+			AboveZeroAllWeights,
+			AboveMovingZScoreAllWeights,
+			AboveMovingMedianAllWeights,
+		},
+		ProfileName: "tuned",
+	}
+}
@@ -0,0 +1,42 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldValidateTuneOptionsWithGroundTruth(t *testing.T) {
+	options := GetDefaultTuneOptions()
+	options.GroundTruthExpression = "4,5,8-10"
+
+	valid, msg := options.AreValid()
+	assert.True(t, valid)
+	assert.Empty(t, msg)
+}
+
+func TestShouldNotValidateInvalidTuneOptions(t *testing.T) {
+	cases := []func(*TuneOptions){
+		func(o *TuneOptions) { o.GroundTruthExpression = "" },
+		func(o *TuneOptions) { o.GroundTruthExpression = "4-" },
+		func(o *TuneOptions) { o.Metric = -1 },
+		func(o *TuneOptions) { o.MinPrecision = 1.1 },
+		func(o *TuneOptions) { o.MinPrecision = -0.1 },
+		func(o *TuneOptions) { o.ThresholdSteps = 1 },
+		func(o *TuneOptions) { o.MovingMeanResolutions = []int{} },
+		func(o *TuneOptions) { o.MovingMeanResolutions = []int{50, 0} },
+		func(o *TuneOptions) { o.DetectionStrategies = []DetectionStrategy{} },
+		func(o *TuneOptions) { o.DetectionStrategies = []DetectionStrategy{-1} },
+		func(o *TuneOptions) { o.ProfileName = "" },
+	}
+
+	for _, apply := range cases {
+		options := GetDefaultTuneOptions()
+		options.GroundTruthExpression = "4,5,8-10"
+		apply(&options)
+
+		valid, msg := options.AreValid()
+		assert.False(t, valid)
+		assert.NotEmpty(t, msg)
+	}
+}
@@ -125,6 +125,346 @@ func (a *ScaleAlgorithm) Type() string {
 	return "scalealgorithm"
 }
 
+type CompositeBlendMode int
+
+const (
+	MaxBlend CompositeBlendMode = iota
+	LightenOverMedianBlend
+	AverageBlend
+)
+
+func IsValidCompositeBlendMode(m CompositeBlendMode) bool {
+	switch m {
+	case MaxBlend, LightenOverMedianBlend, AverageBlend:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetCompositeBlendModeValues() []string {
+	values := make([]string, 0, len(compositeBlendModeNames))
+	for value := range compositeBlendModeNames {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+var compositeBlendModeNames = map[string]CompositeBlendMode{
+	"max":     MaxBlend,
+	"lighten": LightenOverMedianBlend,
+	"average": AverageBlend,
+}
+
+func (m *CompositeBlendMode) String() string {
+	for name, mode := range compositeBlendModeNames {
+		if mode == *m {
+			return name
+		}
+	}
+
+	panic("options: invalid unknown composite blend mode")
+}
+
+func (m *CompositeBlendMode) Set(s string) error {
+	if mode, ok := compositeBlendModeNames[strings.ToLower(s)]; !ok {
+		return fmt.Errorf("options: invalid unknown composite blend mode name")
+	} else {
+		*m = mode
+	}
+
+	return nil
+}
+
+func (m *CompositeBlendMode) Type() string {
+	return "compositeblendmode"
+}
+
+type DetectionStrategy int
+
+const (
+	AboveMovingMeanAllWeights DetectionStrategy = iota
+	AboveGlobalMeanAllWeights
+	AboveZeroAllWeights
+	AboveMovingZScoreAllWeights
+	AboveMovingMedianAllWeights
+)
+
+func IsValidDetectionStrategy(s DetectionStrategy) bool {
+	switch s {
+	case AboveMovingMeanAllWeights, AboveGlobalMeanAllWeights, AboveZeroAllWeights, AboveMovingZScoreAllWeights, AboveMovingMedianAllWeights:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetDetectionStrategyValues() []string {
+	values := make([]string, 0, len(detectionStrategyNames))
+	for value := range detectionStrategyNames {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+var detectionStrategyNames = map[string]DetectionStrategy{
+	"moving-mean":   AboveMovingMeanAllWeights,
+	"global-mean":   AboveGlobalMeanAllWeights,
+	"zero":          AboveZeroAllWeights,
+	"z-score":       AboveMovingZScoreAllWeights,
+	"moving-median": AboveMovingMedianAllWeights,
+}
+
+func (s *DetectionStrategy) String() string {
+	for name, strategy := range detectionStrategyNames {
+		if strategy == *s {
+			return name
+		}
+	}
+
+	panic("options: invalid unknown detection strategy")
+}
+
+func (s *DetectionStrategy) Set(v string) error {
+	if strategy, ok := detectionStrategyNames[strings.ToLower(v)]; !ok {
+		return fmt.Errorf("options: invalid unknown detection strategy name")
+	} else {
+		*s = strategy
+	}
+
+	return nil
+}
+
+func (s *DetectionStrategy) Type() string {
+	return "detectionstrategy"
+}
+
+type VotingRule int
+
+const (
+	AllOfVoting VotingRule = iota
+	AnyOfVoting
+	KOfNVoting
+	WeightedVoting
+)
+
+func IsValidVotingRule(r VotingRule) bool {
+	switch r {
+	case AllOfVoting, AnyOfVoting, KOfNVoting, WeightedVoting:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetVotingRuleValues() []string {
+	values := make([]string, 0, len(votingRuleNames))
+	for value := range votingRuleNames {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+var votingRuleNames = map[string]VotingRule{
+	"all":      AllOfVoting,
+	"any":      AnyOfVoting,
+	"k-of-n":   KOfNVoting,
+	"weighted": WeightedVoting,
+}
+
+func (r *VotingRule) String() string {
+	for name, rule := range votingRuleNames {
+		if rule == *r {
+			return name
+		}
+	}
+
+	panic("options: invalid unknown voting rule")
+}
+
+func (r *VotingRule) Set(s string) error {
+	if rule, ok := votingRuleNames[strings.ToLower(s)]; !ok {
+		return fmt.Errorf("options: invalid unknown voting rule name")
+	} else {
+		*r = rule
+	}
+
+	return nil
+}
+
+func (r *VotingRule) Type() string {
+	return "votingrule"
+}
+
+type StatisticsBackend int
+
+const (
+	MovingWindowStatistics StatisticsBackend = iota
+	ExponentialStatistics
+)
+
+func IsValidStatisticsBackend(b StatisticsBackend) bool {
+	switch b {
+	case MovingWindowStatistics, ExponentialStatistics:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetStatisticsBackendValues() []string {
+	values := make([]string, 0, len(statisticsBackendNames))
+	for value := range statisticsBackendNames {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+var statisticsBackendNames = map[string]StatisticsBackend{
+	"moving-window": MovingWindowStatistics,
+	"exponential":   ExponentialStatistics,
+}
+
+func (b *StatisticsBackend) String() string {
+	for name, backend := range statisticsBackendNames {
+		if backend == *b {
+			return name
+		}
+	}
+
+	panic("options: invalid unknown statistics backend")
+}
+
+func (b *StatisticsBackend) Set(s string) error {
+	if backend, ok := statisticsBackendNames[strings.ToLower(s)]; !ok {
+		return fmt.Errorf("options: invalid unknown statistics backend name")
+	} else {
+		*b = backend
+	}
+
+	return nil
+}
+
+func (b *StatisticsBackend) Type() string {
+	return "statisticsbackend"
+}
+
+type AutoThresholdStrategy int
+
+const (
+	AboveMeanOfDeviations AutoThresholdStrategy = iota
+	PercentileOfDeviations
+	OtsuOfDeviations
+)
+
+func IsValidAutoThresholdStrategy(s AutoThresholdStrategy) bool {
+	switch s {
+	case AboveMeanOfDeviations, PercentileOfDeviations, OtsuOfDeviations:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetAutoThresholdStrategyValues() []string {
+	values := make([]string, 0, len(autoThresholdStrategyNames))
+	for value := range autoThresholdStrategyNames {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+var autoThresholdStrategyNames = map[string]AutoThresholdStrategy{
+	"mean-of-deviations": AboveMeanOfDeviations,
+	"percentile":         PercentileOfDeviations,
+	"otsu":               OtsuOfDeviations,
+}
+
+func (s *AutoThresholdStrategy) String() string {
+	for name, strategy := range autoThresholdStrategyNames {
+		if strategy == *s {
+			return name
+		}
+	}
+
+	panic("options: invalid unknown auto threshold strategy")
+}
+
+func (s *AutoThresholdStrategy) Set(v string) error {
+	if strategy, ok := autoThresholdStrategyNames[strings.ToLower(v)]; !ok {
+		return fmt.Errorf("options: invalid unknown auto threshold strategy name")
+	} else {
+		*s = strategy
+	}
+
+	return nil
+}
+
+func (s *AutoThresholdStrategy) Type() string {
+	return "autothresholdstrategy"
+}
+
+type TuneMetric int
+
+const (
+	FScoreMetric TuneMetric = iota
+	MccMetric
+	RecallAtPrecisionMetric
+)
+
+func IsValidTuneMetric(m TuneMetric) bool {
+	switch m {
+	case FScoreMetric, MccMetric, RecallAtPrecisionMetric:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetTuneMetricValues() []string {
+	values := make([]string, 0, len(tuneMetricNames))
+	for value := range tuneMetricNames {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+var tuneMetricNames = map[string]TuneMetric{
+	"f-score":             FScoreMetric,
+	"mcc":                 MccMetric,
+	"recall-at-precision": RecallAtPrecisionMetric,
+}
+
+func (m *TuneMetric) String() string {
+	for name, metric := range tuneMetricNames {
+		if metric == *m {
+			return name
+		}
+	}
+
+	panic("options: invalid unknown tune metric")
+}
+
+func (m *TuneMetric) Set(s string) error {
+	if metric, ok := tuneMetricNames[strings.ToLower(s)]; !ok {
+		return fmt.Errorf("options: invalid unknown tune metric name")
+	} else {
+		*m = metric
+	}
+
+	return nil
+}
+
+func (m *TuneMetric) Type() string {
+	return "tunemetric"
+}
+
 type LogLevel int
 
 const (
@@ -39,3 +39,95 @@ func TestIsValidScaleAlgorithmShouldReturnCorrectBoolean(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	}
 }
+
+func TestIsValidCompositeBlendModeShouldReturnCorrectBoolean(t *testing.T) {
+	cases := map[CompositeBlendMode]bool{
+		MaxBlend:               true,
+		LightenOverMedianBlend: true,
+		AverageBlend:           true,
+		-1:                     false,
+	}
+
+	for mode, expected := range cases {
+		actual := IsValidCompositeBlendMode(mode)
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestIsValidDetectionStrategyShouldReturnCorrectBoolean(t *testing.T) {
+	cases := map[DetectionStrategy]bool{
+		AboveMovingMeanAllWeights:   true,
+		AboveGlobalMeanAllWeights:   true,
+		AboveZeroAllWeights:         true,
+		AboveMovingZScoreAllWeights: true,
+		AboveMovingMedianAllWeights: true,
+		-1:                          false,
+	}
+
+	for strategy, expected := range cases {
+		actual := IsValidDetectionStrategy(strategy)
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestIsValidVotingRuleShouldReturnCorrectBoolean(t *testing.T) {
+	cases := map[VotingRule]bool{
+		AllOfVoting:    true,
+		AnyOfVoting:    true,
+		KOfNVoting:     true,
+		WeightedVoting: true,
+		-1:             false,
+	}
+
+	for rule, expected := range cases {
+		actual := IsValidVotingRule(rule)
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestIsValidStatisticsBackendShouldReturnCorrectBoolean(t *testing.T) {
+	cases := map[StatisticsBackend]bool{
+		MovingWindowStatistics: true,
+		ExponentialStatistics:  true,
+		-1:                     false,
+	}
+
+	for backend, expected := range cases {
+		actual := IsValidStatisticsBackend(backend)
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestIsValidAutoThresholdStrategyShouldReturnCorrectBoolean(t *testing.T) {
+	cases := map[AutoThresholdStrategy]bool{
+		AboveMeanOfDeviations:  true,
+		PercentileOfDeviations: true,
+		OtsuOfDeviations:       true,
+		-1:                     false,
+	}
+
+	for strategy, expected := range cases {
+		actual := IsValidAutoThresholdStrategy(strategy)
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestIsValidTuneMetricShouldReturnCorrectBoolean(t *testing.T) {
+	cases := map[TuneMetric]bool{
+		FScoreMetric:            true,
+		MccMetric:               true,
+		RecallAtPrecisionMetric: true,
+		-1:                      false,
+	}
+
+	for metric, expected := range cases {
+		actual := IsValidTuneMetric(metric)
+
+		assert.Equal(t, expected, actual)
+	}
+}
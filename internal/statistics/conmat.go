@@ -102,6 +102,19 @@ func CreateConfusionMatrix(actualClassifcationFrames, predictedClassificationFra
 	}
 }
 
+// Shift the classified frames by the offset and discard the frames which are not in the range of the given frames count. The base
+// specifies if the classification is represented by 0-based indexes or 1-based ordinal numbers.
+func ShiftClassificationRange(classification []int, offset, base, count int) []int {
+	shifted := make([]int, 0, len(classification))
+	for _, value := range classification {
+		if value -= offset; value >= base && value < count+base {
+			shifted = append(shifted, value)
+		}
+	}
+
+	return shifted
+}
+
 func getTotalClassification(classifiedIndices []int, totalCount int, ordinalNumerInsteadOfIndex bool) []bool {
 	classification := make([]bool, totalCount)
 
@@ -0,0 +1,26 @@
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftClassificationRangeShouldShiftAndDiscardTheFramesOutOfRange(t *testing.T) {
+	cases := []struct {
+		Classification []int
+		Offset         int
+		Base           int
+		Count          int
+		Expected       []int
+	}{
+		{[]int{1, 5, 10}, 0, 1, 10, []int{1, 5, 10}},
+		{[]int{100, 101, 105, 110, 111}, 100, 1, 10, []int{1, 5, 10}},
+		{[]int{99, 100, 104, 109, 110}, 100, 0, 10, []int{0, 4, 9}},
+		{[]int{}, 100, 0, 10, []int{}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.Expected, ShiftClassificationRange(c.Classification, c.Offset, c.Base, c.Count))
+	}
+}
@@ -2,27 +2,36 @@ package statistics
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
 )
 
+// Descriptive statistics of the frames. The moving median and median absolute deviation are optional and are not available
+// if not requested on the statistics creation.
 type DescriptiveStatistics struct {
 	BrightnessMean                             float64   `json:"brightness-mean"`
 	BrightnessMovingMean                       []float64 `json:"brightness-moving-mean"`
 	BrightnessMovingStdDev                     []float64 `json:"brightness-moving-standard-deviation"`
+	BrightnessMovingMedian                     []float64 `json:"brightness-moving-median,omitempty"`
+	BrightnessMovingMad                        []float64 `json:"brightness-moving-median-absolute-deviation,omitempty"`
 	BrightnessStandardDeviation                float64   `json:"brightness-standard-deviation"`
 	BrightnessMin                              float64   `json:"brightness-min"`
 	BrightnessMax                              float64   `json:"brightness-max"`
 	ColorDifferenceMean                        float64   `json:"color-difference-mean"`
 	ColorDifferenceMovingMean                  []float64 `json:"color-difference-moving-mean"`
 	ColorDifferenceMovingStdDev                []float64 `json:"color-difference-moving-standard-deviation"`
+	ColorDifferenceMovingMedian                []float64 `json:"color-difference-moving-median,omitempty"`
+	ColorDifferenceMovingMad                   []float64 `json:"color-difference-moving-median-absolute-deviation,omitempty"`
 	ColorDifferenceStandardDeviation           float64   `json:"color-difference-standard-deviation"`
 	ColorDifferenceMin                         float64   `json:"color-difference-min"`
 	ColorDifferenceMax                         float64   `json:"color-difference-max"`
 	BinaryThresholdDifferenceMean              float64   `json:"binary-threshold-difference-mean"`
 	BinaryThresholdDifferenceMovingMean        []float64 `json:"binary-threshold-difference-moving-mean"`
 	BinaryThresholdDifferenceMovingStdDev      []float64 `json:"binary-threshold-difference-moving-standard-deviation"`
+	BinaryThresholdDifferenceMovingMedian      []float64 `json:"binary-threshold-difference-moving-median,omitempty"`
+	BinaryThresholdDifferenceMovingMad         []float64 `json:"binary-threshold-difference-moving-median-absolute-deviation,omitempty"`
 	BinaryThresholdDifferenceStandardDeviation float64   `json:"binary-threshold-difference-standard-deviation"`
 	BinaryThresholdDifferenceMin               float64   `json:"binary-threshold-difference-min"`
 	BinaryThresholdDifferenceMax               float64   `json:"binary-threshold-difference-max"`
@@ -37,26 +46,41 @@ func (ds *DescriptiveStatistics) At(index int) (DescriptiveStatisticsEntry, erro
 	}
 }
 
+// Return a value indicating if the moving median and median absolute deviation are available.
+func (ds *DescriptiveStatistics) HasMovingMedian() bool {
+	return ds.BrightnessMovingMedian != nil
+}
+
+// Access the statistics of the frame specified by the index. The moving median and median absolute deviation of the entry
+// are NaN if not available.
 func (ds *DescriptiveStatistics) AtP(index int, e *DescriptiveStatisticsEntry) error {
 	if len(ds.BrightnessMovingMean) <= index {
 		return fmt.Errorf("statistics: the index is out of per element statistics range")
 	}
 
+	hasMovingMedian := ds.HasMovingMedian()
+
 	e.BrightnessMean = ds.BrightnessMean
 	e.BrightnessMovingMeanAtPoint = ds.BrightnessMovingMean[index]
 	e.BrightnessMovingStdDevAtPoint = ds.BrightnessMovingStdDev[index]
+	e.BrightnessMovingMedianAtPoint = movingStatisticAt(ds.BrightnessMovingMedian, index, hasMovingMedian)
+	e.BrightnessMovingMadAtPoint = movingStatisticAt(ds.BrightnessMovingMad, index, hasMovingMedian)
 	e.BrightnessStandardDeviation = ds.BrightnessStandardDeviation
 	e.BrightnessMin = ds.BrightnessMin
 	e.BrightnessMax = ds.BrightnessMax
 	e.ColorDifferenceMean = ds.ColorDifferenceMean
 	e.ColorDifferenceMovingMeanAtPoint = ds.ColorDifferenceMovingMean[index]
 	e.ColorDifferenceMovingStdDevAtPoint = ds.ColorDifferenceMovingStdDev[index]
+	e.ColorDifferenceMovingMedianAtPoint = movingStatisticAt(ds.ColorDifferenceMovingMedian, index, hasMovingMedian)
+	e.ColorDifferenceMovingMadAtPoint = movingStatisticAt(ds.ColorDifferenceMovingMad, index, hasMovingMedian)
 	e.ColorDifferenceStandardDeviation = ds.ColorDifferenceStandardDeviation
 	e.ColorDifferenceMin = ds.ColorDifferenceMin
 	e.ColorDifferenceMax = ds.ColorDifferenceMax
 	e.BinaryThresholdDifferenceMean = ds.BinaryThresholdDifferenceMean
 	e.BinaryThresholdDifferenceMovingMeanAtPoint = ds.BinaryThresholdDifferenceMovingMean[index]
 	e.BinaryThresholdDifferenceMovingStdDevAtPoint = ds.BinaryThresholdDifferenceMovingStdDev[index]
+	e.BinaryThresholdDifferenceMovingMedianAtPoint = movingStatisticAt(ds.BinaryThresholdDifferenceMovingMedian, index, hasMovingMedian)
+	e.BinaryThresholdDifferenceMovingMadAtPoint = movingStatisticAt(ds.BinaryThresholdDifferenceMovingMad, index, hasMovingMedian)
 	e.BinaryThresholdDifferenceStandardDeviation = ds.BinaryThresholdDifferenceStandardDeviation
 	e.BinaryThresholdDifferenceMin = ds.BinaryThresholdDifferenceMin
 	e.BinaryThresholdDifferenceMax = ds.BinaryThresholdDifferenceMax
@@ -64,28 +88,44 @@ func (ds *DescriptiveStatistics) AtP(index int, e *DescriptiveStatisticsEntry) e
 	return nil
 }
 
+func movingStatisticAt(values []float64, index int, available bool) float64 {
+	if !available {
+		return math.NaN()
+	}
+
+	return values[index]
+}
+
 type DescriptiveStatisticsEntry struct {
 	BrightnessMean                               float64 `json:"brightness-mean"`
 	BrightnessMovingMeanAtPoint                  float64 `json:"brightness-moving-mean"`
 	BrightnessMovingStdDevAtPoint                float64 `json:"brightness-moving-standard-deviation"`
+	BrightnessMovingMedianAtPoint                float64 `json:"brightness-moving-median"`
+	BrightnessMovingMadAtPoint                   float64 `json:"brightness-moving-median-absolute-deviation"`
 	BrightnessStandardDeviation                  float64 `json:"brightness-standard-deviation"`
 	BrightnessMin                                float64 `json:"brightness-min"`
 	BrightnessMax                                float64 `json:"brightness-max"`
 	ColorDifferenceMean                          float64 `json:"color-difference-mean"`
 	ColorDifferenceMovingMeanAtPoint             float64 `json:"color-difference-moving-mean"`
 	ColorDifferenceMovingStdDevAtPoint           float64 `json:"color-difference-moving-standard-deviation"`
+	ColorDifferenceMovingMedianAtPoint           float64 `json:"color-difference-moving-median"`
+	ColorDifferenceMovingMadAtPoint              float64 `json:"color-difference-moving-median-absolute-deviation"`
 	ColorDifferenceStandardDeviation             float64 `json:"color-difference-standard-deviation"`
 	ColorDifferenceMin                           float64 `json:"color-difference-min"`
 	ColorDifferenceMax                           float64 `json:"color-difference-max"`
 	BinaryThresholdDifferenceMean                float64 `json:"binary-threshold-difference-mean"`
 	BinaryThresholdDifferenceMovingMeanAtPoint   float64 `json:"binary-threshold-difference-moving-mean"`
 	BinaryThresholdDifferenceMovingStdDevAtPoint float64 `json:"binary-threshold-difference-moving-standard-deviation"`
+	BinaryThresholdDifferenceMovingMedianAtPoint float64 `json:"binary-threshold-difference-moving-median"`
+	BinaryThresholdDifferenceMovingMadAtPoint    float64 `json:"binary-threshold-difference-moving-median-absolute-deviation"`
 	BinaryThresholdDifferenceStandardDeviation   float64 `json:"binary-threshold-difference-standard-deviation"`
 	BinaryThresholdDifferenceMin                 float64 `json:"binary-threshold-difference-min"`
 	BinaryThresholdDifferenceMax                 float64 `json:"binary-threshold-difference-max"`
 }
 
-func CreateDescriptiveStatistics(fc frame.FrameCollection, movingMeanResolution int) DescriptiveStatistics {
+// Create the descriptive statistics of the frames using the moving window statistics. The moving median and median absolute
+// deviation are calculated only if requested, because they are required only by the moving median detection strategy.
+func CreateDescriptiveStatistics(fc frame.FrameCollection, movingMeanResolution int, movingMedian bool) DescriptiveStatistics {
 	frames := fc.GetAll()
 
 	var (
@@ -95,10 +135,16 @@ func CreateDescriptiveStatistics(fc frame.FrameCollection, movingMeanResolution
 		binaryThresholdDiff             []float64 = make([]float64, 0, len(frames))
 		brightnessMovingMean            []float64 = make([]float64, 0, len(frames))
 		brightnessMovingStdDev          []float64 = make([]float64, 0, len(frames))
+		brightnessMovingMedian          []float64 = nil
+		brightnessMovingMad             []float64 = nil
 		colorDiffMovingMean             []float64 = make([]float64, 0, len(frames))
 		colorDiffMovingStdDev           []float64 = make([]float64, 0, len(frames))
+		colorDiffMovingMedian           []float64 = nil
+		colorDiffMovingMad              []float64 = nil
 		binaryThresholdDiffMovingMean   []float64 = make([]float64, 0, len(frames))
 		binaryThresholdDiffMovingStdDev []float64 = make([]float64, 0, len(frames))
+		binaryThresholdDiffMovingMedian []float64 = nil
+		binaryThresholdDiffMovingMad    []float64 = nil
 	)
 
 	for _, frame := range frames {
@@ -126,6 +172,12 @@ func CreateDescriptiveStatistics(fc frame.FrameCollection, movingMeanResolution
 		binaryThresholdDiffMovingStdDev = append(binaryThresholdDiffMovingStdDev, movingStdDev)
 	}
 
+	if movingMedian {
+		brightnessMovingMedian, brightnessMovingMad = createMovingMedianMad(brightness, movingMeanBias)
+		colorDiffMovingMedian, colorDiffMovingMad = createMovingMedianMad(colorDiff, movingMeanBias)
+		binaryThresholdDiffMovingMedian, binaryThresholdDiffMovingMad = createMovingMedianMad(binaryThresholdDiff, movingMeanBias)
+	}
+
 	var (
 		brightnessMin, brightnessMax     = utils.MinMax(brightness)
 		brightnessMean, brightnessStdDev = utils.MeanStdDev(brightness)
@@ -139,20 +191,45 @@ func CreateDescriptiveStatistics(fc frame.FrameCollection, movingMeanResolution
 		BrightnessMean:                             brightnessMean,
 		BrightnessMovingMean:                       brightnessMovingMean,
 		BrightnessMovingStdDev:                     brightnessMovingStdDev,
+		BrightnessMovingMedian:                     brightnessMovingMedian,
+		BrightnessMovingMad:                        brightnessMovingMad,
 		BrightnessStandardDeviation:                brightnessStdDev,
 		BrightnessMin:                              brightnessMin,
 		BrightnessMax:                              brightnessMax,
 		ColorDifferenceMean:                        colorDiffMean,
 		ColorDifferenceMovingMean:                  colorDiffMovingMean,
 		ColorDifferenceMovingStdDev:                colorDiffMovingStdDev,
+		ColorDifferenceMovingMedian:                colorDiffMovingMedian,
+		ColorDifferenceMovingMad:                   colorDiffMovingMad,
 		ColorDifferenceStandardDeviation:           colorDiffStdDev,
 		ColorDifferenceMin:                         colorDiffMin,
 		ColorDifferenceMax:                         colorDiffMax,
 		BinaryThresholdDifferenceMean:              btDiffMean,
 		BinaryThresholdDifferenceMovingMean:        binaryThresholdDiffMovingMean,
 		BinaryThresholdDifferenceMovingStdDev:      binaryThresholdDiffMovingStdDev,
+		BinaryThresholdDifferenceMovingMedian:      binaryThresholdDiffMovingMedian,
+		BinaryThresholdDifferenceMovingMad:         binaryThresholdDiffMovingMad,
 		BinaryThresholdDifferenceStandardDeviation: btDiffStdDev,
 		BinaryThresholdDifferenceMin:               btDiffMin,
 		BinaryThresholdDifferenceMax:               btDiffMax,
 	}
 }
+
+// Helper function used to calculate the moving median and median absolute deviation of all values. A single buffer is reused
+// for the window values of all positions in order to avoid the allocation for each position.
+func createMovingMedianMad(values []float64, bias int) ([]float64, []float64) {
+	var (
+		movingMedian []float64 = make([]float64, 0, len(values))
+		movingMad    []float64 = make([]float64, 0, len(values))
+		buffer       []float64 = make([]float64, 0, 2*bias+1)
+		median, mad  float64
+	)
+
+	for index := range values {
+		median, mad, buffer = utils.MovingMedianMadBuffered(values, index, bias, buffer)
+		movingMedian = append(movingMedian, median)
+		movingMad = append(movingMad, mad)
+	}
+
+	return movingMedian, movingMad
+}
@@ -0,0 +1,155 @@
+package statistics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+type exponentialDescriptiveStatistics struct {
+	Alpha    float64
+	Count    int
+	Previous DescriptiveStatisticsEntry
+}
+
+func (stat *exponentialDescriptiveStatistics) Push(f *frame.Frame) {
+	var (
+		length         int     = stat.Count
+		nextBrightness float64 = f.Brightness
+		nextColorDiff  float64 = f.ColorDifference
+		nextBtDiff     float64 = f.BinaryThresholdDifference
+		previous       DescriptiveStatisticsEntry
+	)
+
+	stat.Count += 1
+
+	if length == 0 {
+		previous = DescriptiveStatisticsEntry{
+			BrightnessMean:                             nextBrightness,
+			BrightnessMovingMeanAtPoint:                nextBrightness,
+			BrightnessMin:                              nextBrightness,
+			BrightnessMax:                              nextBrightness,
+			ColorDifferenceMean:                        nextColorDiff,
+			ColorDifferenceMovingMeanAtPoint:           nextColorDiff,
+			ColorDifferenceMin:                         nextColorDiff,
+			ColorDifferenceMax:                         nextColorDiff,
+			BinaryThresholdDifferenceMean:              nextBtDiff,
+			BinaryThresholdDifferenceMovingMeanAtPoint: nextBtDiff,
+			BinaryThresholdDifferenceMin:               nextBtDiff,
+			BinaryThresholdDifferenceMax:               nextBtDiff,
+		}
+	} else {
+		previous = stat.Previous
+	}
+
+	next := DescriptiveStatisticsEntry{}
+
+	// NOTE: The exponential backend does not keep the frame values, therefore the moving median and MAD are not available
+	next.BrightnessMin, next.BrightnessMax = utils.MinMaxInc(nextBrightness, previous.BrightnessMin, previous.BrightnessMax)
+	next.BrightnessMean, next.BrightnessStandardDeviation = utils.MeanStdDevInc(nextBrightness, previous.BrightnessMean, previous.BrightnessStandardDeviation, length)
+	next.BrightnessMovingMeanAtPoint, next.BrightnessMovingStdDevAtPoint = utils.ExponentialMeanStdDevInc(nextBrightness, previous.BrightnessMovingMeanAtPoint, previous.BrightnessMovingStdDevAtPoint, stat.Alpha)
+	next.BrightnessMovingMedianAtPoint = math.NaN()
+	next.BrightnessMovingMadAtPoint = math.NaN()
+
+	next.ColorDifferenceMin, next.ColorDifferenceMax = utils.MinMaxInc(nextColorDiff, previous.ColorDifferenceMin, previous.ColorDifferenceMax)
+	next.ColorDifferenceMean, next.ColorDifferenceStandardDeviation = utils.MeanStdDevInc(nextColorDiff, previous.ColorDifferenceMean, previous.ColorDifferenceStandardDeviation, length)
+	next.ColorDifferenceMovingMeanAtPoint, next.ColorDifferenceMovingStdDevAtPoint = utils.ExponentialMeanStdDevInc(nextColorDiff, previous.ColorDifferenceMovingMeanAtPoint, previous.ColorDifferenceMovingStdDevAtPoint, stat.Alpha)
+	next.ColorDifferenceMovingMedianAtPoint = math.NaN()
+	next.ColorDifferenceMovingMadAtPoint = math.NaN()
+
+	next.BinaryThresholdDifferenceMin, next.BinaryThresholdDifferenceMax = utils.MinMaxInc(nextBtDiff, previous.BinaryThresholdDifferenceMin, previous.BinaryThresholdDifferenceMax)
+	next.BinaryThresholdDifferenceMean, next.BinaryThresholdDifferenceStandardDeviation = utils.MeanStdDevInc(nextBtDiff, previous.BinaryThresholdDifferenceMean, previous.BinaryThresholdDifferenceStandardDeviation, length)
+	next.BinaryThresholdDifferenceMovingMeanAtPoint, next.BinaryThresholdDifferenceMovingStdDevAtPoint = utils.ExponentialMeanStdDevInc(nextBtDiff, previous.BinaryThresholdDifferenceMovingMeanAtPoint, previous.BinaryThresholdDifferenceMovingStdDevAtPoint, stat.Alpha)
+	next.BinaryThresholdDifferenceMovingMedianAtPoint = math.NaN()
+	next.BinaryThresholdDifferenceMovingMadAtPoint = math.NaN()
+
+	stat.Previous = next
+}
+
+func (stat *exponentialDescriptiveStatistics) Peek() DescriptiveStatisticsEntry {
+	return stat.Previous
+}
+
+// Create a new incremental descriptive statistics instance using the exponentially weighted moving mean and variance
+// as the moving statistics. The half-life is specified in seconds and is converted to frames using the frame rate.
+// The memory usage is constant and is not dependent on the half-life length.
+func NewExponentialDescriptiveStatistics(halfLife, fps float64) (IncrementalDescriptiveStatistics, error) {
+	alpha, err := exponentialSmoothingFactor(halfLife, fps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exponentialDescriptiveStatistics{
+		Alpha:    alpha,
+		Count:    0,
+		Previous: DescriptiveStatisticsEntry{},
+	}, nil
+}
+
+// Create the descriptive statistics of the frames using the exponentially weighted moving mean and variance as the
+// moving statistics. The half-life is specified in seconds and is converted to frames using the frame rate. In contrast
+// to the moving window statistics the values at each point are only dependent on the previous frames.
+func CreateExponentialDescriptiveStatistics(fc frame.FrameCollection, halfLife, fps float64) (DescriptiveStatistics, error) {
+	frames := fc.GetAll()
+
+	if len(frames) == 0 {
+		return DescriptiveStatistics{}, fmt.Errorf("statistics: can not create the descriptive statistics of an empty frame collection")
+	}
+
+	stats, err := NewExponentialDescriptiveStatistics(halfLife, fps)
+	if err != nil {
+		return DescriptiveStatistics{}, err
+	}
+
+	ds := DescriptiveStatistics{
+		BrightnessMovingMean:                  make([]float64, 0, len(frames)),
+		BrightnessMovingStdDev:                make([]float64, 0, len(frames)),
+		ColorDifferenceMovingMean:             make([]float64, 0, len(frames)),
+		ColorDifferenceMovingStdDev:           make([]float64, 0, len(frames)),
+		BinaryThresholdDifferenceMovingMean:   make([]float64, 0, len(frames)),
+		BinaryThresholdDifferenceMovingStdDev: make([]float64, 0, len(frames)),
+	}
+
+	var entry DescriptiveStatisticsEntry
+	for _, frame := range frames {
+		stats.Push(frame)
+		entry = stats.Peek()
+
+		ds.BrightnessMovingMean = append(ds.BrightnessMovingMean, entry.BrightnessMovingMeanAtPoint)
+		ds.BrightnessMovingStdDev = append(ds.BrightnessMovingStdDev, entry.BrightnessMovingStdDevAtPoint)
+		ds.ColorDifferenceMovingMean = append(ds.ColorDifferenceMovingMean, entry.ColorDifferenceMovingMeanAtPoint)
+		ds.ColorDifferenceMovingStdDev = append(ds.ColorDifferenceMovingStdDev, entry.ColorDifferenceMovingStdDevAtPoint)
+		ds.BinaryThresholdDifferenceMovingMean = append(ds.BinaryThresholdDifferenceMovingMean, entry.BinaryThresholdDifferenceMovingMeanAtPoint)
+		ds.BinaryThresholdDifferenceMovingStdDev = append(ds.BinaryThresholdDifferenceMovingStdDev, entry.BinaryThresholdDifferenceMovingStdDevAtPoint)
+	}
+
+	ds.BrightnessMean = entry.BrightnessMean
+	ds.BrightnessStandardDeviation = entry.BrightnessStandardDeviation
+	ds.BrightnessMin = entry.BrightnessMin
+	ds.BrightnessMax = entry.BrightnessMax
+	ds.ColorDifferenceMean = entry.ColorDifferenceMean
+	ds.ColorDifferenceStandardDeviation = entry.ColorDifferenceStandardDeviation
+	ds.ColorDifferenceMin = entry.ColorDifferenceMin
+	ds.ColorDifferenceMax = entry.ColorDifferenceMax
+	ds.BinaryThresholdDifferenceMean = entry.BinaryThresholdDifferenceMean
+	ds.BinaryThresholdDifferenceStandardDeviation = entry.BinaryThresholdDifferenceStandardDeviation
+	ds.BinaryThresholdDifferenceMin = entry.BinaryThresholdDifferenceMin
+	ds.BinaryThresholdDifferenceMax = entry.BinaryThresholdDifferenceMax
+
+	return ds, nil
+}
+
+// Calculate the exponential smoothing factor for which the weight of a value is halved after the half-life seconds.
+func exponentialSmoothingFactor(halfLife, fps float64) (float64, error) {
+	if halfLife <= 0 {
+		return 0, fmt.Errorf("statistics: the exponential statistics half-life must be greater than zero")
+	}
+
+	if fps <= 0 {
+		return 0, fmt.Errorf("statistics: the frame rate is required to calculate the exponential statistics")
+	}
+
+	return 1 - math.Pow(2, -1/(halfLife*fps)), nil
+}
@@ -0,0 +1,95 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/frame"
+)
+
+func TestNewExponentialDescriptiveStatisticsShouldFailForInvalidArguments(t *testing.T) {
+	cases := [][2]float64{{0, 30}, {-1, 30}, {1, 0}, {1, -30}}
+
+	for _, c := range cases {
+		stats, err := NewExponentialDescriptiveStatistics(c[0], c[1])
+
+		assert.Nil(t, stats)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestExponentialDescriptiveStatisticsShouldHalveTheWeightAfterHalfLife(t *testing.T) {
+	const (
+		halfLife float64 = 2.0
+		fps      float64 = 5.0
+		delta    float64 = 1e-10
+	)
+
+	stats, err := NewExponentialDescriptiveStatistics(halfLife, fps)
+	assert.Nil(t, err)
+
+	stats.Push(&frame.Frame{OrdinalNumber: 1, Brightness: 1.0, ColorDifference: 1.0, BinaryThresholdDifference: 1.0})
+
+	for index := 0; index < int(halfLife*fps); index += 1 {
+		stats.Push(&frame.Frame{OrdinalNumber: index + 2, Brightness: 0.0, ColorDifference: 0.0, BinaryThresholdDifference: 0.0})
+	}
+
+	actualStats := stats.Peek()
+
+	assert.InDelta(t, 0.5, actualStats.BrightnessMovingMeanAtPoint, delta)
+	assert.InDelta(t, 0.5, actualStats.ColorDifferenceMovingMeanAtPoint, delta)
+	assert.InDelta(t, 0.5, actualStats.BinaryThresholdDifferenceMovingMeanAtPoint, delta)
+	assert.True(t, math.IsNaN(actualStats.BrightnessMovingMedianAtPoint))
+	assert.True(t, math.IsNaN(actualStats.BrightnessMovingMadAtPoint))
+	assert.InDelta(t, 0.0, actualStats.BrightnessMin, delta)
+	assert.InDelta(t, 1.0, actualStats.BrightnessMax, delta)
+	assert.InDelta(t, 1.0/11.0, actualStats.BrightnessMean, delta)
+}
+
+func TestCreateExponentialDescriptiveStatisticsShouldMatchIncrementalStatistics(t *testing.T) {
+	testFrames := []*frame.Frame{
+		{OrdinalNumber: 1, ColorDifference: 0.5, BinaryThresholdDifference: 0.0, Brightness: 0.1},
+		{OrdinalNumber: 2, ColorDifference: 0.5, BinaryThresholdDifference: 0.0, Brightness: 0.2},
+		{OrdinalNumber: 3, ColorDifference: 0.0, BinaryThresholdDifference: 0.1, Brightness: 0.3},
+		{OrdinalNumber: 4, ColorDifference: 0.0, BinaryThresholdDifference: 0.4, Brightness: 0.4},
+		{OrdinalNumber: 5, ColorDifference: 0.4, BinaryThresholdDifference: 0.9, Brightness: 0.5},
+	}
+
+	fc := frame.NewFrameCollection(len(testFrames))
+	for _, f := range testFrames {
+		err := fc.Push(f)
+		assert.Nil(t, err)
+	}
+
+	fc.Lock()
+
+	const delta float64 = 1e-10
+
+	ds, err := CreateExponentialDescriptiveStatistics(fc, 0.5, 4)
+	assert.Nil(t, err)
+
+	stats, err := NewExponentialDescriptiveStatistics(0.5, 4)
+	assert.Nil(t, err)
+
+	for index, f := range testFrames {
+		stats.Push(f)
+
+		expected := stats.Peek()
+		actual, err := ds.At(index)
+		assert.Nil(t, err)
+
+		assert.InDelta(t, expected.BrightnessMovingMeanAtPoint, actual.BrightnessMovingMeanAtPoint, delta)
+		assert.InDelta(t, expected.ColorDifferenceMovingStdDevAtPoint, actual.ColorDifferenceMovingStdDevAtPoint, delta)
+		assert.True(t, math.IsNaN(actual.BinaryThresholdDifferenceMovingMadAtPoint))
+		assert.False(t, math.IsNaN(actual.BrightnessMovingStdDevAtPoint))
+	}
+
+	assert.False(t, ds.HasMovingMedian())
+	assert.Nil(t, ds.BrightnessMovingMedian)
+
+	assert.InDelta(t, 0.3, ds.BrightnessMean, delta)
+	assert.InDelta(t, 0.14142135623731, ds.BrightnessStandardDeviation, delta)
+	assert.InDelta(t, 0.9, ds.BinaryThresholdDifferenceMax, delta)
+}
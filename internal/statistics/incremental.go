@@ -14,9 +14,10 @@ type IncrementalDescriptiveStatistics interface {
 }
 
 type incrementalDescriptiveStatistics struct {
-	Bias        int
-	Previous    DescriptiveStatisticsEntry
-	FrameBuffer utils.CircularBuffer[*frame.Frame]
+	Bias         int
+	Previous     DescriptiveStatisticsEntry
+	FrameBuffer  utils.CircularBuffer[*frame.Frame]
+	WindowValues []float64
 }
 
 func (stat *incrementalDescriptiveStatistics) Push(f *frame.Frame) {
@@ -33,18 +34,24 @@ func (stat *incrementalDescriptiveStatistics) Push(f *frame.Frame) {
 			BrightnessMean:                               nextBrightness,
 			BrightnessMovingMeanAtPoint:                  nextBrightness,
 			BrightnessMovingStdDevAtPoint:                0,
+			BrightnessMovingMedianAtPoint:                nextBrightness,
+			BrightnessMovingMadAtPoint:                   0,
 			BrightnessStandardDeviation:                  0,
 			BrightnessMin:                                nextBrightness,
 			BrightnessMax:                                nextBrightness,
 			ColorDifferenceMean:                          nextColorDiff,
 			ColorDifferenceMovingMeanAtPoint:             nextColorDiff,
 			ColorDifferenceMovingStdDevAtPoint:           0,
+			ColorDifferenceMovingMedianAtPoint:           nextColorDiff,
+			ColorDifferenceMovingMadAtPoint:              0,
 			ColorDifferenceStandardDeviation:             0,
 			ColorDifferenceMin:                           nextColorDiff,
 			ColorDifferenceMax:                           nextColorDiff,
 			BinaryThresholdDifferenceMean:                nextBtDiff,
 			BinaryThresholdDifferenceMovingMeanAtPoint:   nextBtDiff,
 			BinaryThresholdDifferenceMovingStdDevAtPoint: 0,
+			BinaryThresholdDifferenceMovingMedianAtPoint: nextBtDiff,
+			BinaryThresholdDifferenceMovingMadAtPoint:    0,
 			BinaryThresholdDifferenceStandardDeviation:   0,
 			BinaryThresholdDifferenceMin:                 nextBtDiff,
 			BinaryThresholdDifferenceMax:                 nextBtDiff,
@@ -114,28 +121,51 @@ func (stat *incrementalDescriptiveStatistics) Push(f *frame.Frame) {
 	wg.Wait()
 
 	stat.FrameBuffer.Push(f)
+
+	var (
+		brightnessMovingMedian, brightnessMovingMad float64 = stat.movingMedianMad(func(f *frame.Frame) float64 { return f.Brightness })
+		colorDiffMovingMedian, colorDiffMovingMad   float64 = stat.movingMedianMad(func(f *frame.Frame) float64 { return f.ColorDifference })
+		btDiffMovingMedian, btDiffMovingMad         float64 = stat.movingMedianMad(func(f *frame.Frame) float64 { return f.BinaryThresholdDifference })
+	)
+
 	stat.Previous = DescriptiveStatisticsEntry{
 		BrightnessMean:                               brightnessMean,
 		BrightnessMovingMeanAtPoint:                  brightnessMovingMean,
 		BrightnessMovingStdDevAtPoint:                brightnessMovingStdDev,
+		BrightnessMovingMedianAtPoint:                brightnessMovingMedian,
+		BrightnessMovingMadAtPoint:                   brightnessMovingMad,
 		BrightnessStandardDeviation:                  brightnessStdDev,
 		BrightnessMin:                                brightnessMin,
 		BrightnessMax:                                brightnessMax,
 		ColorDifferenceMean:                          colorDiffMean,
 		ColorDifferenceMovingMeanAtPoint:             colorDiffMovingMean,
 		ColorDifferenceMovingStdDevAtPoint:           colorDiffMovingStdDev,
+		ColorDifferenceMovingMedianAtPoint:           colorDiffMovingMedian,
+		ColorDifferenceMovingMadAtPoint:              colorDiffMovingMad,
 		ColorDifferenceStandardDeviation:             colorDiffStdDev,
 		ColorDifferenceMin:                           colorDiffMin,
 		ColorDifferenceMax:                           colorDiffMax,
 		BinaryThresholdDifferenceMean:                btDiffMean,
 		BinaryThresholdDifferenceMovingMeanAtPoint:   btDiffMovingMean,
 		BinaryThresholdDifferenceMovingStdDevAtPoint: btDiffMovingStdDev,
+		BinaryThresholdDifferenceMovingMedianAtPoint: btDiffMovingMedian,
+		BinaryThresholdDifferenceMovingMadAtPoint:    btDiffMovingMad,
 		BinaryThresholdDifferenceStandardDeviation:   btDiffStdDev,
 		BinaryThresholdDifferenceMin:                 btDiffMin,
 		BinaryThresholdDifferenceMax:                 btDiffMax,
 	}
 }
 
+// Calculate the median and median absolute deviation of the selected frame values currently stored in the frame buffer.
+func (stat *incrementalDescriptiveStatistics) movingMedianMad(selector func(f *frame.Frame) float64) (float64, float64) {
+	stat.WindowValues = stat.WindowValues[:0]
+	for _, f := range stat.FrameBuffer.PeekWindow()[:stat.FrameBuffer.GetBufferCount()] {
+		stat.WindowValues = append(stat.WindowValues, selector(f))
+	}
+
+	return utils.MedianMad(stat.WindowValues)
+}
+
 func (stat *incrementalDescriptiveStatistics) Peek() DescriptiveStatisticsEntry {
 	return stat.Previous
 }
@@ -146,8 +176,9 @@ func NewIncrementalDescriptiveStatistics(movingMeanResolution int) IncrementalDe
 	bias := movingMeanResolution/2 + 1
 
 	return &incrementalDescriptiveStatistics{
-		Bias:        bias,
-		Previous:    DescriptiveStatisticsEntry{},
-		FrameBuffer: utils.NewCircularBuffer[*frame.Frame](bias),
+		Bias:         bias,
+		Previous:     DescriptiveStatisticsEntry{},
+		FrameBuffer:  utils.NewCircularBuffer[*frame.Frame](bias),
+		WindowValues: make([]float64, 0, bias),
 	}
 }
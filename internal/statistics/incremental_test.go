@@ -22,18 +22,24 @@ func TestIncrementalDescriptiveStatisticsShouldCorrectlyCalculateDescriptiveStat
 		ExpectedBrightnessMean                               float64
 		ExpectedBrightnessMovingMeanAtPoint                  float64
 		ExpectedBrightnessMovingStdDevAtPoint                float64
+		ExpectedBrightnessMovingMedianAtPoint                float64
+		ExpectedBrightnessMovingMadAtPoint                   float64
 		ExpectedBrightnessStandardDeviation                  float64
 		ExpectedBrightnessMin                                float64
 		ExpectedBrightnessMax                                float64
 		ExpectedColorDifferenceMean                          float64
 		ExpectedColorDifferenceMovingMeanAtPoint             float64
 		ExpectedColorDifferenceMovingStdDevAtPoint           float64
+		ExpectedColorDifferenceMovingMedianAtPoint           float64
+		ExpectedColorDifferenceMovingMadAtPoint              float64
 		ExpectedColorDifferenceStandardDeviation             float64
 		ExpectedColorDifferenceMin                           float64
 		ExpectedColorDifferenceMax                           float64
 		ExpectedBinaryThresholdDifferenceMean                float64
 		ExpectedBinaryThresholdDifferenceMovingMeanAtPoint   float64
 		ExpectedBinaryThresholdDifferenceMovingStdDevAtPoint float64
+		ExpectedBinaryThresholdDifferenceMovingMedianAtPoint float64
+		ExpectedBinaryThresholdDifferenceMovingMadAtPoint    float64
 		ExpectedBinaryThresholdDifferenceStandardDeviation   float64
 		ExpectedBinaryThresholdDifferenceMin                 float64
 		ExpectedBinaryThresholdDifferenceMax                 float64
@@ -44,18 +50,24 @@ func TestIncrementalDescriptiveStatisticsShouldCorrectlyCalculateDescriptiveStat
 			ExpectedBrightnessMean:                               0.3,
 			ExpectedBrightnessMovingMeanAtPoint:                  0.4,
 			ExpectedBrightnessMovingStdDevAtPoint:                0.081649658092773,
+			ExpectedBrightnessMovingMedianAtPoint:                0.4,
+			ExpectedBrightnessMovingMadAtPoint:                   0.1,
 			ExpectedBrightnessStandardDeviation:                  0.14142135623731,
 			ExpectedBrightnessMin:                                0.1,
 			ExpectedBrightnessMax:                                0.5,
 			ExpectedColorDifferenceMean:                          0.28,
 			ExpectedColorDifferenceMovingMeanAtPoint:             0.13333333333333,
 			ExpectedColorDifferenceMovingStdDevAtPoint:           0.18856180831641,
+			ExpectedColorDifferenceMovingMedianAtPoint:           0.0,
+			ExpectedColorDifferenceMovingMadAtPoint:              0.0,
 			ExpectedColorDifferenceStandardDeviation:             0.2315167380558,
 			ExpectedColorDifferenceMin:                           0.0,
 			ExpectedColorDifferenceMax:                           0.5,
 			ExpectedBinaryThresholdDifferenceMean:                0.28,
 			ExpectedBinaryThresholdDifferenceMovingMeanAtPoint:   0.46666666666667,
 			ExpectedBinaryThresholdDifferenceMovingStdDevAtPoint: 0.32998316455372,
+			ExpectedBinaryThresholdDifferenceMovingMedianAtPoint: 0.4,
+			ExpectedBinaryThresholdDifferenceMovingMadAtPoint:    0.3,
 			ExpectedBinaryThresholdDifferenceStandardDeviation:   0.34292856398964,
 			ExpectedBinaryThresholdDifferenceMin:                 0.0,
 			ExpectedBinaryThresholdDifferenceMax:                 0.9,
@@ -77,18 +89,24 @@ func TestIncrementalDescriptiveStatisticsShouldCorrectlyCalculateDescriptiveStat
 		assert.InDelta(t, c.ExpectedBrightnessMean, actualStats.BrightnessMean, delta)
 		assert.InDelta(t, c.ExpectedBrightnessMovingMeanAtPoint, actualStats.BrightnessMovingMeanAtPoint, delta)
 		assert.InDelta(t, c.ExpectedBrightnessMovingStdDevAtPoint, actualStats.BrightnessMovingStdDevAtPoint, delta)
+		assert.InDelta(t, c.ExpectedBrightnessMovingMedianAtPoint, actualStats.BrightnessMovingMedianAtPoint, delta)
+		assert.InDelta(t, c.ExpectedBrightnessMovingMadAtPoint, actualStats.BrightnessMovingMadAtPoint, delta)
 		assert.InDelta(t, c.ExpectedBrightnessStandardDeviation, actualStats.BrightnessStandardDeviation, delta)
 		assert.InDelta(t, c.ExpectedBrightnessMin, actualStats.BrightnessMin, delta)
 		assert.InDelta(t, c.ExpectedBrightnessMax, actualStats.BrightnessMax, delta)
 		assert.InDelta(t, c.ExpectedColorDifferenceMean, actualStats.ColorDifferenceMean, delta)
 		assert.InDelta(t, c.ExpectedColorDifferenceMovingMeanAtPoint, actualStats.ColorDifferenceMovingMeanAtPoint, delta)
 		assert.InDelta(t, c.ExpectedColorDifferenceMovingStdDevAtPoint, actualStats.ColorDifferenceMovingStdDevAtPoint, delta)
+		assert.InDelta(t, c.ExpectedColorDifferenceMovingMedianAtPoint, actualStats.ColorDifferenceMovingMedianAtPoint, delta)
+		assert.InDelta(t, c.ExpectedColorDifferenceMovingMadAtPoint, actualStats.ColorDifferenceMovingMadAtPoint, delta)
 		assert.InDelta(t, c.ExpectedColorDifferenceStandardDeviation, actualStats.ColorDifferenceStandardDeviation, delta)
 		assert.InDelta(t, c.ExpectedColorDifferenceMin, actualStats.ColorDifferenceMin, delta)
 		assert.InDelta(t, c.ExpectedColorDifferenceMax, actualStats.ColorDifferenceMax, delta)
 		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMean, actualStats.BinaryThresholdDifferenceMean, delta)
 		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMovingMeanAtPoint, actualStats.BinaryThresholdDifferenceMovingMeanAtPoint, delta)
 		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMovingStdDevAtPoint, actualStats.BinaryThresholdDifferenceMovingStdDevAtPoint, delta)
+		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMovingMedianAtPoint, actualStats.BinaryThresholdDifferenceMovingMedianAtPoint, delta)
+		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMovingMadAtPoint, actualStats.BinaryThresholdDifferenceMovingMadAtPoint, delta)
 		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceStandardDeviation, actualStats.BinaryThresholdDifferenceStandardDeviation, delta)
 		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMin, actualStats.BinaryThresholdDifferenceMin, delta)
 		assert.InDelta(t, c.ExpectedBinaryThresholdDifferenceMax, actualStats.BinaryThresholdDifferenceMax, delta)
@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -103,3 +107,176 @@ func ImportImageRgba(path string) (*image.RGBA, error) {
 		return rgba, nil
 	}
 }
+
+// Find the files of the directory which names or slash separated paths relative to the directory are matching any of the include
+// glob patterns and none of the exclude glob patterns. The patterns are matched case-insensitive. The subdirectories are searched
+// only if the recursive flag is set. The excluded directory (Example: output directory within the searched directory) is skipped if
+// specified. The returned paths are relative to the directory and sorted lexically.
+func FindFiles(directoryPath string, include, exclude []string, recursive bool, excludedDirectoryPath string) ([]string, error) {
+	if info, err := os.Stat(directoryPath); err != nil {
+		return nil, fmt.Errorf("utils: failed to access the directory: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("utils: the specified path is not pointing to a directory")
+	}
+
+	if len(excludedDirectoryPath) != 0 {
+		absolutePath, err := filepath.Abs(excludedDirectoryPath)
+		if err != nil {
+			return nil, fmt.Errorf("utils: failed to access the excluded directory absolute path: %w", err)
+		}
+
+		excludedDirectoryPath = absolutePath
+	}
+
+	matchAny := func(patterns []string, name, relativePath string) (bool, error) {
+		for _, pattern := range patterns {
+			for _, value := range []string{name, relativePath} {
+				if ok, err := filepath.Match(strings.ToLower(pattern), strings.ToLower(value)); err != nil {
+					return false, err
+				} else if ok {
+					return true, nil
+				}
+			}
+		}
+
+		return false, nil
+	}
+
+	files := make([]string, 0)
+	err := filepath.WalkDir(directoryPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path == directoryPath {
+				return nil
+			}
+
+			if !recursive {
+				return filepath.SkipDir
+			}
+
+			if len(excludedDirectoryPath) != 0 {
+				if absolutePath, err := filepath.Abs(path); err != nil {
+					return err
+				} else if absolutePath == excludedDirectoryPath {
+					return filepath.SkipDir
+				}
+			}
+
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(directoryPath, path)
+		if err != nil {
+			return err
+		}
+
+		relativePath = filepath.ToSlash(relativePath)
+
+		if included, err := matchAny(include, entry.Name(), relativePath); err != nil || !included {
+			return err
+		}
+
+		if excluded, err := matchAny(exclude, entry.Name(), relativePath); err != nil || excluded {
+			return err
+		}
+
+		files = append(files, relativePath)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("utils: failed to search the directory files: %w", err)
+	}
+
+	return files, nil
+}
+
+// The length of a single file content sample used by the fingerprint.
+const fingerprintSampleLength int64 = 64 * 1024
+
+// Calculate a fast SHA1, hex-encoded fingerprint of the file content. The fingerprint is covering the file size, the modification
+// time and the samples of the file content taken from the beginning, the middle and the end of the file, therefore the whole file
+// is not read and the fingerprint is not suitable for the detection of intentional modifications.
+func FileFingerprint(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("utils: failed to open the file for the fingerprint: %w", err)
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("utils: failed to access the file information for the fingerprint: %w", err)
+	}
+
+	hash := sha1.New()
+
+	if err := binary.Write(hash, binary.LittleEndian, info.Size()); err != nil {
+		return "", fmt.Errorf("utils: failed to encode the file size for the fingerprint: %w", err)
+	}
+
+	if err := binary.Write(hash, binary.LittleEndian, info.ModTime().UnixNano()); err != nil {
+		return "", fmt.Errorf("utils: failed to encode the file modification time for the fingerprint: %w", err)
+	}
+
+	offsets := []int64{0, (info.Size() - fingerprintSampleLength) / 2, info.Size() - fingerprintSampleLength}
+	sample := make([]byte, fingerprintSampleLength)
+
+	for _, offset := range offsets {
+		if offset < 0 {
+			offset = 0
+		}
+
+		n, err := file.ReadAt(sample, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("utils: failed to read the file sample for the fingerprint: %w", err)
+		}
+
+		hash.Write(sample[:n])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Calculate a fast SHA1, hex-encoded fingerprint of the ordered set of files. The fingerprint is covering the order, names, sizes and
+// modification times of all files and the content fingerprints of the first and the last file.
+func FileSetFingerprint(paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", fmt.Errorf("utils: the fingerprint file set can not be empty")
+	}
+
+	hash := sha1.New()
+
+	if err := binary.Write(hash, binary.LittleEndian, int64(len(paths))); err != nil {
+		return "", fmt.Errorf("utils: failed to encode the files count for the fingerprint: %w", err)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("utils: failed to access the file information for the fingerprint: %w", err)
+		}
+
+		hash.Write([]byte(filepath.Base(path)))
+		hash.Write([]byte{0})
+
+		if err := binary.Write(hash, binary.LittleEndian, []int64{info.Size(), info.ModTime().UnixNano()}); err != nil {
+			return "", fmt.Errorf("utils: failed to encode the file size and modification time for the fingerprint: %w", err)
+		}
+	}
+
+	for _, path := range []string{paths[0], paths[len(paths)-1]} {
+		fingerprint, err := FileFingerprint(path)
+		if err != nil {
+			return "", err
+		}
+
+		hash.Write([]byte(fingerprint))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -1,11 +1,13 @@
 package utils
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -71,3 +73,120 @@ func TestShouldExportImageAsPngForValidPathAndImage(t *testing.T) {
 
 	assert.True(t, FileExists(imagePath))
 }
+
+func TestShouldFindFilesMatchingThePatterns(t *testing.T) {
+	testCleanupPath()
+	defer testCleanupPath()
+
+	for _, name := range []string{"a.mp4", "b.MOV", "c.txt", "preview.mp4", "nested/d.mp4", "nested/deep/e.mp4", "output/a/event-1.mp4"} {
+		file, err := CreateFileWithTree(path.Join(testPath, name))
+		assert.Nil(t, err)
+
+		_ = file.Close()
+	}
+
+	files, err := FindFiles(testPath, []string{"*.mp4", "*.mov"}, []string{"PREVIEW*"}, false, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.mp4", "b.MOV"}, files)
+
+	files, err = FindFiles(testPath, []string{"*.mp4"}, []string{"nested/deep/*"}, true, path.Join(testPath, "output"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.mp4", "nested/d.mp4", "preview.mp4"}, files)
+
+	files, err = FindFiles(testPath, []string{"*.mp4"}, []string{"nested/*"}, true, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.mp4", "nested/deep/e.mp4", "output/a/event-1.mp4", "preview.mp4"}, files)
+
+	_, err = FindFiles(path.Join(testPath, "a.mp4"), []string{"*.mp4"}, nil, false, "")
+	assert.NotNil(t, err)
+}
+
+func TestFileFingerprintShouldDetectTheContentChange(t *testing.T) {
+	testCleanupPath()
+	defer testCleanupPath()
+
+	for _, length := range []int{0, 10, 3 * 64 * 1024, 5 * 64 * 1024} {
+		filePath := path.Join(testPath, "video.mp4")
+		content := make([]byte, length)
+
+		assert.Nil(t, os.MkdirAll(testPath, 0770))
+		assert.Nil(t, os.WriteFile(filePath, content, 0660))
+
+		modTime := time.Unix(1700000000, 0)
+		assert.Nil(t, os.Chtimes(filePath, modTime, modTime))
+
+		a, err := FileFingerprint(filePath)
+		assert.Nil(t, err)
+		assert.Len(t, a, 40)
+
+		b, err := FileFingerprint(filePath)
+		assert.Nil(t, err)
+		assert.Equal(t, a, b)
+
+		if length > 0 {
+			content[length-1] = 0xFF
+			assert.Nil(t, os.WriteFile(filePath, content, 0660))
+			assert.Nil(t, os.Chtimes(filePath, modTime, modTime))
+
+			c, err := FileFingerprint(filePath)
+			assert.Nil(t, err)
+			assert.NotEqual(t, a, c)
+		}
+
+		assert.Nil(t, os.Chtimes(filePath, modTime, modTime.Add(time.Second)))
+
+		d, err := FileFingerprint(filePath)
+		assert.Nil(t, err)
+		assert.NotEqual(t, a, d)
+	}
+
+	_, err := FileFingerprint(path.Join(testPath, "missing.mp4"))
+	assert.NotNil(t, err)
+}
+
+func TestFileSetFingerprintShouldDetectTheFileSetChange(t *testing.T) {
+	testCleanupPath()
+	defer testCleanupPath()
+
+	assert.Nil(t, os.MkdirAll(testPath, 0770))
+
+	modTime := time.Unix(1700000000, 0)
+	paths := make([]string, 0, 3)
+
+	for index := range 3 {
+		filePath := path.Join(testPath, fmt.Sprintf("frame-%d.png", index))
+		assert.Nil(t, os.WriteFile(filePath, []byte{byte(index)}, 0660))
+		assert.Nil(t, os.Chtimes(filePath, modTime, modTime))
+
+		paths = append(paths, filePath)
+	}
+
+	a, err := FileSetFingerprint(paths)
+	assert.Nil(t, err)
+	assert.Len(t, a, 40)
+
+	b, err := FileSetFingerprint(paths)
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := FileSetFingerprint(paths[:2])
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, c)
+
+	d, err := FileSetFingerprint([]string{paths[1], paths[0], paths[2]})
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, d)
+
+	assert.Nil(t, os.WriteFile(paths[1], []byte{0xFF, 0xFF}, 0660))
+	assert.Nil(t, os.Chtimes(paths[1], modTime, modTime))
+
+	e, err := FileSetFingerprint(paths)
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, e)
+
+	_, err = FileSetFingerprint(nil)
+	assert.NotNil(t, err)
+
+	_, err = FileSetFingerprint([]string{path.Join(testPath, "missing.png")})
+	assert.NotNil(t, err)
+}
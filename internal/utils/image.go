@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image"
 	"image/draw"
+	"slices"
 )
 
 // TODO: Add tests
@@ -18,6 +19,64 @@ func CopyAsRgba(i image.Image) (*image.RGBA, error) {
 	return rgba, nil
 }
 
+// Blend the src image over the dst image using the lighten (per-channel maximum) blending mode. The result is stored in the dst image.
+func BlendLighten(dst, src *image.RGBA) error {
+	if dst == nil || src == nil {
+		return fmt.Errorf("utils: invalid image reference provided")
+	}
+
+	if dst.Bounds().Dx() != src.Bounds().Dx() || dst.Bounds().Dy() != src.Bounds().Dy() {
+		return fmt.Errorf("utils: source and destination images bounds missmatch")
+	}
+
+	for index := 0; index < len(dst.Pix); index += 1 {
+		if src.Pix[index] > dst.Pix[index] {
+			dst.Pix[index] = src.Pix[index]
+		}
+	}
+
+	return nil
+}
+
+// Create a new image where each pixel channel is the median of the corresponding pixel channels of the provided images.
+func MedianRgba(images []*image.RGBA) (*image.RGBA, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("utils: can not calculate the median of an empty image set")
+	}
+
+	for _, img := range images {
+		if img == nil {
+			return nil, fmt.Errorf("utils: invalid image reference provided")
+		}
+
+		if img.Bounds().Dx() != images[0].Bounds().Dx() || img.Bounds().Dy() != images[0].Bounds().Dy() {
+			return nil, fmt.Errorf("utils: the images bounds missmatch")
+		}
+	}
+
+	var (
+		median *image.RGBA = image.NewRGBA(image.Rect(0, 0, images[0].Bounds().Dx(), images[0].Bounds().Dy()))
+		values []uint8     = make([]uint8, len(images))
+		middle int         = len(images) / 2
+	)
+
+	for index := 0; index < len(median.Pix); index += 1 {
+		for imageIndex, img := range images {
+			values[imageIndex] = img.Pix[index]
+		}
+
+		slices.Sort(values)
+
+		if len(values)%2 == 0 {
+			median.Pix[index] = uint8((uint16(values[middle-1]) + uint16(values[middle]) + 1) / 2)
+		} else {
+			median.Pix[index] = values[middle]
+		}
+	}
+
+	return median, nil
+}
+
 func Otsu(i image.RGBA) float64 {
 	var (
 		histogram [256]int = [256]int{}
@@ -1 +1,68 @@
 package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlendLightenShouldNotBlendInvalidImages(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	b := image.NewRGBA(image.Rect(0, 0, 2, 3))
+
+	assert.NotNil(t, BlendLighten(nil, a))
+	assert.NotNil(t, BlendLighten(a, nil))
+	assert.NotNil(t, BlendLighten(a, b))
+}
+
+func TestBlendLightenShouldBlendPerChannelMaximum(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	dst.SetRGBA(0, 0, color.RGBA{10, 200, 30, 255})
+
+	src := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	src.SetRGBA(0, 0, color.RGBA{100, 20, 30, 255})
+
+	err := BlendLighten(dst, src)
+	assert.Nil(t, err)
+	assert.Equal(t, color.RGBA{100, 200, 30, 255}, dst.RGBAAt(0, 0))
+}
+
+func TestMedianRgbaShouldNotCalculateForInvalidImages(t *testing.T) {
+	_, err := MedianRgba([]*image.RGBA{})
+	assert.NotNil(t, err)
+
+	_, err = MedianRgba([]*image.RGBA{image.NewRGBA(image.Rect(0, 0, 1, 1)), nil})
+	assert.NotNil(t, err)
+
+	_, err = MedianRgba([]*image.RGBA{image.NewRGBA(image.Rect(0, 0, 1, 1)), image.NewRGBA(image.Rect(0, 0, 2, 1))})
+	assert.NotNil(t, err)
+}
+
+func TestMedianRgbaShouldCalculatePerChannelMedian(t *testing.T) {
+	cases := []struct {
+		Values   []uint8
+		Expected uint8
+	}{
+		{[]uint8{10}, 10},
+		{[]uint8{10, 20}, 15},
+		{[]uint8{30, 10, 20}, 20},
+		{[]uint8{255, 0, 0, 255}, 128},
+		{[]uint8{5, 250, 6, 7, 8}, 7},
+	}
+
+	for _, c := range cases {
+		images := make([]*image.RGBA, 0, len(c.Values))
+		for _, value := range c.Values {
+			img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+			img.SetRGBA(0, 0, color.RGBA{value, value, value, 255})
+
+			images = append(images, img)
+		}
+
+		median, err := MedianRgba(images)
+		assert.Nil(t, err)
+		assert.Equal(t, color.RGBA{c.Expected, c.Expected, c.Expected, 255}, median.RGBAAt(0, 0))
+	}
+}
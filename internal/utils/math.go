@@ -1,6 +1,9 @@
 package utils
 
-import "math"
+import (
+	"math"
+	"slices"
+)
 
 // Calculate the moving mean and standard deviation values of the provided set. The position paramter is the index of
 // the central subset element and the bias is the amount of "left" and "right" neighbours. Elements out of index are
@@ -41,6 +44,150 @@ func MovingMeanStdDev(x []float64, position, bias int) (float64, float64) {
 	return mean, stdDev
 }
 
+// Calculate the moving median and median absolute deviation values of the provided set. The position parameter is the
+// index of the central subset element and the bias is the amount of "left" and "right" neighbours. Elements out of
+// index are not taken under account.
+func MovingMedianMad(x []float64, position, bias int) (float64, float64) {
+	median, mad, _ := MovingMedianMadBuffered(x, position, bias, nil)
+	return median, mad
+}
+
+// Calculate the moving median and median absolute deviation values of the provided set using the provided buffer for the
+// intermediate values. The buffer is grown if needed and is returned in order to be reused by the following calls.
+func MovingMedianMadBuffered(x []float64, position, bias int, buffer []float64) (float64, float64, []float64) {
+	if len(x) == 0 {
+		panic("utils: can not calculate the median of an empty set")
+	}
+
+	if position >= len(x) {
+		panic("utils: the position is out of bounds of the value set")
+	}
+
+	from := MaxInt(0, position-bias)
+	to := MinInt(len(x), position+bias+1)
+
+	return MedianMadBuffered(x[from:to], buffer)
+}
+
+// Calculate the median and median absolute deviation values of the provided set. The provided set is not modified.
+// Panic if the value set is empty.
+func MedianMad(x []float64) (float64, float64) {
+	median, mad, _ := MedianMadBuffered(x, nil)
+	return median, mad
+}
+
+// Calculate the median and median absolute deviation values of the provided set using the provided buffer for the intermediate
+// values. The buffer is grown if needed and is returned in order to be reused by the following calls. The provided set is not
+// modified. Panic if the value set is empty.
+func MedianMadBuffered(x []float64, buffer []float64) (float64, float64, []float64) {
+	if len(x) == 0 {
+		panic("utils: can not calculate the median and median absolute deviation of an empty set")
+	}
+
+	values := append(buffer[:0], x...)
+	slices.Sort(values)
+
+	median := sortedMedian(values)
+
+	for index, value := range values {
+		values[index] = math.Abs(value - median)
+	}
+
+	slices.Sort(values)
+
+	mad := sortedMedian(values)
+
+	return median, mad, values
+}
+
+func sortedMedian(x []float64) float64 {
+	middle := len(x) / 2
+	if len(x)%2 == 0 {
+		return (x[middle-1] + x[middle]) / 2
+	}
+
+	return x[middle]
+}
+
+// Calculate the p-th percentile of the provided set using the linear interpolation between the closest ranks. The provided set
+// is not modified. Panic if the value set is empty or the percentile is not in the range from 0 to 100.
+func Percentile(x []float64, p float64) float64 {
+	if len(x) == 0 {
+		panic("utils: can not calculate the percentile of an empty set")
+	}
+
+	if p < 0 || p > 100 {
+		panic("utils: the percentile must be between zero and one hundred")
+	}
+
+	values := slices.Clone(x)
+	slices.Sort(values)
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return values[lower] + (rank-float64(lower))*(values[upper]-values[lower])
+}
+
+// Calculate the Otsu threshold of the provided set, which is splitting the histogram of the values with the given amount of bins
+// into two classes with the maximal between-class variance. The returned value is the upper edge of the last bin of the lower class.
+// Panic if the value set is empty or the amount of bins is lower than two.
+func OtsuThreshold(x []float64, bins int) float64 {
+	if len(x) == 0 {
+		panic("utils: can not calculate the otsu threshold of an empty set")
+	}
+
+	if bins < 2 {
+		panic("utils: the otsu threshold requires at least two histogram bins")
+	}
+
+	min, max := MinMax(x)
+	if min == max {
+		return max
+	}
+
+	var (
+		histogram []int   = make([]int, bins)
+		binWidth  float64 = (max - min) / float64(bins)
+	)
+
+	for _, value := range x {
+		histogram[MinInt(bins-1, int((value-min)/binWidth))] += 1
+	}
+
+	var (
+		total      float64 = float64(len(x))
+		sum        float64 = 0
+		lowerSum   float64 = 0
+		lowerCount float64 = 0
+		bestBin    int     = 0
+		bestVar    float64 = -1
+	)
+
+	for bin, count := range histogram {
+		sum += float64(bin * count)
+	}
+
+	for bin, count := range histogram[:bins-1] {
+		lowerCount += float64(count)
+		lowerSum += float64(bin * count)
+
+		upperCount := total - lowerCount
+		if lowerCount == 0 || upperCount == 0 {
+			continue
+		}
+
+		meanDiff := lowerSum/lowerCount - (sum-lowerSum)/upperCount
+		if betweenVar := lowerCount * upperCount * meanDiff * meanDiff; betweenVar > bestVar {
+			bestVar = betweenVar
+			bestBin = bin
+		}
+	}
+
+	return min + float64(bestBin+1)*binWidth
+}
+
 // Calculate the moving mean and standard deviation in a incremental way. Provide the push and pop values, current dataset mean
 // stddev and length. Other functions MovingMeanStd functions here are taking the position of the calculation center point and
 // taking half of the bias left and right. This function takes a bias amount of left values.
@@ -79,6 +226,22 @@ func MovingMeanStdDevInc(value, discardValue, mean, stdDev float64, length, bias
 	return meanNext, stdDevNext
 }
 
+// Calculate the exponentially weighted moving mean and standard deviation in a incremental way using the previous
+// mean, standard deviation and the smoothing factor alpha which must be in the (0, 1] range.
+func ExponentialMeanStdDevInc(value, mean, stdDev, alpha float64) (float64, float64) {
+	if alpha <= 0 || alpha > 1 {
+		panic("utils: the exponential smoothing factor must be in the (0, 1] range")
+	}
+
+	diff := value - mean
+	inc := alpha * diff
+
+	meanNext := mean + inc
+	varianceNext := (1 - alpha) * (stdDev*stdDev + diff*inc)
+
+	return meanNext, math.Sqrt(math.Max(0, varianceNext))
+}
+
 // Calculate the mean and standard deviation values of the provided set. Panic if the value set is empty.
 func MeanStdDev(x []float64) (float64, float64) {
 	if len(x) == 0 {
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -275,3 +276,170 @@ func TestDivShouldDivAndUseFallback(t *testing.T) {
 		assert.Equal(t, c.expected, actual)
 	}
 }
+
+func TestMedianMadShouldPanicForEmptyValueSet(t *testing.T) {
+	assert.Panics(t, func() {
+		MedianMad([]float64{})
+	})
+}
+
+func TestMedianMadShouldCalculateMedianAndMadForValueSet(t *testing.T) {
+	cases := []struct {
+		values         []float64
+		expectedMedian float64
+		expectedMad    float64
+	}{
+		{[]float64{1}, 1, 0},
+		{[]float64{1, 2}, 1.5, 0.5},
+		{[]float64{1, 1, 2, 2, 4, 6, 9}, 2, 1},
+		{[]float64{9, 1, 6, 2, 4, 2, 1}, 2, 1},
+		{[]float64{0.1, 0.1, 0.1, 0.9}, 0.1, 0},
+	}
+
+	const delta float64 = 1e-9
+
+	for _, c := range cases {
+		values := slices.Clone(c.values)
+		actualMedian, actualMad := MedianMad(values)
+
+		assert.InDelta(t, c.expectedMedian, actualMedian, delta)
+		assert.InDelta(t, c.expectedMad, actualMad, delta)
+		assert.Equal(t, c.values, values)
+	}
+}
+
+func TestMovingMedianMadShouldPanicForPositionOutOfBounds(t *testing.T) {
+	assert.Panics(t, func() {
+		MovingMedianMad([]float64{1, 2}, 2, 1)
+	})
+}
+
+func TestMovingMedianMadShouldCalculateMedianAndMadForValueSet(t *testing.T) {
+	values := []float64{1, 2, 9, 3, 4}
+
+	cases := []struct {
+		position       int
+		bias           int
+		expectedMedian float64
+		expectedMad    float64
+	}{
+		{0, 1, 1.5, 0.5},
+		{1, 1, 2, 1},
+		{2, 1, 3, 1},
+		{4, 1, 3.5, 0.5},
+		{2, 2, 3, 1},
+		{2, 0, 9, 0},
+	}
+
+	const delta float64 = 1e-9
+
+	for _, c := range cases {
+		actualMedian, actualMad := MovingMedianMad(values, c.position, c.bias)
+
+		assert.InDelta(t, c.expectedMedian, actualMedian, delta)
+		assert.InDelta(t, c.expectedMad, actualMad, delta)
+	}
+}
+
+func TestMovingMedianMadBufferedShouldReuseTheBuffer(t *testing.T) {
+	values := []float64{1, 2, 9, 3, 4}
+	buffer := make([]float64, 0, 3)
+
+	const delta float64 = 1e-9
+
+	for position := range values {
+		expectedMedian, expectedMad := MovingMedianMad(values, position, 1)
+
+		var actualMedian, actualMad float64
+		actualMedian, actualMad, buffer = MovingMedianMadBuffered(values, position, 1, buffer)
+
+		assert.InDelta(t, expectedMedian, actualMedian, delta)
+		assert.InDelta(t, expectedMad, actualMad, delta)
+		assert.Equal(t, 3, cap(buffer))
+	}
+
+	assert.Equal(t, []float64{1, 2, 9, 3, 4}, values)
+}
+
+func TestExponentialMeanStdDevIncShouldPanicForInvalidAlpha(t *testing.T) {
+	for _, alpha := range []float64{0, -0.5, 1.5} {
+		assert.Panics(t, func() {
+			ExponentialMeanStdDevInc(1, 0, 0, alpha)
+		})
+	}
+}
+
+func TestExponentialMeanStdDevIncShouldCalculateMeanAndStdDevForValueSet(t *testing.T) {
+	const delta float64 = 1e-9
+
+	mean, stdDev := ExponentialMeanStdDevInc(5, 5, 0, 0.5)
+	assert.InDelta(t, 5.0, mean, delta)
+	assert.InDelta(t, 0.0, stdDev, delta)
+
+	mean, stdDev = ExponentialMeanStdDevInc(7, mean, stdDev, 0.5)
+	assert.InDelta(t, 6.0, mean, delta)
+	assert.InDelta(t, 1.0, stdDev, delta)
+
+	mean, stdDev = ExponentialMeanStdDevInc(3, mean, stdDev, 1.0)
+	assert.InDelta(t, 3.0, mean, delta)
+	assert.InDelta(t, 0.0, stdDev, delta)
+}
+
+func TestPercentileShouldPanicForInvalidParams(t *testing.T) {
+	assert.Panics(t, func() {
+		Percentile([]float64{}, 50)
+	})
+
+	assert.Panics(t, func() {
+		Percentile([]float64{1}, -1)
+	})
+
+	assert.Panics(t, func() {
+		Percentile([]float64{1}, 101)
+	})
+}
+
+func TestPercentileShouldCalculatePercentileForValueSet(t *testing.T) {
+	cases := []struct {
+		values     []float64
+		percentile float64
+		expected   float64
+	}{
+		{[]float64{1}, 50, 1},
+		{[]float64{1, 2, 3, 4, 5}, 0, 1},
+		{[]float64{1, 2, 3, 4, 5}, 50, 3},
+		{[]float64{1, 2, 3, 4, 5}, 100, 5},
+		{[]float64{5, 1, 4, 2, 3}, 75, 4},
+		{[]float64{1, 2}, 25, 1.25},
+	}
+
+	const delta float64 = 1e-9
+
+	for _, c := range cases {
+		values := slices.Clone(c.values)
+		actual := Percentile(values, c.percentile)
+
+		assert.InDelta(t, c.expected, actual, delta)
+		assert.Equal(t, c.values, values)
+	}
+}
+
+func TestOtsuThresholdShouldPanicForInvalidParams(t *testing.T) {
+	assert.Panics(t, func() {
+		OtsuThreshold([]float64{}, 10)
+	})
+
+	assert.Panics(t, func() {
+		OtsuThreshold([]float64{1, 2}, 1)
+	})
+}
+
+func TestOtsuThresholdShouldSplitTheValueSet(t *testing.T) {
+	values := []float64{0.01, 0.02, 0.02, 0.03, 0.01, 0.02, 0.03, 0.02, 0.5, 0.55, 0.6}
+
+	threshold := OtsuThreshold(values, 100)
+
+	assert.Greater(t, threshold, 0.03)
+	assert.LessOrEqual(t, threshold, 0.5)
+	assert.Equal(t, 0.4, OtsuThreshold([]float64{0.4, 0.4}, 10))
+}
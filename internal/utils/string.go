@@ -2,15 +2,18 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
 )
 
 const (
-	rangeExpressionSeparatorToken  string = ","
-	rangeExpressionSeriesToken     string = "-"
-	boundsExpressionSeparatorToken string = ":"
+	rangeExpressionSeparatorToken    string = ","
+	rangeExpressionSeriesToken       string = "-"
+	boundsExpressionSeparatorToken   string = ":"
+	positionExpressionTimeToken      string = ":"
+	positionExpressionSecondsPostfix string = "s"
 )
 
 func IsRangeExpressionValid(expr string) bool {
@@ -128,3 +131,80 @@ func parseBoundsExpressionToken(token string, min int) (int, error) {
 
 	return value, nil
 }
+
+func IsPositionExpressionValid(expr string) bool {
+	_, _, err := ParsePositionExpression(expr)
+	return err == nil
+}
+
+// Parse the video position expression. The position can be specified as a frame ordinal number (Example: 1500), amount
+// of seconds (Example: 90.5s) or a timestamp (Example: 42:10 or 00:42:10.500). The boolean return value indicates if the
+// parsed value is a frame ordinal number. Otherwise the value is a position in seconds.
+func ParsePositionExpression(expr string) (float64, bool, error) {
+	expr = strings.TrimSpace(expr)
+	if len(expr) == 0 {
+		return 0, false, fmt.Errorf("utils: the position expression can not be empty")
+	}
+
+	if strings.Contains(expr, positionExpressionTimeToken) {
+		seconds, err := parsePositionExpressionTimestamp(expr)
+		if err != nil {
+			return 0, false, fmt.Errorf("utils: failed to parse the position expression timestamp: %w", err)
+		}
+
+		return seconds, false, nil
+	}
+
+	if secondsToken, ok := strings.CutSuffix(expr, positionExpressionSecondsPostfix); ok {
+		seconds, err := strconv.ParseFloat(secondsToken, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("utils: failed to parse the position expression seconds: %w", err)
+		}
+
+		if seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return 0, false, fmt.Errorf("utils: the position expression seconds are out of range")
+		}
+
+		return seconds, false, nil
+	}
+
+	frame, err := strconv.ParseInt(expr, 10, 0)
+	if err != nil {
+		return 0, false, fmt.Errorf("utils: failed to parse the position expression frame number: %w", err)
+	}
+
+	if frame < 1 {
+		return 0, false, fmt.Errorf("utils: the position expression frame number must be greater than zero")
+	}
+
+	return float64(frame), true, nil
+}
+
+func parsePositionExpressionTimestamp(expr string) (float64, error) {
+	tokens := strings.Split(expr, positionExpressionTimeToken)
+	if len(tokens) < 2 || len(tokens) > 3 {
+		return 0, fmt.Errorf("utils: invalid timestamp format")
+	}
+
+	seconds, err := strconv.ParseFloat(tokens[len(tokens)-1], 64)
+	if err != nil || seconds < 0 || seconds >= 60 {
+		return 0, fmt.Errorf("utils: invalid timestamp seconds value")
+	}
+
+	multiplier := 60.0
+	for index := len(tokens) - 2; index >= 0; index -= 1 {
+		value, err := strconv.ParseUint(tokens[index], 10, 0)
+		if err != nil {
+			return 0, fmt.Errorf("utils: invalid timestamp minutes or hours value: %w", err)
+		}
+
+		if index == len(tokens)-2 && len(tokens) == 3 && value >= 60 {
+			return 0, fmt.Errorf("utils: invalid timestamp minutes value")
+		}
+
+		seconds += float64(value) * multiplier
+		multiplier *= 60
+	}
+
+	return seconds, nil
+}
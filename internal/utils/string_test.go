@@ -44,3 +44,40 @@ func TestParseBoundsExpressionShouldCorrectlyParseExpression(t *testing.T) {
 		assert.Equal(t, h, expected.H)
 	}
 }
+
+func TestParsePositionExpressionShouldCorrectlyParseExpression(t *testing.T) {
+	cases := map[string]struct {
+		Value float64
+		Frame bool
+	}{
+		"1":            {1, true},
+		"1500":         {1500, true},
+		"90s":          {90, false},
+		"90.5s":        {90.5, false},
+		"42:10":        {2530, false},
+		"00:42:10":     {2530, false},
+		"01:05:00.250": {3900.25, false},
+		"120:00":       {7200, false},
+	}
+
+	for expression, expected := range cases {
+		assert.True(t, IsPositionExpressionValid(expression))
+
+		value, frame, err := ParsePositionExpression(expression)
+
+		assert.Nil(t, err)
+		assert.InDelta(t, expected.Value, value, 1e-9)
+		assert.Equal(t, expected.Frame, frame)
+	}
+}
+
+func TestParsePositionExpressionShouldNotParseInvalidExpression(t *testing.T) {
+	cases := []string{"", "0", "-5", "abc", "-1s", "1:2:3:4", "00:60:00", "00:00:60", "1.5", ":30", "1h"}
+
+	for _, expression := range cases {
+		assert.False(t, IsPositionExpressionValid(expression))
+
+		_, _, err := ParsePositionExpression(expression)
+		assert.NotNil(t, err)
+	}
+}
@@ -0,0 +1,68 @@
+package video
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// Cut a clip of the video file specified by the input path, starting at the from timestamp and ending at the to timestamp (both
+// in seconds) and store it at the output path. The streams are copied without re-encoding, therefore the actual clip bounds
+// are aligned to the nearest keyframes of the source video. If the video stream index is specified, only the given video stream
+// and the audio streams are copied, otherwise all streams are copied.
+func CutVideoClip(inputPath, outputPath string, streamIndex int, from, to float64) error {
+	if !utils.FileExists(inputPath) {
+		return fmt.Errorf("video: the video file specified by the path does not exist")
+	}
+
+	if len(outputPath) == 0 {
+		return fmt.Errorf("video: invalid clip output path specified")
+	}
+
+	if from < 0 || to <= from {
+		return fmt.Errorf("video: invalid clip time range specified")
+	}
+
+	if ok, err := AreBinariesAvailable(); !ok && err != nil {
+		return fmt.Errorf("video: the required video processing binaries are not available: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0770); err != nil {
+		return fmt.Errorf("video: failed to create the clip output directory tree: %w", err)
+	}
+
+	cmd := exec.Command(ffmpegBinaryName, videoClipArgs(inputPath, outputPath, streamIndex, from, to)...)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("video: failed to cut the video clip: %w", err)
+	}
+
+	return nil
+}
+
+func videoClipArgs(inputPath, outputPath string, streamIndex int, from, to float64) []string {
+	args := make([]string, 0, 24)
+	args = append(args, "-loglevel", "quiet")
+	args = append(args, "-hide_banner")
+	args = append(args, "-ss", strconv.FormatFloat(from, 'f', 3, 64))
+	args = append(args, "-i", inputPath)
+	args = append(args, "-t", strconv.FormatFloat(to-from, 'f', 3, 64))
+
+	if streamIndex >= 0 {
+		args = append(args, "-map", fmt.Sprintf("0:v:%d", streamIndex))
+		args = append(args, "-map", "0:a?")
+	} else {
+		args = append(args, "-map", "0")
+	}
+
+	args = append(args, "-c", "copy")
+	args = append(args, "-avoid_negative_ts", "make_zero")
+	args = append(args, "-y")
+	args = append(args, outputPath)
+
+	return args
+}
@@ -0,0 +1,61 @@
+package video
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVideoClipArgsShouldMapTheSelectedVideoStream(t *testing.T) {
+	args := videoClipArgs("video.mkv", "clip.mkv", -1, 1.5, 4)
+	assert.Equal(t, []string{
+		"-loglevel", "quiet",
+		"-hide_banner",
+		"-ss", "1.500",
+		"-i", "video.mkv",
+		"-t", "2.500",
+		"-map", "0",
+		"-c", "copy",
+		"-avoid_negative_ts", "make_zero",
+		"-y",
+		"clip.mkv",
+	}, args)
+
+	args = videoClipArgs("video.mkv", "clip.mkv", 1, 1.5, 4)
+	assert.Equal(t, []string{
+		"-loglevel", "quiet",
+		"-hide_banner",
+		"-ss", "1.500",
+		"-i", "video.mkv",
+		"-t", "2.500",
+		"-map", "0:v:1",
+		"-map", "0:a?",
+		"-c", "copy",
+		"-avoid_negative_ts", "make_zero",
+		"-y",
+		"clip.mkv",
+	}, args)
+}
+
+func TestCutVideoClipShouldValidateTheArguments(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "video.mp4")
+	outputPath := filepath.Join(t.TempDir(), "clips", "clip.mp4")
+
+	err := CutVideoClip(inputPath, outputPath, -1, 1, 2)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, os.WriteFile(inputPath, []byte("video content"), 0660))
+
+	err = CutVideoClip(inputPath, "", -1, 1, 2)
+	assert.NotNil(t, err)
+
+	for _, r := range [][2]float64{{-1, 2}, {2, 2}, {3, 2}} {
+		err = CutVideoClip(inputPath, outputPath, -1, r[0], r[1])
+		assert.NotNil(t, err)
+	}
+
+	_, err = os.Stat(filepath.Dir(outputPath))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,164 @@
+package video
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The display orientation of the video represented as a clockwise rotation in degrees which is followed by an optional horizontal
+// flip. All combinations of right angle rotations and flips can be represented this way. The coded frames are transformed using
+// the orientation, therefore the dimensions and bounds expressions are always relative to the display orientation.
+type Orientation struct {
+	Rotation       int
+	HorizontalFlip bool
+}
+
+// Return a value indicating if the width and height of the coded frames are swapped in the display orientation.
+func (o Orientation) IsTransposed() bool {
+	return o.Rotation == 90 || o.Rotation == 270
+}
+
+// Return a value indicating if the coded frames require a transformation to match the display orientation.
+func (o Orientation) IsTransformed() bool {
+	return o.Rotation != 0 || o.HorizontalFlip
+}
+
+// Get the FFmpeg filters transforming the coded frames to the display orientation.
+func (o Orientation) Filters() []string {
+	switch o.Rotation {
+	case 90:
+		if o.HorizontalFlip {
+			return []string{"transpose=cclock_flip"}
+		}
+
+		return []string{"transpose=clock"}
+	case 180:
+		if o.HorizontalFlip {
+			return []string{"vflip"}
+		}
+
+		return []string{"hflip", "vflip"}
+	case 270:
+		if o.HorizontalFlip {
+			return []string{"transpose=clock_flip"}
+		}
+
+		return []string{"transpose=cclock"}
+	default:
+		if o.HorizontalFlip {
+			return []string{"hflip"}
+		}
+
+		return []string{}
+	}
+}
+
+func (o Orientation) String() string {
+	if o.HorizontalFlip {
+		return fmt.Sprintf("%d° (flipped)", o.Rotation)
+	}
+
+	return fmt.Sprintf("%d°", o.Rotation)
+}
+
+// Normalize the clockwise rotation in degrees to the nearest right angle in the range from 0 to 270.
+func normalizeRotation(degrees float64) int {
+	rotation := int(math.Round(degrees/90)) * 90 % 360
+	if rotation < 0 {
+		rotation += 360
+	}
+
+	return rotation
+}
+
+// Create the orientation from the legacy rotate metadata tag which is specifying the clockwise rotation in degrees.
+func orientationFromRotateTag(value string) (Orientation, error) {
+	degrees, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return Orientation{}, fmt.Errorf("video: failed to parse the rotate tag value: %w", err)
+	}
+
+	return Orientation{Rotation: normalizeRotation(degrees)}, nil
+}
+
+// Create the orientation from the display matrix side data rotation which is specifying the counterclockwise rotation in degrees.
+func orientationFromRotation(value string) (Orientation, error) {
+	degrees, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return Orientation{}, fmt.Errorf("video: failed to parse the side data rotation value: %w", err)
+	}
+
+	return Orientation{Rotation: normalizeRotation(-degrees)}, nil
+}
+
+// Create the orientation from the 3x3 display matrix, where the first two columns are 16.16 fixed point values. The rotation and
+// flips are resolved in the same way as the FFmpeg automatic rotation, therefore the result is matching the FFmpeg playback.
+func orientationFromDisplayMatrix(matrix [9]int32) (Orientation, error) {
+	var (
+		a  float64 = float64(matrix[0])
+		b  float64 = float64(matrix[1])
+		c  float64 = float64(matrix[3])
+		d  float64 = float64(matrix[4])
+		sx float64 = math.Hypot(a, c)
+		sy float64 = math.Hypot(b, d)
+	)
+
+	if sx == 0 || sy == 0 {
+		return Orientation{}, fmt.Errorf("video: the display matrix is degenerate")
+	}
+
+	rotation := normalizeRotation(math.Atan2(b/sy, a/sx) * 180 / math.Pi)
+
+	switch rotation {
+	case 90:
+		return Orientation{Rotation: 90, HorizontalFlip: matrix[3] > 0}, nil
+	case 180:
+		hflip, vflip := matrix[0] < 0, matrix[4] < 0
+		switch {
+		case hflip && vflip:
+			return Orientation{Rotation: 180}, nil
+		case hflip:
+			return Orientation{Rotation: 0, HorizontalFlip: true}, nil
+		case vflip:
+			return Orientation{Rotation: 180, HorizontalFlip: true}, nil
+		default:
+			return Orientation{}, nil
+		}
+	case 270:
+		return Orientation{Rotation: 270, HorizontalFlip: matrix[3] < 0}, nil
+	default:
+		if matrix[4] < 0 {
+			return Orientation{Rotation: 180, HorizontalFlip: true}, nil
+		}
+
+		return Orientation{}, nil
+	}
+}
+
+// Parse a single row of the display matrix printed by the probe in the format of a hexadecimal row offset followed by three values
+// (Example: 00000001:       -65536           0           0). The boolean return value indicates if the line is a display matrix row.
+func parseDisplayMatrixRow(line string) (int, [3]int32, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 || !strings.HasSuffix(fields[0], ":") {
+		return 0, [3]int32{}, false
+	}
+
+	row, err := strconv.ParseUint(strings.TrimSuffix(fields[0], ":"), 16, 8)
+	if err != nil || row > 2 {
+		return 0, [3]int32{}, false
+	}
+
+	var values [3]int32
+	for index := range values {
+		value, err := strconv.ParseInt(fields[index+1], 10, 32)
+		if err != nil {
+			return 0, [3]int32{}, false
+		}
+
+		values[index] = int32(value)
+	}
+
+	return int(row), values, true
+}
@@ -0,0 +1,93 @@
+package video
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrientationFromDisplayMatrixShouldResolveRotationAndFlips(t *testing.T) {
+	cases := []struct {
+		Matrix   [9]int32
+		Expected Orientation
+	}{
+		{[9]int32{65536, 0, 0, 0, 65536, 0, 0, 0, 1073741824}, Orientation{Rotation: 0}},
+		{[9]int32{0, 65536, 0, -65536, 0, 0, 0, 0, 1073741824}, Orientation{Rotation: 90}},
+		{[9]int32{-65536, 0, 0, 0, -65536, 0, 0, 0, 1073741824}, Orientation{Rotation: 180}},
+		{[9]int32{0, -65536, 0, 65536, 0, 0, 0, 0, 1073741824}, Orientation{Rotation: 270}},
+		{[9]int32{-65536, 0, 0, 0, 65536, 0, 0, 0, 1073741824}, Orientation{Rotation: 0, HorizontalFlip: true}},
+		{[9]int32{65536, 0, 0, 0, -65536, 0, 0, 0, 1073741824}, Orientation{Rotation: 180, HorizontalFlip: true}},
+		{[9]int32{0, 65536, 0, 65536, 0, 0, 0, 0, 1073741824}, Orientation{Rotation: 90, HorizontalFlip: true}},
+	}
+
+	for _, c := range cases {
+		actual, err := orientationFromDisplayMatrix(c.Matrix)
+		assert.Nil(t, err)
+		assert.Equal(t, c.Expected, actual)
+	}
+
+	_, err := orientationFromDisplayMatrix([9]int32{})
+	assert.NotNil(t, err)
+}
+
+func TestOrientationShouldNormalizeRotations(t *testing.T) {
+	cases := map[string]int{
+		"0":    0,
+		"90":   270,
+		"-90":  90,
+		"180":  180,
+		"-180": 180,
+		"270":  90,
+		"-270": 270,
+		"450":  270,
+	}
+
+	for value, expected := range cases {
+		actual, err := orientationFromRotation(value)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual.Rotation)
+	}
+
+	actual, err := orientationFromRotateTag("-90")
+	assert.Nil(t, err)
+	assert.Equal(t, 270, actual.Rotation)
+
+	_, err = orientationFromRotateTag("invalid")
+	assert.NotNil(t, err)
+}
+
+func TestOrientationFiltersShouldMatchTheOrientation(t *testing.T) {
+	assert.Empty(t, Orientation{}.Filters())
+	assert.Equal(t, []string{"transpose=clock"}, Orientation{Rotation: 90}.Filters())
+	assert.Equal(t, []string{"hflip", "vflip"}, Orientation{Rotation: 180}.Filters())
+	assert.Equal(t, []string{"transpose=cclock"}, Orientation{Rotation: 270}.Filters())
+	assert.Equal(t, []string{"hflip"}, Orientation{HorizontalFlip: true}.Filters())
+	assert.Equal(t, []string{"transpose=cclock_flip"}, Orientation{Rotation: 90, HorizontalFlip: true}.Filters())
+	assert.Equal(t, []string{"vflip"}, Orientation{Rotation: 180, HorizontalFlip: true}.Filters())
+	assert.Equal(t, []string{"transpose=clock_flip"}, Orientation{Rotation: 270, HorizontalFlip: true}.Filters())
+}
+
+func TestParseProbeResultShouldParseDisplayMatrixSideData(t *testing.T) {
+	stdout := []byte("[STREAM]\nwidth=1920\nheight=1080\nnb_frames=300\nr_frame_rate=30/1\navg_frame_rate=30/1\n" +
+		"[SIDE_DATA]\nside_data_type=Display Matrix\ndisplaymatrix=\n" +
+		"00000000:            0       65536           0\n" +
+		"00000001:       -65536           0           0\n" +
+		"00000002:            0           0  1073741824\n" +
+		"\nrotation=-90\n[/SIDE_DATA]\n[/STREAM]\n[FORMAT]\nduration=10.000000\n[/FORMAT]\n")
+
+	probe, err := parseProbeResult(stdout, singleStreamStrategy)
+	assert.Nil(t, err)
+	assert.Equal(t, Orientation{Rotation: 90}, probe.Orientation)
+	assert.Equal(t, 1080, probe.Width)
+	assert.Equal(t, 1920, probe.Height)
+}
+
+func TestParseProbeResultShouldParseLegacyRotateTag(t *testing.T) {
+	stdout := []byte("[STREAM]\nwidth=1920\nheight=1080\nnb_frames=300\nr_frame_rate=30/1\nTAG:rotate=180\n[/STREAM]\n[FORMAT]\nduration=10.000000\n[/FORMAT]\n")
+
+	probe, err := parseProbeResult(stdout, singleStreamStrategy)
+	assert.Nil(t, err)
+	assert.Equal(t, Orientation{Rotation: 180}, probe.Orientation)
+	assert.Equal(t, 1920, probe.Width)
+	assert.Equal(t, 1080, probe.Height)
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
 )
@@ -32,20 +34,36 @@ const (
 	notApplicable     probeToken = "N/A"
 )
 
+const (
+	// The maximal relative difference between the real and average frame rate of a constant frame rate video
+	frameRateTolerance float64 = 0.01
+	// The maximal relative difference between a frame duration and the median frame duration of a constant frame rate video
+	frameDurationTolerance float64 = 0.1
+	// The maximal fraction of frames with an irregular duration in a constant frame rate video
+	frameDurationOutliersTolerance float64 = 0.01
+)
+
 type VideoProbe struct {
-	Width    int
-	Height   int
-	Duration float64
-	Frames   int
-	Fps      float64
+	Width             int
+	Height            int
+	Duration          float64
+	Frames            int
+	Fps               float64
+	AvgFps            float64
+	VariableFrameRate bool
+	Orientation       Orientation
+	CreationTime      time.Time
+	StreamIndex       int
 }
 
-func probeVideoFile(path string) (VideoProbe, error) {
+// Probe the video file stream specified by the 0-based index of the video streams. A negative index indicates that the video file
+// must contain a single video stream.
+func probeVideoFile(path string, streamIndex int) (VideoProbe, error) {
 	if !utils.FileExists(path) {
 		return VideoProbe{}, fmt.Errorf("video: the probe target video file does not exist")
 	}
 
-	return probeVideo(path, singleStreamStrategy)
+	return probeVideo(path, videoStreamSpecifier(streamIndex), singleStreamStrategy)
 }
 
 func probeVideoStream(url string) (VideoProbe, error) {
@@ -53,16 +71,44 @@ func probeVideoStream(url string) (VideoProbe, error) {
 		return VideoProbe{}, fmt.Errorf("video: the probe target video stream url format is invalid")
 	}
 
-	return probeVideo(url, firstStreamStrategy)
+	return probeVideo(url, "v", firstStreamStrategy)
 }
 
-func probeVideo(resource string, strategy streamStrategy) (VideoProbe, error) {
+// Probe the video file or the video stream specified by the url. The stream index is the 0-based index of the video streams and a
+// negative index indicates that a video file must contain a single video stream or that the first video stream of the url is used.
+func ProbeVideo(resource string, streamIndex int) (VideoProbe, error) {
+	if ok, err := AreBinariesAvailable(); !ok && err != nil {
+		return VideoProbe{}, fmt.Errorf("video: the required video processing binaries are not available: %w", err)
+	}
+
+	if utils.IsValidUrl(resource) {
+		if streamIndex < 0 {
+			return probeVideoStream(resource)
+		}
+
+		return probeVideo(resource, videoStreamSpecifier(streamIndex), firstStreamStrategy)
+	}
+
+	return probeVideoFile(resource, streamIndex)
+}
+
+// Get the FFmpeg stream specifier of the video stream specified by the 0-based index of the video streams. A negative index
+// results in a specifier matching all video streams.
+func videoStreamSpecifier(streamIndex int) string {
+	if streamIndex < 0 {
+		return "v"
+	}
+
+	return fmt.Sprintf("v:%d", streamIndex)
+}
+
+func probeVideo(resource, streamSpecifier string, strategy streamStrategy) (VideoProbe, error) {
 	cmd := exec.Command(
 		ffprobeBinaryName,
-		"-select_streams", "v",
+		"-select_streams", streamSpecifier,
 		"-loglevel", "quiet",
-		"-show_entries", "stream=width,height,rotation,nb_frames,r_frame_rate",
-		"-show_entries", "format=duration",
+		"-show_entries", "stream=width,height,nb_frames,r_frame_rate,avg_frame_rate:stream_tags=rotate:stream_side_data=displaymatrix,rotation:stream_disposition=attached_pic",
+		"-show_entries", "format=duration:format_tags=creation_time",
 		"-of", "default=noprint_wrappers=0:nokey=0",
 		resource,
 	)
@@ -80,6 +126,8 @@ func probeVideo(resource string, strategy streamStrategy) (VideoProbe, error) {
 	return result, nil
 }
 
+// Parse the video probe result. The attached pictures (Example: cover art) are skipped using the single stream strategy, therefore
+// the index of the probed stream among the selected streams is stored in the probe result.
 func parseProbeResult(stdout []byte, strategy streamStrategy) (VideoProbe, error) {
 	reader := strings.NewReader(string(stdout))
 	scanner := bufio.NewScanner(reader)
@@ -87,13 +135,30 @@ func parseProbeResult(stdout []byte, strategy streamStrategy) (VideoProbe, error
 
 	var (
 		probe              VideoProbe = VideoProbe{}
-		stateRotate        bool       = false
 		stateStreamCount   int        = 0
+		stateStreamIndex   int        = 0
 		stateStreamParity  int        = 0
 		stateFormatParity  int        = 0
 		stateProgramParity int        = 0
 	)
 
+	var (
+		rotateTag          *Orientation = nil
+		sideDataRotation   *Orientation = nil
+		displayMatrix      [9]int32
+		displayMatrixRows  int  = 0
+		stateDisplayMatrix bool = false
+	)
+
+	var (
+		attachedPicture  bool       = false
+		streamSnapshot   VideoProbe = VideoProbe{}
+		rotateSnapshot   *Orientation
+		rotationSnapshot *Orientation
+		matrixSnapshot   [9]int32
+		rowsSnapshot     int
+	)
+
 	var (
 		key   string
 		value string
@@ -104,28 +169,40 @@ scanning:
 		switch scanner.Text() {
 		case string(tokenStreamOpen):
 			{
-				switch strategy {
-				case singleStreamStrategy:
-					{
-						if stateStreamCount > 0 {
-							return VideoProbe{}, fmt.Errorf("video: video files containing multiple video streams are not supported")
-						}
-					}
-				case firstStreamStrategy:
-					{
-						if stateStreamCount == 1 && stateStreamParity == 0 {
-							break scanning
-						}
-					}
+				if strategy == firstStreamStrategy && stateStreamCount == 1 && stateStreamParity == 0 {
+					break scanning
 				}
 
-				stateStreamCount += 1
+				// NOTE: The stream values are stored in order to be restored if the stream is a attached picture
+				attachedPicture = false
+				streamSnapshot = probe
+				rotateSnapshot, rotationSnapshot = rotateTag, sideDataRotation
+				matrixSnapshot, rowsSnapshot = displayMatrix, displayMatrixRows
+
 				stateStreamParity += 1
 				continue
 			}
 		case string(tokenStreamClose):
 			{
 				stateStreamParity -= 1
+
+				if strategy == singleStreamStrategy && attachedPicture {
+					probe.Width, probe.Height = streamSnapshot.Width, streamSnapshot.Height
+					probe.Frames, probe.Fps, probe.AvgFps = streamSnapshot.Frames, streamSnapshot.Fps, streamSnapshot.AvgFps
+					rotateTag, sideDataRotation = rotateSnapshot, rotationSnapshot
+					displayMatrix, displayMatrixRows = matrixSnapshot, rowsSnapshot
+
+					stateStreamIndex += 1
+					continue
+				}
+
+				if strategy == singleStreamStrategy && stateStreamCount > 0 {
+					return VideoProbe{}, fmt.Errorf("video: the video file contains multiple video streams and the video stream index must be specified")
+				}
+
+				probe.StreamIndex = stateStreamIndex
+				stateStreamCount += 1
+				stateStreamIndex += 1
 				continue
 			}
 		case string(tokenFormatOpen):
@@ -151,7 +228,25 @@ scanning:
 		default:
 		}
 
-		if textParts := strings.Split(scanner.Text(), "="); len(textParts) != 2 {
+		// NOTE: The display matrix side data value is printed as multiple rows following the key line
+		if stateDisplayMatrix {
+			if row, values, ok := parseDisplayMatrixRow(scanner.Text()); ok {
+				copy(displayMatrix[row*3:row*3+3], values[:])
+				displayMatrixRows += 1
+				continue
+			}
+
+			stateDisplayMatrix = false
+		}
+
+		if text := strings.TrimSpace(scanner.Text()); len(text) == 0 {
+			continue
+		} else if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			// NOTE: Skipping the nested sections (Example: [SIDE_DATA]), the entries of the sections are parsed as regular entries
+			continue
+		}
+
+		if textParts := strings.SplitN(scanner.Text(), "=", 2); len(textParts) != 2 {
 			return VideoProbe{}, fmt.Errorf("video: failed to parse the probe result due to invalid result format")
 		} else {
 			key = textParts[0]
@@ -203,39 +298,62 @@ scanning:
 			}
 		case "r_frame_rate":
 			{
-				fpsParts := strings.Split(value, "/")
-				if len(fpsParts) != 2 {
-					return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video frame rate due to invalid format")
+				if v, err := parseProbeFrameRate(value); err != nil {
+					return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video frame rate: %w", err)
+				} else {
+					probe.Fps = v
 				}
-
-				var (
-					frames  float64
-					seconds float64
-					err     error
-				)
-
-				if frames, err = strconv.ParseFloat(fpsParts[0], 64); err != nil {
-					return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video frame rate seconds value: %w", err)
+			}
+		case "avg_frame_rate":
+			{
+				if v, err := parseProbeFrameRate(value); err != nil {
+					return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video average frame rate: %w", err)
+				} else {
+					probe.AvgFps = v
 				}
-
-				if seconds, err = strconv.ParseFloat(fpsParts[1], 64); err != nil {
-					return VideoProbe{}, fmt.Errorf("video: failed to prase the probed video frame rate frames value: %w", err)
+			}
+		case "TAG:creation_time":
+			{
+				// NOTE: The creation time is an optional container metadata entry, therefore invalid values are ignored
+				if v, err := time.Parse(time.RFC3339Nano, value); err == nil {
+					probe.CreationTime = v.UTC()
 				}
-
-				probe.Fps = frames / seconds
 			}
-		case "tag:rotate":
+		case "TAG:rotate", "tag:rotate":
 			{
-				if value == "90" || value == "270" {
-					stateRotate = true
+				if v, err := orientationFromRotateTag(value); err != nil {
+					return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video rotate tag: %w", err)
+				} else {
+					rotateTag = &v
 				}
 			}
+		case "rotation":
+			{
+				if v, err := orientationFromRotation(value); err != nil {
+					return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video side data rotation: %w", err)
+				} else {
+					sideDataRotation = &v
+				}
+			}
+		case "displaymatrix":
+			{
+				stateDisplayMatrix = true
+				displayMatrixRows = 0
+			}
+		case "DISPOSITION:attached_pic":
+			{
+				attachedPicture = value == "1"
+			}
 		default:
 			{
 			}
 		}
 	}
 
+	if stateStreamCount == 0 {
+		return VideoProbe{}, fmt.Errorf("video: the video does not contain the selected video stream")
+	}
+
 	if stateStreamCount != 1 {
 		return VideoProbe{}, fmt.Errorf("video: the video contains a invalid number of video streams")
 	}
@@ -244,15 +362,294 @@ scanning:
 		return VideoProbe{}, fmt.Errorf("video: failed to probe the video due to invalid probe result format")
 	}
 
-	if stateRotate {
+	// NOTE: The display matrix is preferred, because the side data rotation and the legacy rotate tag are not representing the flips
+	if displayMatrixRows == 3 {
+		if v, err := orientationFromDisplayMatrix(displayMatrix); err != nil {
+			return VideoProbe{}, fmt.Errorf("video: failed to parse the probed video display matrix: %w", err)
+		} else {
+			probe.Orientation = v
+		}
+	} else if sideDataRotation != nil {
+		probe.Orientation = *sideDataRotation
+	} else if rotateTag != nil {
+		probe.Orientation = *rotateTag
+	}
+
+	if probe.Orientation.IsTransposed() {
 		probe.Width, probe.Height = probe.Height, probe.Width
 	}
 
+	// NOTE: The real frame rate is the lowest rate at which all timestamps can be represented, therefore for a variable frame
+	// rate video it is greater than the average frame rate and the average frame rate is used to calculate the frames count.
+	framesCountFps := probe.Fps
+	if probe.AvgFps > 0 && math.Abs(probe.Fps-probe.AvgFps) > probe.Fps*frameRateTolerance {
+		probe.VariableFrameRate = true
+		framesCountFps = probe.AvgFps
+	}
+
 	// NOTE: Additional calculation due to the fact that nb_frames is metadata based and is not exact. The greater count value is preferred for now.
-	calculatedFramesCount := int(math.Ceil(probe.Fps * probe.Duration))
+	calculatedFramesCount := int(math.Ceil(framesCountFps * probe.Duration))
 	if probe.Frames < calculatedFramesCount {
 		probe.Frames = calculatedFramesCount
 	}
 
 	return probe, nil
 }
+
+// Parse the frame rate represented as a fraction. An undefined frame rate (0/0) is represented as zero.
+func parseProbeFrameRate(value string) (float64, error) {
+	fpsParts := strings.Split(value, "/")
+	if len(fpsParts) != 2 {
+		return 0, fmt.Errorf("video: failed to parse the frame rate due to invalid format")
+	}
+
+	var (
+		frames  float64
+		seconds float64
+		err     error
+	)
+
+	if frames, err = strconv.ParseFloat(fpsParts[0], 64); err != nil {
+		return 0, fmt.Errorf("video: failed to parse the frame rate frames value: %w", err)
+	}
+
+	if seconds, err = strconv.ParseFloat(fpsParts[1], 64); err != nil {
+		return 0, fmt.Errorf("video: failed to prase the frame rate seconds value: %w", err)
+	}
+
+	if seconds == 0 {
+		return 0, nil
+	}
+
+	return frames / seconds, nil
+}
+
+// Check if the durations between the presentation timestamps are varying, which indicates a variable frame rate. The
+// frame durations are compared to the median frame duration and a small amount of outliers is tolerated.
+func isVariableFrameRate(timestamps []float64) bool {
+	if len(timestamps) < 3 {
+		return false
+	}
+
+	durations := make([]float64, 0, len(timestamps)-1)
+	for index := 1; index < len(timestamps); index += 1 {
+		durations = append(durations, timestamps[index]-timestamps[index-1])
+	}
+
+	median, _ := utils.MedianMad(durations)
+
+	outliers := 0
+	for _, duration := range durations {
+		if math.Abs(duration-median) > median*frameDurationTolerance {
+			outliers += 1
+		}
+	}
+
+	return float64(outliers) > float64(len(durations))*frameDurationOutliersTolerance
+}
+
+// Structure representing a single video stream of a video file containing possibly multiple video streams. The index is the 0-based
+// index of the video streams, which can be used to select the stream for the analysis.
+type VideoStreamProbe struct {
+	Index           int
+	Codec           string
+	Width           int
+	Height          int
+	Fps             float64
+	Frames          int
+	Default         bool
+	AttachedPicture bool
+}
+
+// Probe the video file or the video stream specified by the url in order to list all available video streams.
+func ProbeVideoStreams(resource string) ([]VideoStreamProbe, error) {
+	if !utils.IsValidUrl(resource) && !utils.FileExists(resource) {
+		return nil, fmt.Errorf("video: the probe target video file does not exist")
+	}
+
+	if ok, err := AreBinariesAvailable(); !ok && err != nil {
+		return nil, fmt.Errorf("video: the required video processing binaries are not available: %w", err)
+	}
+
+	cmd := exec.Command(
+		ffprobeBinaryName,
+		"-select_streams", "v",
+		"-loglevel", "quiet",
+		"-show_entries", "stream=codec_name,width,height,nb_frames,r_frame_rate:stream_disposition=default,attached_pic",
+		"-of", "default=noprint_wrappers=0:nokey=0",
+		resource,
+	)
+
+	stdout, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("video: failed to probe the video streams: %w", err)
+	}
+
+	streams, err := parseStreamsProbeResult(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("video: failed to parse the video streams probe result: %w", err)
+	}
+
+	return streams, nil
+}
+
+// Return the 0-based index of the video stream which should be used if the stream index is not specified. The stream marked as
+// default is preferred and the attached pictures (Example: cover art) are used only if no other stream is available.
+func DefaultVideoStreamIndex(streams []VideoStreamProbe) int {
+	fallbackIndex := -1
+	for _, stream := range streams {
+		if stream.AttachedPicture {
+			continue
+		}
+
+		if stream.Default {
+			return stream.Index
+		}
+
+		if fallbackIndex < 0 {
+			fallbackIndex = stream.Index
+		}
+	}
+
+	return utils.MaxInt(0, fallbackIndex)
+}
+
+func parseStreamsProbeResult(stdout []byte) ([]VideoStreamProbe, error) {
+	reader := strings.NewReader(string(stdout))
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanLines)
+
+	var (
+		streams []VideoStreamProbe = make([]VideoStreamProbe, 0, 1)
+		stream  *VideoStreamProbe  = nil
+	)
+
+	for scanner.Scan() {
+		switch strings.TrimSpace(scanner.Text()) {
+		case string(tokenStreamOpen):
+			{
+				if stream != nil {
+					return nil, fmt.Errorf("video: failed to parse the probe result due to invalid result format")
+				}
+
+				stream = &VideoStreamProbe{Index: len(streams)}
+				continue
+			}
+		case string(tokenStreamClose):
+			{
+				if stream == nil {
+					return nil, fmt.Errorf("video: failed to parse the probe result due to invalid result format")
+				}
+
+				streams = append(streams, *stream)
+				stream = nil
+				continue
+			}
+		}
+
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || stream == nil || value == string(notApplicable) {
+			continue
+		}
+
+		var err error
+		switch key {
+		case "codec_name":
+			stream.Codec = value
+		case "width":
+			stream.Width, err = strconv.Atoi(value)
+		case "height":
+			stream.Height, err = strconv.Atoi(value)
+		case "nb_frames":
+			stream.Frames, err = strconv.Atoi(value)
+		case "r_frame_rate":
+			stream.Fps, err = parseProbeFrameRate(value)
+		case "DISPOSITION:default":
+			stream.Default = value == "1"
+		case "DISPOSITION:attached_pic":
+			stream.AttachedPicture = value == "1"
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("video: failed to parse the probed video stream %s value: %w", key, err)
+		}
+	}
+
+	if stream != nil {
+		return nil, fmt.Errorf("video: failed to parse the probe result due to invalid result format")
+	}
+
+	return streams, nil
+}
+
+// Probe the presentation timestamps of the video frames in seconds relative to the first frame. The timestamps are
+// read from the video stream packets without decoding the frames and are sorted to the presentation order. The packets
+// discarded by the decoder are skipped, therefore the timestamps are matching the decoded frames.
+func probeVideoFrameTimestamps(path string, streamIndex int) ([]float64, error) {
+	if !utils.FileExists(path) {
+		return nil, fmt.Errorf("video: the probe target video file does not exist")
+	}
+
+	cmd := exec.Command(
+		ffprobeBinaryName,
+		"-select_streams", videoStreamSpecifier(utils.MaxInt(0, streamIndex)),
+		"-loglevel", "quiet",
+		"-show_entries", "packet=pts_time,flags",
+		"-of", "csv=p=0",
+		path,
+	)
+
+	stdout, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("video: failed to probe the video frame timestamps: %w", err)
+	}
+
+	timestamps, err := parseFrameTimestampsProbeResult(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("video: failed to parse the video frame timestamps probe result: %w", err)
+	}
+
+	return timestamps, nil
+}
+
+// The packet flag indicating that the packet is discarded by the decoder (e.g. due to the edit list of the container).
+const packetDiscardFlag string = "D"
+
+func parseFrameTimestampsProbeResult(stdout []byte) ([]float64, error) {
+	reader := strings.NewReader(string(stdout))
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanLines)
+
+	timestamps := make([]float64, 0)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+
+		value := strings.TrimSpace(fields[0])
+		if len(value) == 0 {
+			continue
+		}
+
+		if len(fields) > 1 && strings.Contains(fields[1], packetDiscardFlag) {
+			continue
+		}
+
+		// NOTE: The timestamps are accessed by the frame index, therefore a single missing timestamp would shift all following frames
+		if value == string(notApplicable) {
+			return nil, fmt.Errorf("video: the probed frame timestamp is not available")
+		}
+
+		if v, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("video: failed to parse the probed frame timestamp: %w", err)
+		} else {
+			timestamps = append(timestamps, v)
+		}
+	}
+
+	slices.Sort(timestamps)
+
+	for index := len(timestamps) - 1; index >= 0; index -= 1 {
+		timestamps[index] -= timestamps[0]
+	}
+
+	return timestamps, nil
+}
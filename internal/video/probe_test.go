@@ -0,0 +1,127 @@
+package video
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFrameTimestampsProbeResultShouldSortAndNormalize(t *testing.T) {
+	stdout := []byte("1.500000,K__\n1.566667,\n1.533333,___\n\n1.600000\n")
+
+	timestamps, err := parseFrameTimestampsProbeResult(stdout)
+	assert.Nil(t, err)
+	assert.Len(t, timestamps, 4)
+
+	expected := []float64{0, 0.033333, 0.066667, 0.1}
+	for index, value := range expected {
+		assert.InDelta(t, value, timestamps[index], 1e-9)
+	}
+}
+
+func TestParseFrameTimestampsProbeResultShouldNotParseInvalidValues(t *testing.T) {
+	_, err := parseFrameTimestampsProbeResult([]byte("0.0\nabc\n"))
+	assert.NotNil(t, err)
+
+	_, err = parseFrameTimestampsProbeResult([]byte("0.0,K__\nN/A,___\n0.08,___\n"))
+	assert.NotNil(t, err)
+}
+
+func TestParseFrameTimestampsProbeResultShouldSkipDiscardedPackets(t *testing.T) {
+	stdout := []byte("-0.080000,KD_\n-0.040000,_D_\n0.000000,K__\n0.040000,___\n0.080000,___\n")
+
+	timestamps, err := parseFrameTimestampsProbeResult(stdout)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 0.04, 0.08}, timestamps)
+}
+
+func TestParseProbeResultShouldParseCreationTime(t *testing.T) {
+	stdout := []byte("[STREAM]\nwidth=1920\nheight=1080\nnb_frames=300\nr_frame_rate=30/1\n[/STREAM]\n[FORMAT]\nduration=10.000000\nTAG:creation_time=2024-07-14T21:03:10.500000Z\n[/FORMAT]\n")
+
+	probe, err := parseProbeResult(stdout, singleStreamStrategy)
+	assert.Nil(t, err)
+	assert.Equal(t, 1920, probe.Width)
+	assert.Equal(t, 300, probe.Frames)
+	assert.Equal(t, "2024-07-14T21:03:10.5Z", probe.CreationTime.Format("2006-01-02T15:04:05.999999999Z07:00"))
+}
+
+func TestIsVariableFrameRateShouldDetectIrregularFrameDurations(t *testing.T) {
+	assert.False(t, isVariableFrameRate(nil))
+	assert.False(t, isVariableFrameRate([]float64{0, 0.04, 0.08, 0.12, 0.16, 0.2}))
+	assert.True(t, isVariableFrameRate([]float64{0, 0.04, 0.08, 0.2, 0.24, 0.3}))
+}
+
+func TestParseProbeResultShouldDetectVariableFrameRate(t *testing.T) {
+	stdout := []byte("[STREAM]\nwidth=1920\nheight=1080\nnb_frames=250\nr_frame_rate=30/1\navg_frame_rate=25/1\n[/STREAM]\n[FORMAT]\nduration=10.000000\n[/FORMAT]\n")
+
+	probe, err := parseProbeResult(stdout, singleStreamStrategy)
+	assert.Nil(t, err)
+	assert.True(t, probe.VariableFrameRate)
+	assert.Equal(t, 25.0, probe.AvgFps)
+	assert.Equal(t, 250, probe.Frames)
+
+	stdout = []byte("[STREAM]\nwidth=1920\nheight=1080\nnb_frames=300\nr_frame_rate=30/1\navg_frame_rate=30/1\n[/STREAM]\n[FORMAT]\nduration=10.000000\n[/FORMAT]\n")
+
+	probe, err = parseProbeResult(stdout, singleStreamStrategy)
+	assert.Nil(t, err)
+	assert.False(t, probe.VariableFrameRate)
+}
+
+func TestParseStreamsProbeResultShouldListVideoStreams(t *testing.T) {
+	stdout := []byte("[STREAM]\ncodec_name=h264\nwidth=3840\nheight=2160\nnb_frames=1800\nr_frame_rate=60/1\nDISPOSITION:default=1\nDISPOSITION:attached_pic=0\n[/STREAM]\n" +
+		"[STREAM]\ncodec_name=h264\nwidth=640\nheight=360\nnb_frames=N/A\nr_frame_rate=30/1\nDISPOSITION:default=0\nDISPOSITION:attached_pic=0\n[/STREAM]\n")
+
+	streams, err := parseStreamsProbeResult(stdout)
+	assert.Nil(t, err)
+	assert.Len(t, streams, 2)
+
+	assert.Equal(t, VideoStreamProbe{Index: 0, Codec: "h264", Width: 3840, Height: 2160, Fps: 60, Frames: 1800, Default: true}, streams[0])
+	assert.Equal(t, VideoStreamProbe{Index: 1, Codec: "h264", Width: 640, Height: 360, Fps: 30, Frames: 0, Default: false}, streams[1])
+
+	_, err = parseStreamsProbeResult([]byte("[STREAM]\ncodec_name=h264\n"))
+	assert.NotNil(t, err)
+}
+
+func TestDefaultVideoStreamIndexShouldPreferTheDefaultStream(t *testing.T) {
+	assert.Equal(t, 0, DefaultVideoStreamIndex(nil))
+
+	assert.Equal(t, 1, DefaultVideoStreamIndex([]VideoStreamProbe{
+		{Index: 0, Default: false},
+		{Index: 1, Default: true},
+	}))
+
+	assert.Equal(t, 1, DefaultVideoStreamIndex([]VideoStreamProbe{
+		{Index: 0, Default: true, AttachedPicture: true},
+		{Index: 1, Default: false},
+		{Index: 2, Default: false},
+	}))
+
+	assert.Equal(t, 0, DefaultVideoStreamIndex([]VideoStreamProbe{
+		{Index: 0, Default: false, AttachedPicture: true},
+	}))
+}
+
+func TestParseProbeResultShouldRequireStreamIndexForMultipleStreams(t *testing.T) {
+	stdout := []byte("[STREAM]\nwidth=1920\nheight=1080\nnb_frames=300\nr_frame_rate=30/1\n[/STREAM]\n[STREAM]\nwidth=640\nheight=360\nnb_frames=300\nr_frame_rate=30/1\n[/STREAM]\n")
+
+	_, err := parseProbeResult(stdout, singleStreamStrategy)
+	assert.NotNil(t, err)
+
+	_, err = parseProbeResult([]byte("[FORMAT]\nduration=10.000000\n[/FORMAT]\n"), singleStreamStrategy)
+	assert.NotNil(t, err)
+}
+
+func TestParseProbeResultShouldSkipAttachedPictures(t *testing.T) {
+	stdout := []byte("[STREAM]\nwidth=600\nheight=600\nnb_frames=1\nr_frame_rate=90000/1\nDISPOSITION:attached_pic=1\n[/STREAM]\n[STREAM]\nwidth=1920\nheight=1080\nnb_frames=300\nr_frame_rate=30/1\nDISPOSITION:attached_pic=0\n[/STREAM]\n[STREAM]\nwidth=300\nheight=300\nnb_frames=1\nr_frame_rate=90000/1\nDISPOSITION:attached_pic=1\n[/STREAM]\n[FORMAT]\nduration=10.000000\n[/FORMAT]\n")
+
+	probe, err := parseProbeResult(stdout, singleStreamStrategy)
+	assert.Nil(t, err)
+	assert.Equal(t, 1920, probe.Width)
+	assert.Equal(t, 1080, probe.Height)
+	assert.Equal(t, 300, probe.Frames)
+	assert.Equal(t, 30.0, probe.Fps)
+	assert.Equal(t, 1, probe.StreamIndex)
+
+	_, err = parseProbeResult([]byte("[STREAM]\nwidth=600\nheight=600\nnb_frames=1\nr_frame_rate=90000/1\nDISPOSITION:attached_pic=1\n[/STREAM]\n"), singleStreamStrategy)
+	assert.NotNil(t, err)
+}
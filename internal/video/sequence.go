@@ -0,0 +1,245 @@
+package video
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+)
+
+// The frame rate of the image sequence used if the frame rate is not specified.
+const DefaultImageSequenceFps float64 = 25
+
+// The number of frame numbers at which the first image of the numbered image sequence is searched. Matches the FFmpeg default.
+const imageSequenceStartNumberRange int = 5
+
+// The file extensions of the images treated as the frames of the image sequence directory.
+var imageSequenceExtensions = []string{".png", ".jpg", ".jpeg", ".tif", ".tiff", ".bmp"}
+
+// The printf-style frame number placeholder of the numbered image sequence pattern. Example: frame-%05d.png
+var imageSequenceNumberPlaceholder = regexp.MustCompile(`%0?[0-9]*d`)
+
+// Structure representing the ordered images of the image sequence and the pattern passed to the FFmpeg image2 demuxer.
+type imageSequence struct {
+	Pattern     string
+	Glob        bool
+	StartNumber int
+	Files       []string
+}
+
+// Return a boolean value representing if the path is specifying an image sequence instead of a video file. The image sequence is
+// specified as a directory, a glob pattern (Example: frames/*.jpg) or a printf-style numbered pattern (Example: frames/img-%04d.png).
+func IsImageSequence(path string) bool {
+	if info, err := os.Stat(path); err == nil {
+		return info.IsDir()
+	}
+
+	return imageSequenceNumberPlaceholder.MatchString(path) || strings.ContainsAny(path, "*?[")
+}
+
+// Resolve the ordered images of the image sequence specified by the directory, glob pattern or numbered pattern. The images of the
+// directory must share the extension of the lexically first image. The glob pattern images are ordered lexically and the numbered
+// pattern images are read until the first missing frame number.
+func resolveImageSequence(path string) (imageSequence, error) {
+	var (
+		sequence imageSequence
+		err      error
+	)
+
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		sequence, err = resolveImageSequenceDirectory(path)
+	} else if placeholders := imageSequenceNumberPlaceholder.FindAllStringIndex(path, -1); len(placeholders) != 0 {
+		if len(placeholders) != 1 || strings.Count(path, "%") != 1 {
+			return imageSequence{}, fmt.Errorf("video: the image sequence pattern must contain a single frame number placeholder")
+		}
+
+		sequence, err = resolveImageSequenceNumbers(path)
+	} else {
+		sequence, err = resolveImageSequenceGlob(path)
+	}
+
+	if err != nil {
+		return imageSequence{}, err
+	}
+
+	if len(sequence.Files) == 0 {
+		return imageSequence{}, fmt.Errorf("video: the image sequence does not contain any images")
+	}
+
+	return sequence, nil
+}
+
+func resolveImageSequenceDirectory(directoryPath string) (imageSequence, error) {
+	entries, err := os.ReadDir(directoryPath)
+	if err != nil {
+		return imageSequence{}, fmt.Errorf("video: failed to read the image sequence directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(imageSequenceExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+			continue
+		}
+
+		// NOTE: The directory path is escaped, because it is a part of the glob pattern
+		return resolveImageSequenceGlob(filepath.Join(escapeGlobPattern(directoryPath), "*"+filepath.Ext(entry.Name())))
+	}
+
+	return imageSequence{}, fmt.Errorf("video: the image sequence directory does not contain any images")
+}
+
+func resolveImageSequenceGlob(pattern string) (imageSequence, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return imageSequence{}, fmt.Errorf("video: invalid image sequence glob pattern: %w", err)
+	}
+
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if utils.FileExists(match) {
+			files = append(files, match)
+		}
+	}
+
+	slices.Sort(files)
+
+	return imageSequence{
+		Pattern: pattern,
+		Glob:    true,
+		Files:   files,
+	}, nil
+}
+
+func resolveImageSequenceNumbers(pattern string) (imageSequence, error) {
+	startNumber := -1
+	for number := range imageSequenceStartNumberRange {
+		if utils.FileExists(fmt.Sprintf(pattern, number)) {
+			startNumber = number
+			break
+		}
+	}
+
+	if startNumber < 0 {
+		return imageSequence{}, fmt.Errorf("video: the first image of the numbered image sequence not found")
+	}
+
+	files := make([]string, 0)
+	for number := startNumber; utils.FileExists(fmt.Sprintf(pattern, number)); number += 1 {
+		files = append(files, fmt.Sprintf(pattern, number))
+	}
+
+	return imageSequence{
+		Pattern:     pattern,
+		Glob:        false,
+		StartNumber: startNumber,
+		Files:       files,
+	}, nil
+}
+
+// Escape the glob pattern special characters of the path. NOTE: The escaping is not supported on Windows, where the glob pattern
+// type of the FFmpeg image2 demuxer is not available either.
+func escapeGlobPattern(path string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return replacer.Replace(path)
+}
+
+// Get the FFmpeg input arguments of the image2 demuxer reading the image sequence at the given frame rate.
+func (sequence imageSequence) InputArgs(fps float64) []string {
+	args := make([]string, 0, 8)
+	args = append(args, "-f", "image2")
+	args = append(args, "-framerate", strconv.FormatFloat(fps, 'f', -1, 64))
+
+	if sequence.Glob {
+		args = append(args, "-pattern_type", "glob")
+	} else {
+		args = append(args, "-pattern_type", "sequence")
+		args = append(args, "-start_number", strconv.Itoa(sequence.StartNumber))
+	}
+
+	return args
+}
+
+// Open the image sequence specified by the directory, glob pattern or numbered pattern as a video with the given frame rate. The
+// default frame rate is used if the frame rate is zero. The frames are timed evenly and the frames count is exact.
+func NewImageSequence(path string, fps float64) (Video, error) {
+	if fps < 0 {
+		return nil, fmt.Errorf("video: the image sequence frame rate can not be negative")
+	}
+
+	if fps == 0 {
+		fps = DefaultImageSequenceFps
+	}
+
+	if ok, err := AreBinariesAvailable(); !ok && err != nil {
+		return nil, fmt.Errorf("video: the required video processing binaries are not available: %w", err)
+	}
+
+	sequence, err := resolveImageSequence(path)
+	if err != nil {
+		return nil, fmt.Errorf("video: failed to resolve the image sequence: %w", err)
+	}
+
+	probe, err := probeVideo(sequence.Files[0], "v", singleStreamStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("video: failed to probe the first image of the image sequence: %w", err)
+	}
+
+	timestamps := make([]float64, len(sequence.Files))
+	for index := range timestamps {
+		timestamps[index] = float64(index) / fps
+	}
+
+	return &video{
+		FilePath:       sequence.Pattern,
+		InputArgs:      sequence.InputArgs(fps),
+		StreamIndex:    -1,
+		Dim:            utils.Vec2i{X: probe.Width, Y: probe.Height},
+		BboxAnchor:     utils.Vec2i{X: 0, Y: 0},
+		BboxDim:        utils.Vec2i{X: probe.Width, Y: probe.Height},
+		Scale:          1,
+		Duration:       float64(len(sequence.Files)) / fps,
+		Fps:            fps,
+		VariableFps:    false,
+		FramesCount:    len(sequence.Files),
+		Timestamps:     timestamps,
+		Created:        time.Time{},
+		FrameBuffer:    nil,
+		Process:        nil,
+		Pipe:           nil,
+		ScaleAlgorithm: options.Default,
+		Orientation:    probe.Orientation,
+		TargetFrames:   nil,
+		FirstFrame:     0,
+		LastFrame:      -1,
+	}, nil
+}
+
+// Open the video file or the image sequence, if the path is specifying an image sequence. The stream index is used only by the video
+// file and the frame rate is used only by the image sequence.
+func OpenVideo(path string, streamIndex int, sequenceFps float64) (Video, error) {
+	if IsImageSequence(path) {
+		return NewImageSequence(path, sequenceFps)
+	}
+
+	return NewVideo(path, streamIndex)
+}
+
+// Calculate the content fingerprint of the video file or the image sequence. The image sequence fingerprint is covering all images.
+func Fingerprint(path string) (string, error) {
+	if !IsImageSequence(path) {
+		return utils.FileFingerprint(path)
+	}
+
+	sequence, err := resolveImageSequence(path)
+	if err != nil {
+		return "", fmt.Errorf("video: failed to resolve the image sequence: %w", err)
+	}
+
+	return utils.FileSetFingerprint(sequence.Files)
+}
@@ -0,0 +1,113 @@
+package video
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockImageFiles(t *testing.T, directoryPath string, names ...string) {
+	for _, name := range names {
+		assert.Nil(t, os.WriteFile(filepath.Join(directoryPath, name), []byte(name), 0660))
+	}
+}
+
+func TestIsImageSequenceShouldDetectTheSequencePaths(t *testing.T) {
+	directoryPath := t.TempDir()
+	mockImageFiles(t, directoryPath, "video[1].mp4")
+
+	assert.True(t, IsImageSequence(directoryPath))
+	assert.True(t, IsImageSequence(filepath.Join(directoryPath, "*.jpg")))
+	assert.True(t, IsImageSequence(filepath.Join(directoryPath, "img-%04d.png")))
+	assert.True(t, IsImageSequence(filepath.Join(directoryPath, "img-%d.png")))
+
+	assert.False(t, IsImageSequence(filepath.Join(directoryPath, "video[1].mp4")))
+	assert.False(t, IsImageSequence(filepath.Join(directoryPath, "video.mp4")))
+	assert.False(t, IsImageSequence("https://example.com/stream.m3u8"))
+}
+
+func TestResolveImageSequenceShouldResolveTheNumberedPattern(t *testing.T) {
+	directoryPath := t.TempDir()
+	mockImageFiles(t, directoryPath, "img-0002.png", "img-0003.png", "img-0004.png", "img-0006.png")
+
+	sequence, err := resolveImageSequence(filepath.Join(directoryPath, "img-%04d.png"))
+	assert.Nil(t, err)
+	assert.False(t, sequence.Glob)
+	assert.Equal(t, 2, sequence.StartNumber)
+	assert.Equal(t, []string{
+		filepath.Join(directoryPath, "img-0002.png"),
+		filepath.Join(directoryPath, "img-0003.png"),
+		filepath.Join(directoryPath, "img-0004.png"),
+	}, sequence.Files)
+
+	assert.Equal(t, []string{"-f", "image2", "-framerate", "12.5", "-pattern_type", "sequence", "-start_number", "2"}, sequence.InputArgs(12.5))
+
+	_, err = resolveImageSequence(filepath.Join(directoryPath, "img-%04d-%d.png"))
+	assert.NotNil(t, err)
+
+	_, err = resolveImageSequence(filepath.Join(directoryPath, "frame-%04d.png"))
+	assert.NotNil(t, err)
+}
+
+func TestResolveImageSequenceShouldResolveTheGlobPatternAndDirectory(t *testing.T) {
+	directoryPath := filepath.Join(t.TempDir(), "night [1]")
+	assert.Nil(t, os.MkdirAll(filepath.Join(directoryPath, "nested.jpg"), 0770))
+	mockImageFiles(t, directoryPath, "b.jpg", "a.jpg", "c.jpg", "notes.txt", "d.png")
+
+	expected := []string{
+		filepath.Join(directoryPath, "a.jpg"),
+		filepath.Join(directoryPath, "b.jpg"),
+		filepath.Join(directoryPath, "c.jpg"),
+	}
+
+	sequence, err := resolveImageSequence(directoryPath)
+	assert.Nil(t, err)
+	assert.True(t, sequence.Glob)
+	assert.Equal(t, expected, sequence.Files)
+	assert.Equal(t, filepath.Join(escapeGlobPattern(directoryPath), "*.jpg"), sequence.Pattern)
+
+	assert.Equal(t, []string{"-f", "image2", "-framerate", "25", "-pattern_type", "glob"}, sequence.InputArgs(25))
+
+	sequence, err = resolveImageSequence(filepath.Join(escapeGlobPattern(directoryPath), "*.png"))
+	assert.Nil(t, err)
+	assert.Len(t, sequence.Files, 1)
+
+	_, err = resolveImageSequence(filepath.Join(escapeGlobPattern(directoryPath), "*.tiff"))
+	assert.NotNil(t, err)
+
+	_, err = resolveImageSequence(t.TempDir())
+	assert.NotNil(t, err)
+}
+
+func TestFingerprintShouldCoverTheImageSequence(t *testing.T) {
+	directoryPath := t.TempDir()
+	pattern := filepath.Join(directoryPath, "*.jpg")
+
+	for index := range 3 {
+		mockImageFiles(t, directoryPath, fmt.Sprintf("img-%d.jpg", index))
+	}
+
+	a, err := Fingerprint(pattern)
+	assert.Nil(t, err)
+
+	b, err := Fingerprint(directoryPath)
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+
+	mockImageFiles(t, directoryPath, "img-3.jpg")
+
+	c, err := Fingerprint(pattern)
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, c)
+
+	_, err = Fingerprint(filepath.Join(directoryPath, "*.png"))
+	assert.NotNil(t, err)
+}
+
+func TestNewImageSequenceShouldNotAcceptNegativeFps(t *testing.T) {
+	_, err := NewImageSequence(t.TempDir(), -1)
+	assert.NotNil(t, err)
+}
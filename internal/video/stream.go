@@ -1,12 +1,17 @@
 package video
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
@@ -20,10 +25,18 @@ type VideoStream interface {
 	SetBbox(x, y, w, h int) error
 	SetFrameBuffer(buffer []byte) error
 	SetHttpHeaders(headers http.Header) error
+	FramesPerSecond() float64
 	Read() error
+	ReadInto(buffer []byte) error
+	FrameTime() time.Time
+	LogError() error
 	Close()
 }
 
+// The maximal time to wait for the presentation timestamp of the read frame. The timestamp is logged by the decoding
+// process before the frame is written, therefore the timeout is only reached if the timestamp of the frame is not available.
+const frameTimestampTimeout time.Duration = time.Second
+
 type videoStream struct {
 	Url            string
 	HttpHeaders    http.Header
@@ -32,10 +45,20 @@ type videoStream struct {
 	BboxDim        utils.Vec2i
 	Scale          float64
 	ScaleAlgorithm options.ScaleAlgorithm
+	Orientation    Orientation
 	Fps            float64
 	FrameBuffer    []byte
 	Process        *exec.Cmd
 	Pipe           io.ReadCloser
+	Timestamps     *frameTimestampReader
+	FrameIndex     int
+	Done           chan struct{}
+	Timestamp      time.Time
+	ClockAnchor    time.Time
+	PtsAnchor      float64
+	IsClosed       bool
+	LogErr         error
+	Mu             sync.Mutex
 }
 
 func (v *videoStream) SetHttpHeaders(headers http.Header) error {
@@ -129,6 +152,10 @@ func (v *videoStream) IsBboxUsed() bool {
 	return v.Dim.X != v.BboxDim.X || v.Dim.Y != v.BboxDim.Y
 }
 
+func (v *videoStream) FramesPerSecond() float64 {
+	return v.Fps
+}
+
 func (v *videoStream) SetFrameBuffer(buffer []byte) error {
 	if v.IsInitialized() {
 		return fmt.Errorf("video: can not change the frame buffer after initialization")
@@ -146,13 +173,44 @@ func (v *videoStream) SetFrameBuffer(buffer []byte) error {
 }
 
 func (v *videoStream) Read() error {
+	return v.ReadInto(v.FrameBuffer)
+}
+
+// Read the next frame into the given buffer instead of the frame buffer. The buffer length must match the frame buffer length.
+// The read can be interrupted by closing the video stream from another goroutine, then the read and all following reads return io.EOF.
+func (v *videoStream) ReadInto(buffer []byte) error {
+	v.Mu.Lock()
+
+	if v.IsClosed {
+		v.Mu.Unlock()
+		return io.EOF
+	}
+
 	if !v.IsInitialized() {
-		if err := v.Init(); err != nil {
+		if err := v.init(); err != nil {
+			v.Mu.Unlock()
 			return fmt.Errorf("video: failed to initliaze frame reading video stream stream: %w", err)
 		}
 	}
 
-	if _, err := io.ReadFull(v.Pipe, v.FrameBuffer); err == nil {
+	if len(buffer) != len(v.FrameBuffer) {
+		v.Mu.Unlock()
+		return fmt.Errorf("video: the target buffer size of %d does not match the required buffer length of %d", len(buffer), len(v.FrameBuffer))
+	}
+
+	// NOTE: The lock is not held during the read, because the read may stall (Example: live stream) and the stream must be
+	// able to be closed in order to interrupt it. The pipe reference is copied, because the close is clearing the fields.
+	pipe := v.Pipe
+	v.Mu.Unlock()
+
+	_, err := io.ReadFull(pipe, buffer)
+
+	if v.isClosed() {
+		return io.EOF
+	}
+
+	if err == nil {
+		v.Timestamp = v.nextFrameTime()
 		return nil
 	} else if errors.Is(err, io.EOF) {
 		return io.EOF
@@ -163,31 +221,141 @@ func (v *videoStream) Read() error {
 	}
 }
 
-func (v *videoStream) Close() {
-	defer func() {
-		if err := recover(); err != nil {
-			v.Process = nil
-			v.Pipe = nil
-			v.FrameBuffer = nil
+func (v *videoStream) isClosed() bool {
+	v.Mu.Lock()
+	defer v.Mu.Unlock()
+
+	return v.IsClosed
+}
+
+// Return the UTC time of the last read frame. The time is based on the source presentation timestamp of the frame relative to the
+// first frame, anchored at the wall-clock time at which the first frame has been read, therefore the intervals between the frames
+// are not affected by the receive latency. The time is falling back to the read time if the timestamp is not available.
+func (v *videoStream) FrameTime() time.Time {
+	return v.Timestamp
+}
+
+func (v *videoStream) nextFrameTime() time.Time {
+	timestamp, ok := v.Timestamps.Next(v.FrameIndex)
+	v.FrameIndex += 1
+
+	now := time.Now().UTC()
+	if !ok {
+		return now
+	}
+
+	// NOTE: The timestamps are re-anchored if the source presentation timestamps are moving backwards (Example: stream restart)
+	if v.ClockAnchor.IsZero() || timestamp < v.PtsAnchor {
+		v.ClockAnchor = now
+		v.PtsAnchor = timestamp
+	}
+
+	return v.ClockAnchor.Add(time.Duration(math.Round((timestamp - v.PtsAnchor) * float64(time.Second))))
+}
+
+// The presentation timestamp of the frame specified by the 0-based frame index logged by the showinfo filter.
+type frameTimestamp struct {
+	Index     int
+	Timestamp float64
+}
+
+// Reader pairing the frames with the presentation timestamps logged by the decoding process using the frame index, therefore
+// a missing or late timestamp is affecting only the timing of a single frame.
+type frameTimestampReader struct {
+	Timestamps <-chan frameTimestamp
+	Pending    *frameTimestamp
+	Timer      *time.Timer
+	Timeout    time.Duration
+}
+
+func newFrameTimestampReader(timestamps <-chan frameTimestamp, timeout time.Duration) *frameTimestampReader {
+	timer := time.NewTimer(timeout)
+	timer.Stop()
+
+	return &frameTimestampReader{
+		Timestamps: timestamps,
+		Pending:    nil,
+		Timer:      timer,
+		Timeout:    timeout,
+	}
+}
+
+// Return the presentation timestamp of the frame specified by the 0-based frame index. The timestamps of the preceding frames
+// are discarded and the timestamp of a following frame is kept for later. The boolean value indicates if the timestamp is available.
+func (r *frameTimestampReader) Next(index int) (float64, bool) {
+	if r.Pending != nil {
+		if r.Pending.Index > index {
+			return 0, false
 		}
-	}()
 
-	if v.Process != nil {
-		if v.Process.ProcessState == nil || !v.Process.ProcessState.Exited() {
-			v.Process.Process.Kill()
+		pending := *r.Pending
+		r.Pending = nil
+
+		if pending.Index == index {
+			return pending.Timestamp, true
+		}
+	}
+
+	r.Timer.Reset(r.Timeout)
+	defer r.Timer.Stop()
+
+	for {
+		select {
+		case timestamp, ok := <-r.Timestamps:
+			if !ok {
+				return 0, false
+			}
+
+			if timestamp.Index < index {
+				continue
+			}
+
+			if timestamp.Index > index {
+				r.Pending = &timestamp
+				return 0, false
+			}
+
+			return timestamp.Timestamp, true
+		case <-r.Timer.C:
+			return 0, false
 		}
+	}
+}
+
+// Close the video stream and stop the reading process. The stream can not be reinitialized after closing, therefore a read
+// in progress and all following reads and initializations return io.EOF instead of starting a new reading process.
+func (v *videoStream) Close() {
+	v.Mu.Lock()
 
-		v.Process.Wait()
-		v.Process = nil
+	if v.IsClosed {
+		v.Mu.Unlock()
+		return
 	}
 
-	if v.Pipe != nil {
-		v.Pipe.Close()
-		v.Pipe = nil
+	v.IsClosed = true
+
+	if v.Done != nil {
+		close(v.Done)
+		v.Done = nil
 	}
 
-	if v.FrameBuffer != nil {
-		v.FrameBuffer = nil
+	process, pipe := v.Process, v.Pipe
+	v.Process = nil
+	v.Pipe = nil
+	v.FrameBuffer = nil
+
+	v.Mu.Unlock()
+
+	if process != nil && process.Process != nil {
+		if process.ProcessState == nil || !process.ProcessState.Exited() {
+			process.Process.Kill()
+		}
+
+		process.Wait()
+	}
+
+	if pipe != nil {
+		pipe.Close()
 	}
 }
 
@@ -196,6 +364,17 @@ func (v *videoStream) IsInitialized() bool {
 }
 
 func (v *videoStream) Init() error {
+	v.Mu.Lock()
+	defer v.Mu.Unlock()
+
+	if v.IsClosed {
+		return io.EOF
+	}
+
+	return v.init()
+}
+
+func (v *videoStream) init() error {
 	if v.IsInitialized() {
 		return fmt.Errorf("video: video stream reading process can not be reinitialized")
 	}
@@ -205,6 +384,10 @@ func (v *videoStream) Init() error {
 		args    []string = make([]string, 0, 32)
 	)
 
+	// NOTE: The automatic rotation is disabled and the orientation is applied as the first filter, therefore the dimensions and the
+	// bbox are always relative to the probed display orientation regardless of the FFmpeg version automatic rotation support.
+	filters = append(filters, v.Orientation.Filters()...)
+
 	if v.Scale != 1 {
 		w, h := v.GetScaledDimensions()
 
@@ -237,9 +420,17 @@ func (v *videoStream) Init() error {
 		filters = append(filters, fmt.Sprintf("crop=%d:%d:%d:%d", w, h, x, y))
 	}
 
+	// NOTE: The showinfo filter is logging the source presentation timestamps of the frames which are preserved by the copyts
+	// option. The passthrough sync method is used to guarantee that each logged timestamp is matching a output frame.
+	filters = append(filters, "showinfo")
+
+	args = append(args, "-noautorotate")
 	args = append(args, "-i", v.Url)
-	args = append(args, "-loglevel", "quiet")
+	args = append(args, "-loglevel", "info")
+	args = append(args, "-nostats")
 	args = append(args, "-hide_banner")
+	args = append(args, "-copyts")
+	args = append(args, "-vsync", "passthrough")
 	args = append(args, "-f", "image2pipe")
 	args = append(args, "-pix_fmt", "rgba")
 	args = append(args, "-vcodec", "rawvideo")
@@ -272,6 +463,11 @@ func (v *videoStream) Init() error {
 		return fmt.Errorf("video: failed to access the video reading process pipe: %w", err)
 	}
 
+	logPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("video: failed to access the video reading process log pipe: %w", err)
+	}
+
 	if v.FrameBuffer == nil {
 		w, h := v.GetOutputDimensions()
 		size := w * h * frameChannelDepth
@@ -279,16 +475,147 @@ func (v *videoStream) Init() error {
 		v.FrameBuffer = make([]byte, size)
 	}
 
+	timestamps := make(chan frameTimestamp, 64)
+
 	v.Process = cmd
 	v.Pipe = pipe
+	v.Timestamps = newFrameTimestampReader(timestamps, frameTimestampTimeout)
+	v.FrameIndex = 0
+	v.ClockAnchor = time.Time{}
+	v.PtsAnchor = 0
+	v.Done = make(chan struct{})
 
 	if err := v.Process.Start(); err != nil {
 		return fmt.Errorf("video: failed to start the video reading proces: %w", err)
 	}
 
+	go func(done <-chan struct{}) {
+		if err := scanFrameTimestamps(logPipe, timestamps, done); err != nil {
+			v.Mu.Lock()
+			v.LogErr = err
+			v.Mu.Unlock()
+		}
+	}(v.Done)
+
+	return nil
+}
+
+// Return the error which stopped the scanning of the decoding process log. The frames following the failure are timed without
+// the presentation timestamps. A nil error is returned if the log is scanned without failures.
+func (v *videoStream) LogError() error {
+	v.Mu.Lock()
+	defer v.Mu.Unlock()
+
+	return v.LogErr
+}
+
+// The maximal length of the decoding process log line. The lines of the stream metadata can exceed the default scanner buffer.
+const maxLogLineLength int = 1024 * 1024
+
+// Scan the decoding process log for the showinfo filter entries and forward the frames presentation timestamps. The log is
+// drained after a scanning failure, because the decoding process is blocked if the log is not read.
+func scanFrameTimestamps(r io.Reader, timestamps chan<- frameTimestamp, done <-chan struct{}) error {
+	defer close(timestamps)
+
+	var (
+		scanner *bufio.Scanner = bufio.NewScanner(r)
+		parser  showinfoParser = showinfoParser{}
+	)
+
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogLineLength)
+
+	for scanner.Scan() {
+		timestamp, ok := parser.Parse(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-done:
+			return nil
+		case timestamps <- timestamp:
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		select {
+		case <-done:
+		default:
+			io.Copy(io.Discard, r)
+		}
+
+		return fmt.Errorf("video: failed to scan the decoding process log: %w", err)
+	}
+
 	return nil
 }
 
+// Parser of the ffmpeg showinfo filter log lines. The time base is parsed from the filter configuration entry in
+// order to calculate the timestamp from the exact presentation timestamp instead of the rounded timestamp in seconds.
+type showinfoParser struct {
+	TimeBase float64
+}
+
+// Parse the log line and return the frame index and the presentation timestamp in seconds if the line is a showinfo frame entry.
+func (p *showinfoParser) Parse(line string) (frameTimestamp, bool) {
+	if !strings.Contains(line, "Parsed_showinfo") {
+		return frameTimestamp{}, false
+	}
+
+	if value, ok := showinfoField(line, "time_base:"); ok {
+		if parts := strings.Split(strings.TrimSuffix(value, ","), "/"); len(parts) == 2 {
+			num, numErr := strconv.ParseFloat(parts[0], 64)
+			den, denErr := strconv.ParseFloat(parts[1], 64)
+
+			if numErr == nil && denErr == nil && den != 0 {
+				p.TimeBase = num / den
+			}
+		}
+
+		return frameTimestamp{}, false
+	}
+
+	value, ok := showinfoField(line, " n:")
+	if !ok {
+		return frameTimestamp{}, false
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		return frameTimestamp{}, false
+	}
+
+	if p.TimeBase != 0 {
+		if value, ok := showinfoField(line, " pts:"); ok {
+			if pts, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return frameTimestamp{Index: index, Timestamp: float64(pts) * p.TimeBase}, true
+			}
+		}
+	}
+
+	if value, ok := showinfoField(line, "pts_time:"); ok {
+		if ptsTime, err := strconv.ParseFloat(value, 64); err == nil {
+			return frameTimestamp{Index: index, Timestamp: ptsTime}, true
+		}
+	}
+
+	return frameTimestamp{}, false
+}
+
+func showinfoField(line, key string) (string, bool) {
+	index := strings.Index(line, key)
+	if index == -1 {
+		return "", false
+	}
+
+	fields := strings.Fields(line[index+len(key):])
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	return fields[0], true
+}
+
 func NewVideoStream(url string) (VideoStream, error) {
 	if !utils.IsValidUrl(url) {
 		return nil, fmt.Errorf("video: the video stream url is invalid")
@@ -311,6 +638,7 @@ func NewVideoStream(url string) (VideoStream, error) {
 		BboxDim:        utils.Vec2i{X: probe.Width, Y: probe.Height},
 		Scale:          1,
 		ScaleAlgorithm: options.Default,
+		Orientation:    probe.Orientation,
 		Fps:            probe.Fps,
 		FrameBuffer:    nil,
 		Process:        nil,
@@ -0,0 +1,176 @@
+package video
+
+import (
+	"bufio"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowinfoParserShouldParseFrameTimestamps(t *testing.T) {
+	parser := showinfoParser{}
+
+	_, ok := parser.Parse("Input #0, rtsp, from 'rtsp://localhost/stream':")
+	assert.False(t, ok)
+
+	_, ok = parser.Parse("[Parsed_showinfo_1 @ 0x55d0c2c0]   color_range:tv color_space:bt709")
+	assert.False(t, ok)
+
+	timestamp, ok := parser.Parse("[Parsed_showinfo_1 @ 0x55d0c2c0] n:   0 pts:      0 pts_time:0.5     duration:3333")
+	assert.True(t, ok)
+	assert.Equal(t, 0, timestamp.Index)
+	assert.Equal(t, 0.5, timestamp.Timestamp)
+
+	_, ok = parser.Parse("[Parsed_showinfo_1 @ 0x55d0c2c0] config in time_base: 1/1000000, frame_rate: 30/1")
+	assert.False(t, ok)
+
+	timestamp, ok = parser.Parse("[Parsed_showinfo_1 @ 0x55d0c2c0] n:   1 pts:1720991000250000 pts_time:1.72099e+09 duration:33333")
+	assert.True(t, ok)
+	assert.Equal(t, 1, timestamp.Index)
+	assert.InDelta(t, 1720991000.25, timestamp.Timestamp, 1e-6)
+
+	_, ok = parser.Parse("[Parsed_showinfo_1 @ 0x55d0c2c0] pts:1720991000250000 pts_time:1.72099e+09 duration:33333")
+	assert.False(t, ok)
+}
+
+func TestFrameTimestampReaderShouldPairTheTimestampsWithFrames(t *testing.T) {
+	timestamps := make(chan frameTimestamp, 8)
+	reader := newFrameTimestampReader(timestamps, 10*time.Millisecond)
+
+	timestamps <- frameTimestamp{Index: 0, Timestamp: 1.0}
+	timestamps <- frameTimestamp{Index: 2, Timestamp: 3.0}
+	timestamps <- frameTimestamp{Index: 3, Timestamp: 4.0}
+
+	timestamp, ok := reader.Next(0)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, timestamp)
+
+	_, ok = reader.Next(1)
+	assert.False(t, ok)
+
+	timestamp, ok = reader.Next(2)
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, timestamp)
+
+	timestamp, ok = reader.Next(3)
+	assert.True(t, ok)
+	assert.Equal(t, 4.0, timestamp)
+
+	readTime := time.Now()
+	_, ok = reader.Next(4)
+	assert.False(t, ok)
+	assert.Less(t, time.Since(readTime), time.Second)
+
+	timestamps <- frameTimestamp{Index: 4, Timestamp: 5.0}
+	timestamps <- frameTimestamp{Index: 5, Timestamp: 6.0}
+
+	timestamp, ok = reader.Next(5)
+	assert.True(t, ok)
+	assert.Equal(t, 6.0, timestamp)
+
+	close(timestamps)
+
+	_, ok = reader.Next(6)
+	assert.False(t, ok)
+}
+
+func TestVideoStreamFrameTimeShouldFollowTheSourceTimestamps(t *testing.T) {
+	timestamps := make(chan frameTimestamp, 8)
+
+	v := &videoStream{
+		Timestamps: newFrameTimestampReader(timestamps, 10*time.Millisecond),
+	}
+
+	timestamps <- frameTimestamp{Index: 0, Timestamp: 12.5}
+	timestamps <- frameTimestamp{Index: 1, Timestamp: 12.75}
+	timestamps <- frameTimestamp{Index: 2, Timestamp: 14.0}
+	timestamps <- frameTimestamp{Index: 3, Timestamp: 1.0}
+
+	first := v.nextFrameTime()
+	assert.Equal(t, time.UTC, first.Location())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, first.Add(250*time.Millisecond), v.nextFrameTime())
+	assert.Equal(t, first.Add(1500*time.Millisecond), v.nextFrameTime())
+
+	restarted := v.nextFrameTime()
+	assert.True(t, restarted.After(first))
+	assert.Equal(t, 1.0, v.PtsAnchor)
+}
+
+func TestVideoStreamCloseShouldInterruptTheReadInProgress(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+
+	pipe, err := cmd.StdoutPipe()
+	assert.Nil(t, err)
+	assert.Nil(t, cmd.Start())
+
+	v := &videoStream{
+		Url:         "rtsp://localhost/stream",
+		Dim:         utils.Vec2i{X: 2, Y: 2},
+		BboxDim:     utils.Vec2i{X: 2, Y: 2},
+		Scale:       1,
+		FrameBuffer: make([]byte, 2*2*frameChannelDepth),
+		Process:     cmd,
+		Pipe:        pipe,
+		Timestamps:  newFrameTimestampReader(make(chan frameTimestamp), 10*time.Millisecond),
+		Done:        make(chan struct{}),
+	}
+
+	result := make(chan error)
+	go func() {
+		result <- v.ReadInto(make([]byte, 2*2*frameChannelDepth))
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	v.Close()
+
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(5 * time.Second):
+		assert.FailNow(t, "the read in progress has not been interrupted by the close")
+	}
+
+	assert.ErrorIs(t, v.ReadInto(make([]byte, 2*2*frameChannelDepth)), io.EOF)
+	assert.ErrorIs(t, v.Init(), io.EOF)
+	assert.Nil(t, v.Process)
+}
+
+func TestScanFrameTimestampsShouldDrainTheLogAfterScanningFailure(t *testing.T) {
+	log := "[Parsed_showinfo_1 @ 0x55d0c2c0] n:   0 pts:      0 pts_time:0.5     duration:3333\n" +
+		strings.Repeat("x", maxLogLineLength+1) + "\n" +
+		"[Parsed_showinfo_1 @ 0x55d0c2c0] n:   1 pts:      0 pts_time:0.6     duration:3333\n"
+
+	var (
+		reader     *strings.Reader     = strings.NewReader(log)
+		timestamps chan frameTimestamp = make(chan frameTimestamp, 8)
+		done       chan struct{}       = make(chan struct{})
+	)
+
+	err := scanFrameTimestamps(reader, timestamps, done)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+	assert.Zero(t, reader.Len())
+
+	timestamp, ok := <-timestamps
+	assert.True(t, ok)
+	assert.Equal(t, 0, timestamp.Index)
+
+	_, ok = <-timestamps
+	assert.False(t, ok)
+}
+
+func TestScanFrameTimestampsShouldScanLongLogLines(t *testing.T) {
+	log := strings.Repeat("x", 2*bufio.MaxScanTokenSize) + "\n" +
+		"[Parsed_showinfo_1 @ 0x55d0c2c0] n:   0 pts:      0 pts_time:0.5     duration:3333\n"
+
+	timestamps := make(chan frameTimestamp, 8)
+
+	assert.Nil(t, scanFrameTimestamps(strings.NewReader(log), timestamps, make(chan struct{})))
+	assert.Len(t, timestamps, 1)
+}
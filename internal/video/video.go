@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Krzysztofz01/video-lightning-detector/internal/options"
 	"github.com/Krzysztofz01/video-lightning-detector/internal/utils"
@@ -20,23 +22,38 @@ type Video interface {
 	SetScaleAlgorithm(a options.ScaleAlgorithm) error
 	SetBbox(x, y, w, h int) error
 	SetTargetFrames(n ...int) error
+	SetFrameRange(first, last int) error
 	FramesCountApprox() int
+	FramesPerSecond() float64
+	IsVariableFrameRate() bool
+	FrameTimestamps() ([]float64, error)
+	SetFrameTimestamps(timestamps []float64) error
+	CreationTime() time.Time
 	SetFrameBuffer(buffer []byte) error
 	Read() error
+	ReadInto(buffer []byte) error
 	Close()
 }
 
 type video struct {
 	FilePath       string
+	InputArgs      []string
+	StreamIndex    int
 	Dim            utils.Vec2i
 	BboxAnchor     utils.Vec2i
 	BboxDim        utils.Vec2i
 	Scale          float64
 	ScaleAlgorithm options.ScaleAlgorithm
+	Orientation    Orientation
 	TargetFrames   []int
+	FirstFrame     int
+	LastFrame      int
 	Duration       float64
 	Fps            float64
+	VariableFps    bool
 	FramesCount    int
+	Timestamps     []float64
+	Created        time.Time
 	FrameBuffer    []byte
 	Process        *exec.Cmd
 	Pipe           io.ReadCloser
@@ -135,18 +152,125 @@ func (v *video) SetTargetFrames(n ...int) error {
 		}
 	}
 
+	if v.IsFrameRangeUsed() {
+		return fmt.Errorf("video: the target frames can not be used together with the frame range")
+	}
+
 	v.TargetFrames = n
 	return nil
 }
 
+// Limit the reading to the specified 0-based frames index range. The last index is inclusive and a negative value
+// indicates reading until the end of the video. The video is seeked to the first frame without decoding the
+// preceding frames.
+func (v *video) SetFrameRange(first, last int) error {
+	if v.IsInitialized() {
+		return fmt.Errorf("video: can not change frame range after initialization")
+	}
+
+	if v.TargetFrames != nil {
+		return fmt.Errorf("video: the frame range can not be used together with the target frames")
+	}
+
+	if first < 0 {
+		return fmt.Errorf("video: the first frame index can not be negative")
+	}
+
+	if first >= v.FramesCount {
+		return fmt.Errorf("video: the first frame index is not in the frame count range")
+	}
+
+	if last >= 0 && last < first {
+		return fmt.Errorf("video: the last frame index can not be lower than the first frame index")
+	}
+
+	v.FirstFrame = first
+	v.LastFrame = last
+	return nil
+}
+
+func (v *video) IsFrameRangeUsed() bool {
+	return v.FirstFrame != 0 || v.LastFrame >= 0
+}
+
 func (v *video) IsBboxUsed() bool {
 	return v.Dim.X != v.BboxDim.X || v.Dim.Y != v.BboxDim.Y
 }
 
 func (v *video) FramesCountApprox() int {
+	if v.IsFrameRangeUsed() {
+		last := v.FramesCount - 1
+		if v.LastFrame >= 0 {
+			last = utils.MinInt(last, v.LastFrame)
+		}
+
+		return utils.MaxInt(1, last-v.FirstFrame+1)
+	}
+
 	return v.FramesCount
 }
 
+func (v *video) FramesPerSecond() float64 {
+	return v.Fps
+}
+
+// Return a value indicating if the video has a variable frame rate. The value is based on the difference between the
+// real and average frame rate and is refined by the frame durations after the frame timestamps are probed.
+func (v *video) IsVariableFrameRate() bool {
+	return v.VariableFps
+}
+
+// Probe the presentation timestamps of all video frames in seconds relative to the first frame. The timestamps are
+// ordered by the presentation order, therefore the frame index can be used to access the timestamp of the frame.
+// The timestamps are probed once and reused for the frames count and the seeking of the frame range.
+func (v *video) FrameTimestamps() ([]float64, error) {
+	if v.Timestamps != nil {
+		return v.Timestamps, nil
+	}
+
+	timestamps, err := probeVideoFrameTimestamps(v.FilePath, v.StreamIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.SetFrameTimestamps(timestamps); err != nil {
+		return nil, err
+	}
+
+	return v.Timestamps, nil
+}
+
+// Provide the previously probed presentation timestamps of the video frames to avoid probing them again. The frames count
+// is updated to the count of timestamps, which unlike the metadata based count is exact.
+func (v *video) SetFrameTimestamps(timestamps []float64) error {
+	if v.IsInitialized() {
+		return fmt.Errorf("video: can not change the frame timestamps after initialization")
+	}
+
+	if len(timestamps) == 0 {
+		return fmt.Errorf("video: the video frame timestamps can not be empty")
+	}
+
+	if v.IsFrameRangeUsed() && v.FirstFrame >= len(timestamps) {
+		return fmt.Errorf("video: the first frame index is not in the frame timestamps range")
+	}
+
+	v.Timestamps = timestamps
+	v.FramesCount = len(timestamps)
+	v.VariableFps = v.VariableFps || isVariableFrameRate(timestamps)
+
+	if last := timestamps[len(timestamps)-1]; v.VariableFps && last > 0 {
+		v.Fps = float64(len(timestamps)-1) / last
+	}
+
+	return nil
+}
+
+// Return the video creation time from the container metadata. A zero time is returned if the creation time is not available.
+func (v *video) CreationTime() time.Time {
+	return v.Created
+}
+
 func (v *video) SetFrameBuffer(buffer []byte) error {
 	if v.IsInitialized() {
 		return fmt.Errorf("video: can not change the frame buffer after initialization")
@@ -164,13 +288,22 @@ func (v *video) SetFrameBuffer(buffer []byte) error {
 }
 
 func (v *video) Read() error {
+	return v.ReadInto(v.FrameBuffer)
+}
+
+// Read the next frame into the given buffer instead of the frame buffer. The buffer length must match the frame buffer length.
+func (v *video) ReadInto(buffer []byte) error {
 	if !v.IsInitialized() {
 		if err := v.Init(); err != nil {
 			return fmt.Errorf("video: failed to initliaze frame reading video stream: %w", err)
 		}
 	}
 
-	if _, err := io.ReadFull(v.Pipe, v.FrameBuffer); err == nil {
+	if len(buffer) != len(v.FrameBuffer) {
+		return fmt.Errorf("video: the target buffer size of %d does not match the required buffer length of %d", len(buffer), len(v.FrameBuffer))
+	}
+
+	if _, err := io.ReadFull(v.Pipe, buffer); err == nil {
 		return nil
 	} else if errors.Is(err, io.EOF) {
 		return io.EOF
@@ -209,6 +342,17 @@ func (v *video) Close() {
 	}
 }
 
+// Get the seek time in seconds preceding the frame of the given index. The time is placed halfway between the frame and
+// the preceding frame to avoid float rounding issues. The probed timestamps are used if available, because the frames
+// of a variable frame rate video are not evenly spaced.
+func (v *video) GetFrameSeekTime(index int) float64 {
+	if index > 0 && index < len(v.Timestamps) {
+		return (v.Timestamps[index-1] + v.Timestamps[index]) / 2
+	}
+
+	return (float64(index) - 0.5) / v.Fps
+}
+
 func (v *video) IsInitialized() bool {
 	return v.Process != nil && v.Pipe != nil && v.FrameBuffer != nil
 }
@@ -236,6 +380,9 @@ func (v *video) Init() error {
 		filters = append(filters, fmt.Sprintf("select='%s'", frames.String()))
 	}
 
+	// NOTE: The frames are transformed to the display orientation before scaling and cropping, because the bbox is relative to it
+	filters = append(filters, v.Orientation.Filters()...)
+
 	if v.Scale != 1 {
 		w, h := v.GetScaledDimensions()
 
@@ -268,21 +415,31 @@ func (v *video) Init() error {
 		filters = append(filters, fmt.Sprintf("crop=%d:%d:%d:%d", w, h, x, y))
 	}
 
+	if v.FirstFrame > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(v.GetFrameSeekTime(v.FirstFrame), 'f', 6, 64))
+	}
+
+	args = append(args, "-noautorotate")
+	args = append(args, v.InputArgs...)
 	args = append(args, "-i", v.FilePath)
 	args = append(args, "-loglevel", "quiet")
 	args = append(args, "-hide_banner")
 	args = append(args, "-f", "image2pipe")
 	args = append(args, "-pix_fmt", "rgba")
 	args = append(args, "-vcodec", "rawvideo")
-	args = append(args, "-map", "0:v:0")
+	args = append(args, "-map", fmt.Sprintf("0:v:%d", utils.MaxInt(0, v.StreamIndex)))
 
 	if len(filters) > 0 {
 		filter := strings.Join(filters, ",")
 		args = append(args, "-vf", filter)
 	}
 
-	if v.TargetFrames != nil {
-		args = append(args, "-vsync", "0")
+	// NOTE: The frames are passed through without duplicating or dropping them to match the output frame rate, therefore the
+	// output frames are corresponding to the decoded frames and their probed timestamps also for variable frame rate videos.
+	args = append(args, "-vsync", "0")
+
+	if v.LastFrame >= 0 {
+		args = append(args, "-frames:v", strconv.Itoa(v.LastFrame-v.FirstFrame+1))
 	}
 
 	args = append(args, "-")
@@ -311,7 +468,9 @@ func (v *video) Init() error {
 	return nil
 }
 
-func NewVideo(path string) (Video, error) {
+// Open the video file and probe the video stream specified by the 0-based index of the video streams. A negative index indicates
+// that the video file must contain a single video stream.
+func NewVideo(path string, streamIndex int) (Video, error) {
 	if !utils.FileExists(path) {
 		return nil, fmt.Errorf("video: the video file specified by the path does not exist")
 	}
@@ -320,7 +479,7 @@ func NewVideo(path string) (Video, error) {
 		return nil, fmt.Errorf("video: the required video processing binaries are not available: %w", err)
 	}
 
-	probe, err := probeVideoFile(path)
+	probe, err := probeVideoFile(path, streamIndex)
 	if err != nil {
 		return nil, fmt.Errorf("video: failed to probe the target video file: %w", err)
 	}
@@ -329,19 +488,39 @@ func NewVideo(path string) (Video, error) {
 		return nil, fmt.Errorf("video: failed to probe the target video duration or frames count")
 	}
 
+	// NOTE: The real frame rate of a variable frame rate video is greater than the rate of the frames, therefore the average
+	// frame rate is used for the conversions between the frames and time.
+	fps := probe.Fps
+	if probe.VariableFrameRate {
+		fps = probe.AvgFps
+	}
+
+	// NOTE: The attached pictures are skipped if the stream index is not specified, therefore the probed stream is used explicitly
+	if streamIndex < 0 {
+		streamIndex = probe.StreamIndex
+	}
+
 	return &video{
 		FilePath:       path,
+		InputArgs:      nil,
+		StreamIndex:    streamIndex,
 		Dim:            utils.Vec2i{X: probe.Width, Y: probe.Height},
 		BboxAnchor:     utils.Vec2i{X: 0, Y: 0},
 		BboxDim:        utils.Vec2i{X: probe.Width, Y: probe.Height},
 		Scale:          1,
 		Duration:       probe.Duration,
-		Fps:            probe.Fps,
+		Fps:            fps,
+		VariableFps:    probe.VariableFrameRate,
 		FramesCount:    probe.Frames,
+		Timestamps:     nil,
+		Created:        probe.CreationTime,
 		FrameBuffer:    nil,
 		Process:        nil,
 		Pipe:           nil,
 		ScaleAlgorithm: options.Default,
+		Orientation:    probe.Orientation,
 		TargetFrames:   nil,
+		FirstFrame:     0,
+		LastFrame:      -1,
 	}, nil
 }